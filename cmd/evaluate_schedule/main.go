@@ -0,0 +1,105 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Command-line tool for evaluating the quality of match schedules, either one already saved in an event database or
+// one built on the fly from a schedule template or the schedule generator.
+//
+// Usage (from the root of the repository):
+//   go run cmd/evaluate_schedule/main.go -db event.db -type qualification
+//   go run cmd/evaluate_schedule/main.go -teams 36 -matches 10 -source template
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/tournament"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"time"
+)
+
+func main() {
+	dbPath := flag.String("db", "", "Path to an event database containing the schedule to evaluate")
+	matchType := flag.String("type", "qualification", "Type of the saved matches to evaluate")
+	numTeams := flag.Int("teams", 0, "Number of teams to build a schedule for, if not reading from a database")
+	matchesPerTeam := flag.Int("matches", 0, "Number of matches per team to build a schedule for")
+	source := flag.String("source", "generated", "Where to build the schedule from: 'generated' or 'template'")
+	flag.Parse()
+	rand.Seed(time.Now().UnixNano())
+
+	var matches []model.Match
+	var err error
+	if *dbPath != "" {
+		matches, err = loadMatches(*dbPath, *matchType)
+	} else if *numTeams > 0 && *matchesPerTeam > 0 {
+		matches, err = buildMatches(*numTeams, *matchesPerTeam, *source)
+	} else {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalln("Error getting schedule:", err)
+	}
+	if len(matches) == 0 {
+		log.Fatalln("The schedule has no matches.")
+	}
+
+	printEvaluation(tournament.EvaluateSchedule(matches))
+}
+
+// Reads the saved schedule of the given match type from the database.
+func loadMatches(dbPath string, matchType string) ([]model.Match, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, err
+	}
+	database, err := model.OpenDatabase(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer database.Close()
+	return database.GetMatchesByType(matchType)
+}
+
+// Builds a schedule for placeholder teams numbered from 1 in a single block of matches spaced six minutes apart.
+func buildMatches(numTeams int, matchesPerTeam int, source string) ([]model.Match, error) {
+	teams := make([]model.Team, numTeams)
+	for i := range teams {
+		teams[i].Id = i + 1
+	}
+	numMatches := int(math.Ceil(float64(numTeams*matchesPerTeam) / tournament.TeamsPerMatch))
+	scheduleBlocks := []model.ScheduleBlock{{0, "", "", time.Unix(0, 0).UTC(), numMatches, 360}}
+	switch source {
+	case "generated":
+		return tournament.BuildGeneratedSchedule(teams, scheduleBlocks, "qualification")
+	case "template":
+		return tournament.BuildRandomSchedule(teams, scheduleBlocks, "qualification")
+	}
+	return nil, fmt.Errorf("Invalid schedule source '%s'", source)
+}
+
+func printEvaluation(evaluation *tournament.ScheduleEvaluation) {
+	fmt.Printf("Teams:                    %d\n", evaluation.NumTeams)
+	fmt.Printf("Matches:                  %d\n", evaluation.NumMatches)
+	fmt.Printf("Matches per team:         %d-%d\n", evaluation.MinMatchesPerTeam, evaluation.MaxMatchesPerTeam)
+	fmt.Printf("Minimum turnaround:       %d matches (team %d)\n", evaluation.MinTurnaround,
+		evaluation.MinTurnaroundTeam)
+	if evaluation.MinTurnaroundTime > 0 {
+		fmt.Printf("Minimum turnaround time:  %v\n", evaluation.MinTurnaroundTime)
+	}
+	fmt.Printf("Back-to-back matches:     %d\n", evaluation.BackToBackMatches)
+	fmt.Printf("Most times partnered:     %d (%d repeated pairs)\n", evaluation.MaxPartnerCount,
+		evaluation.DuplicatePartners)
+	fmt.Printf("Most times opposed:       %d (%d repeated pairs)\n", evaluation.MaxOpponentCount,
+		evaluation.DuplicateOpponents)
+	fmt.Printf("Worst color imbalance:    %d\n", evaluation.MaxColorImbalance)
+	fmt.Printf("Most times in a station:  %d\n", evaluation.MaxStationCount)
+	fmt.Printf("Surrogate appearances:    %d (%d misplaced)\n", evaluation.NumSurrogates,
+		evaluation.MisplacedSurrogates)
+	for _, warning := range evaluation.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+}
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE sensor_filters (
+  id INTEGER PRIMARY KEY,
+  sensor VARCHAR(255),
+  minonms int,
+  glitchrejectms int,
+  maxcountrate int
+);
+CREATE UNIQUE INDEX sensor_filter_sensor ON sensor_filters(sensor);
+
+-- +goose Down
+DROP TABLE sensor_filters;
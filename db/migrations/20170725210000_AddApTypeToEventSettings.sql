@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE event_settings ADD COLUMN aptype VARCHAR(255) NOT NULL DEFAULT 'openwrt';
+
+-- +goose Down
+-- SQLite doesn't support dropping columns; the extra column is harmless.
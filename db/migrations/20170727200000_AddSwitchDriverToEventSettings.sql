@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE event_settings ADD COLUMN switchtype VARCHAR(255) NOT NULL DEFAULT 'cisco';
+ALTER TABLE event_settings ADD COLUMN switchtransport VARCHAR(255) NOT NULL DEFAULT 'telnet';
+ALTER TABLE event_settings ADD COLUMN switchusername VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE event_settings ADD COLUMN switchdryrun bool NOT NULL DEFAULT 0;
+
+-- +goose Down
+-- SQLite doesn't support dropping columns; the extra columns are harmless.
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE bandwidth_logs (
+  id INTEGER PRIMARY KEY,
+  matchid int,
+  teamid int,
+  alliancestation VARCHAR(2),
+  samplesjson text
+);
+CREATE UNIQUE INDEX bandwidth_log_match_station ON bandwidth_logs(matchid, alliancestation);
+
+-- +goose Down
+DROP TABLE bandwidth_logs;
@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE event_settings ADD COLUMN bandwidthalertmbps REAL NOT NULL DEFAULT 7;
+ALTER TABLE event_settings ADD COLUMN red1switchport int NOT NULL DEFAULT 6;
+ALTER TABLE event_settings ADD COLUMN red2switchport int NOT NULL DEFAULT 8;
+ALTER TABLE event_settings ADD COLUMN red3switchport int NOT NULL DEFAULT 10;
+ALTER TABLE event_settings ADD COLUMN blue1switchport int NOT NULL DEFAULT 12;
+ALTER TABLE event_settings ADD COLUMN blue2switchport int NOT NULL DEFAULT 14;
+ALTER TABLE event_settings ADD COLUMN blue3switchport int NOT NULL DEFAULT 16;
+ALTER TABLE teams ADD COLUMN bandwidthlimitmbps REAL NOT NULL DEFAULT 0;
+
+-- +goose Down
+-- SQLite doesn't support dropping columns; the extra columns are harmless.
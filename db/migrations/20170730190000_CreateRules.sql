@@ -0,0 +1,47 @@
+-- +goose Up
+CREATE TABLE rules (
+  id INTEGER PRIMARY KEY,
+  rulenumber VARCHAR(16),
+  istechnical bool,
+  description VARCHAR(1000),
+  triggerscard bool,
+  awardsrankingpoint bool
+);
+CREATE UNIQUE INDEX rule_number_technical ON rules(rulenumber, istechnical);
+
+-- Seed the catalogue with the rules from the 2017 game that carry point penalties.
+INSERT INTO rules (rulenumber, istechnical, description, triggerscard, awardsrankingpoint) VALUES
+  ('S08', 0, '', 0, 0),
+  ('C08', 0, '', 0, 0),
+  ('C11', 0, '', 0, 0),
+  ('G04', 0, '', 0, 0),
+  ('G05', 0, '', 0, 0),
+  ('G08', 0, '', 0, 0),
+  ('G09', 0, '', 0, 0),
+  ('G11', 0, '', 0, 0),
+  ('G11', 1, '', 0, 0),
+  ('G12', 0, '', 0, 0),
+  ('G13', 1, '', 0, 0),
+  ('G15', 0, '', 0, 0),
+  ('G17', 0, '', 0, 0),
+  ('G20', 0, '', 0, 0),
+  ('G22', 0, '', 0, 0),
+  ('G23', 0, '', 0, 0),
+  ('G26', 1, '', 0, 0),
+  ('G27', 0, '', 0, 0),
+  ('G27', 1, '', 0, 0),
+  ('A01', 0, '', 0, 0),
+  ('A02', 0, '', 0, 0),
+  ('A04', 0, '', 0, 0),
+  ('A04', 1, '', 0, 0),
+  ('A05', 1, '', 0, 0),
+  ('H06', 0, '', 0, 0),
+  ('H07', 0, '', 0, 0),
+  ('H08', 0, '', 0, 0),
+  ('H11', 0, '', 0, 0),
+  ('H11', 1, '', 0, 0),
+  ('H12', 1, '', 0, 0),
+  ('H13', 0, '', 0, 0);
+
+-- +goose Down
+DROP TABLE rules;
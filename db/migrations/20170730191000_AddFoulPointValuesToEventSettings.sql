@@ -0,0 +1,6 @@
+-- +goose Up
+ALTER TABLE event_settings ADD COLUMN foulpointvalue int NOT NULL DEFAULT 5;
+ALTER TABLE event_settings ADD COLUMN techfoulpointvalue int NOT NULL DEFAULT 25;
+
+-- +goose Down
+-- SQLite doesn't support dropping columns; the extra columns are harmless.
@@ -0,0 +1,6 @@
+-- +goose Up
+ALTER TABLE event_settings ADD COLUMN elimtiebreakers VARCHAR(255) NOT NULL DEFAULT 'foulPoints,autoPoints,rotorPoints,takeoffPoints,replay';
+ALTER TABLE matches ADD COLUMN tiebreaker VARCHAR(255) NOT NULL DEFAULT '';
+
+-- +goose Down
+-- SQLite doesn't support dropping columns; the extra columns are harmless.
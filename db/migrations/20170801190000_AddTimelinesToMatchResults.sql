@@ -0,0 +1,6 @@
+-- +goose Up
+ALTER TABLE match_results ADD COLUMN redtimelinejson text NOT NULL DEFAULT '[]';
+ALTER TABLE match_results ADD COLUMN bluetimelinejson text NOT NULL DEFAULT '[]';
+
+-- +goose Down
+-- SQLite doesn't support dropping columns; the extra columns are harmless.
@@ -0,0 +1,6 @@
+-- +goose Up
+ALTER TABLE match_results ADD COLUMN redcardreasonsjson text NOT NULL DEFAULT '{}';
+ALTER TABLE match_results ADD COLUMN bluecardreasonsjson text NOT NULL DEFAULT '{}';
+
+-- +goose Down
+-- SQLite doesn't support dropping columns; the extra columns are harmless.
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE schedule_blocks (
+  id INTEGER PRIMARY KEY,
+  matchtype VARCHAR(16),
+  name VARCHAR(255),
+  starttime DATETIME,
+  nummatches int,
+  matchspacingsec int
+);
+CREATE INDEX schedule_blocks_matchtype ON schedule_blocks(matchtype);
+
+-- +goose Down
+DROP TABLE schedule_blocks;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE matches ADD COLUMN endedat DATETIME NOT NULL DEFAULT '0001-01-01 00:00:00+00:00';
+ALTER TABLE matches ADD COLUMN scorescommittedat DATETIME NOT NULL DEFAULT '0001-01-01 00:00:00+00:00';
+ALTER TABLE matches ADD COLUMN fieldresetat DATETIME NOT NULL DEFAULT '0001-01-01 00:00:00+00:00';
+
+-- +goose Down
+-- SQLite doesn't support dropping columns; the extra columns are harmless.
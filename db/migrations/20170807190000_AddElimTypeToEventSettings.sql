@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE event_settings ADD COLUMN elimtype VARCHAR(255) NOT NULL DEFAULT 'single';
+
+-- +goose Down
+-- SQLite doesn't support dropping columns; the extra columns are harmless.
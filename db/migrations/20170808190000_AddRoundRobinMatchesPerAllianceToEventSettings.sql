@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE event_settings ADD COLUMN roundrobinmatchesperalliance int NOT NULL DEFAULT 6;
+
+-- +goose Down
+-- SQLite doesn't support dropping columns; the extra columns are harmless.
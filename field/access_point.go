@@ -1,7 +1,8 @@
 // Copyright 2017 Team 254. All Rights Reserved.
 // Author: pat@patfairbank.com (Patrick Fairbank)
 //
-// Methods for configuring a Linksys WRT1900ACS access point running OpenWRT for team SSIDs and VLANs.
+// Common interface and methods for configuring a wireless access point for team SSIDs and VLANs. Each supported type
+// of access point hardware has its own driver.
 
 package field
 
@@ -10,10 +11,10 @@ import (
 	"fmt"
 	"github.com/Team254/cheesy-arena/model"
 	"golang.org/x/crypto/ssh"
-	"os"
-	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"sync"
-	"text/template"
 	"time"
 )
 
@@ -28,9 +29,65 @@ const (
 	blue1Vlan = 40
 	blue2Vlan = 50
 	blue3Vlan = 60
+	adminVlan = 100
 )
 
-type AccessPoint struct {
+// Marks the start of the details of each network interface in the output of an access point status command.
+const statusSectionPrefix = "### "
+
+var surveyFrequencyRe = regexp.MustCompile("^frequency:.*\\[in use\\]")
+var surveyActiveTimeRe = regexp.MustCompile("^channel active time:\\s+(\\d+) ms")
+var surveyBusyTimeRe = regexp.MustCompile("^channel busy time:\\s+(\\d+) ms")
+
+var stationVlans = map[string]int{"R1": red1Vlan, "R2": red2Vlan, "R3": red3Vlan, "B1": blue1Vlan, "B2": blue2Vlan,
+	"B3": blue3Vlan}
+
+// Types of access point hardware that have drivers, with their descriptions.
+var AccessPointTypes = map[string]string{
+	"openwrt": "Linksys WRT1900ACS (OpenWRT)",
+	"hostapd": "Generic Linux AP (hostapd)",
+}
+
+type AccessPoint interface {
+	// Sets up wireless networks for the given set of teams.
+	ConfigureTeamWifi(red1, red2, red3, blue1, blue2, blue3 *model.Team) error
+
+	// Sets up the wireless network used by the field staff.
+	ConfigureAdminWifi() error
+
+	// Queries the access point for the networks it is currently broadcasting.
+	GetStatus() (*AccessPointStatus, error)
+
+	// Returns the team network configuration file that would be uploaded for the given set of teams, without applying
+	// it.
+	GenerateTeamWifiConfig(red1, red2, red3, blue1, blue2, blue3 *model.Team) (string, error)
+
+	// Reads back the team network configuration file currently on the access point.
+	GetTeamWifiConfig() (string, error)
+}
+
+type AccessPointStatus struct {
+	Networks []AccessPointNetwork
+}
+
+type AccessPointNetwork struct {
+	Interface          string
+	Ssid               string
+	Channel            int
+	ChannelUtilization float64 // Percentage of time that the channel was busy
+	Clients            []AccessPointClient
+}
+
+// A wireless client (typically a robot radio) that is associated with one of the access point's networks.
+type AccessPointClient struct {
+	MacAddress string
+	SignalDbm  int
+	TxRateMbps float64
+	RxRateMbps float64
+}
+
+// Fields and methods common to all the access point drivers, which are configured over SSH.
+type sshAccessPoint struct {
 	address      string
 	port         int
 	username     string
@@ -41,36 +98,31 @@ type AccessPoint struct {
 	mutex        sync.Mutex
 }
 
-func NewAccessPoint(address, username, password string, teamChannel, adminChannel int, adminWpaKey string) *AccessPoint {
-	return &AccessPoint{address: address, port: accessPointSshPort, username: username, password: password,
-		teamChannel: teamChannel, adminChannel: adminChannel, adminWpaKey: adminWpaKey}
-}
-
-// Sets up wireless networks for the given set of teams.
-func (ap *AccessPoint) ConfigureTeamWifi(red1, red2, red3, blue1, blue2, blue3 *model.Team) error {
-	// Make sure multiple configurations aren't being set at the same time.
-	ap.mutex.Lock()
-	defer ap.mutex.Unlock()
-
-	config, err := ap.generateAccessPointConfig(red1, red2, red3, blue1, blue2, blue3)
-	if err != nil {
-		return err
-	}
-	command := fmt.Sprintf("cat <<ENDCONFIG > /etc/config/wireless && wifi radio0\n%sENDCONFIG\n", config)
-	return ap.runCommand(command)
-}
-
-func (ap *AccessPoint) ConfigureAdminWifi() error {
-	config, err := ap.generateAccessPointConfig(nil, nil, nil, nil, nil, nil)
-	if err != nil {
-		return err
+// Creates a driver for the given type of access point, defaulting to OpenWRT if the type is unknown.
+func NewAccessPoint(apType, address, username, password string, teamChannel, adminChannel int,
+	adminWpaKey string) AccessPoint {
+	var ap AccessPoint
+	var sshAp *sshAccessPoint
+	switch apType {
+	case "hostapd":
+		hostapdAp := new(HostapdAccessPoint)
+		ap, sshAp = hostapdAp, &hostapdAp.sshAccessPoint
+	default:
+		openWrtAp := new(OpenWrtAccessPoint)
+		ap, sshAp = openWrtAp, &openWrtAp.sshAccessPoint
 	}
-	command := fmt.Sprintf("cat <<ENDCONFIG > /etc/config/wireless && wifi radio1\n%sENDCONFIG\n", config)
-	return ap.runCommand(command)
+	sshAp.address = address
+	sshAp.port = accessPointSshPort
+	sshAp.username = username
+	sshAp.password = password
+	sshAp.teamChannel = teamChannel
+	sshAp.adminChannel = adminChannel
+	sshAp.adminWpaKey = adminWpaKey
+	return ap
 }
 
-// Logs into the access point via SSH and runs the given shell command.
-func (ap *AccessPoint) runCommand(command string) error {
+// Logs into the access point via SSH, runs the given shell command and returns its output.
+func (ap *sshAccessPoint) runCommand(command string) (string, error) {
 	// Open an SSH connection to the AP.
 	config := &ssh.ClientConfig{User: ap.username,
 		Auth:            []ssh.AuthMethod{ssh.Password(ap.password)},
@@ -78,15 +130,16 @@ func (ap *AccessPoint) runCommand(command string) error {
 		Timeout:         connectTimeoutSec * time.Second}
 	conn, err := ssh.Dial("tcp", fmt.Sprintf("%s:%d", ap.address, ap.port), config)
 	if err != nil {
-		return err
+		return "", err
 	}
 	session, err := conn.NewSession()
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer session.Close()
 	defer conn.Close()
-	session.Stdout = os.Stdout
+	var output bytes.Buffer
+	session.Stdout = &output
 
 	// Run the command with a timeout. An error will be returned if the exit status is non-zero.
 	commandChan := make(chan error, 1)
@@ -95,53 +148,35 @@ func (ap *AccessPoint) runCommand(command string) error {
 	}()
 	select {
 	case err = <-commandChan:
-		return err
+		return output.String(), err
 	case <-time.After(commandTimeoutSec * time.Second):
-		return fmt.Errorf("WiFi SSH command timed out after %d seconds", commandTimeoutSec)
+		return "", fmt.Errorf("WiFi SSH command timed out after %d seconds", commandTimeoutSec)
 	}
 }
 
-func (ap *AccessPoint) generateAccessPointConfig(red1, red2, red3, blue1, blue2, blue3 *model.Team) (string, error) {
-	// Determine what new SSIDs are needed.
+// Returns the set of team networks to configure, keyed by VLAN.
+func getTeamNetworks(red1, red2, red3, blue1, blue2, blue3 *model.Team) (map[int]*model.Team, error) {
 	networks := make(map[int]*model.Team)
 	var err error
 	if err = addTeamNetwork(networks, red1, red1Vlan); err != nil {
-		return "", err
+		return nil, err
 	}
 	if err = addTeamNetwork(networks, red2, red2Vlan); err != nil {
-		return "", err
+		return nil, err
 	}
 	if err = addTeamNetwork(networks, red3, red3Vlan); err != nil {
-		return "", err
+		return nil, err
 	}
 	if err = addTeamNetwork(networks, blue1, blue1Vlan); err != nil {
-		return "", err
+		return nil, err
 	}
 	if err = addTeamNetwork(networks, blue2, blue2Vlan); err != nil {
-		return "", err
+		return nil, err
 	}
 	if err = addTeamNetwork(networks, blue3, blue3Vlan); err != nil {
-		return "", err
-	}
-
-	// Generate the config file to be uploaded to the AP.
-	template, err := template.ParseFiles(filepath.Join(model.BaseDir, "templates/access_point.cfg"))
-	if err != nil {
-		return "", err
-	}
-	data := struct {
-		Networks     map[int]*model.Team
-		TeamChannel  int
-		AdminChannel int
-		AdminWpaKey  string
-	}{networks, ap.teamChannel, ap.adminChannel, ap.adminWpaKey}
-	var configFile bytes.Buffer
-	err = template.Execute(&configFile, data)
-	if err != nil {
-		return "", err
+		return nil, err
 	}
-
-	return configFile.String(), nil
+	return networks, nil
 }
 
 // Verifies the validity of the given team's WPA key and adds a network for it to the list to be configured.
@@ -149,9 +184,46 @@ func addTeamNetwork(networks map[int]*model.Team, team *model.Team, vlan int) er
 	if team == nil {
 		return nil
 	}
-	if len(team.WpaKey) < 8 || len(team.WpaKey) > 63 {
+	if model.ValidateWpaKey(team.WpaKey) != nil {
 		return fmt.Errorf("Invalid WPA key '%s' configured for team %d.", team.WpaKey, team.Id)
 	}
 	networks[vlan] = team
 	return nil
 }
+
+// Splits the output of a status command into the part before the first interface section and a map of the sections
+// keyed by interface name.
+func splitStatusSections(output string) (string, map[string]string) {
+	sections := make(map[string]string)
+	parts := strings.Split("\n"+output, "\n"+statusSectionPrefix)
+	for _, part := range parts[1:] {
+		lines := strings.SplitN(part, "\n", 2)
+		sections[strings.TrimSpace(lines[0])] = ""
+		if len(lines) == 2 {
+			sections[strings.TrimSpace(lines[0])] = lines[1]
+		}
+	}
+	return strings.TrimPrefix(parts[0], "\n"), sections
+}
+
+// Parses the output of "iw dev <interface> survey dump" into the percentage of time that the channel in use was busy.
+func parseSurveyUtilization(output string) float64 {
+	inUse := false
+	activeTime, busyTime := 0, 0
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "frequency:") {
+			inUse = surveyFrequencyRe.MatchString(line)
+		} else if !inUse {
+			continue
+		} else if match := surveyActiveTimeRe.FindStringSubmatch(line); match != nil {
+			activeTime, _ = strconv.Atoi(match[1])
+		} else if match := surveyBusyTimeRe.FindStringSubmatch(line); match != nil {
+			busyTime, _ = strconv.Atoi(match[1])
+		}
+	}
+	if activeTime == 0 {
+		return 0
+	}
+	return float64(busyTime) * 100 / float64(activeTime)
+}
@@ -0,0 +1,197 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Access point driver for a generic Linux machine running one hostapd instance per radio, with the team and admin
+// networks bridged onto their VLANs as br-vlan<VLAN>.
+
+package field
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	hostapdTeamInterface  = "wlan0"
+	hostapdAdminInterface = "wlan1"
+)
+
+type HostapdAccessPoint struct {
+	sshAccessPoint
+}
+
+type hostapdNetwork struct {
+	Vlan int
+	Team *model.Team
+}
+
+var hostapdBssRe = regexp.MustCompile("^bss\\[\\d+\\]$")
+var hostapdSsidRe = regexp.MustCompile("^ssid\\[\\d+\\]$")
+var hostapdStationRe = regexp.MustCompile("^[0-9a-fA-F:]{17}$")
+
+func (ap *HostapdAccessPoint) ConfigureTeamWifi(red1, red2, red3, blue1, blue2, blue3 *model.Team) error {
+	// Make sure multiple configurations aren't being set at the same time.
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+
+	config, err := ap.generateTeamConfig(red1, red2, red3, blue1, blue2, blue3)
+	if err != nil {
+		return err
+	}
+	var command string
+	if config == "" {
+		// hostapd can't run without at least one network, so just stop it.
+		command = "systemctl stop hostapd@team"
+	} else {
+		command = fmt.Sprintf("cat <<ENDCONFIG > /etc/hostapd/team.conf && systemctl restart hostapd@team\n"+
+			"%sENDCONFIG\n", config)
+	}
+	_, err = ap.runCommand(command)
+	return err
+}
+
+func (ap *HostapdAccessPoint) ConfigureAdminWifi() error {
+	config, err := ap.generateConfig("templates/hostapd_admin.conf", nil)
+	if err != nil {
+		return err
+	}
+	command := fmt.Sprintf("cat <<ENDCONFIG > /etc/hostapd/admin.conf && systemctl restart hostapd@admin\n"+
+		"%sENDCONFIG\n", config)
+	_, err = ap.runCommand(command)
+	return err
+}
+
+func (ap *HostapdAccessPoint) GetStatus() (*AccessPointStatus, error) {
+	// Also list the associated clients and channel survey of each team network.
+	output, err := ap.runCommand(fmt.Sprintf("hostapd_cli -i %s status; hostapd_cli -i %s status; "+
+		"for iface in $(hostapd_cli -i %s status | sed -n 's/^bss\\[[0-9]*\\]=//p'); do echo \"%s$iface\"; "+
+		"hostapd_cli -i $iface all_sta; iw dev $iface survey dump; done", hostapdTeamInterface,
+		hostapdAdminInterface, hostapdTeamInterface, statusSectionPrefix))
+	if err != nil {
+		return nil, err
+	}
+	statusOutput, sections := splitStatusSections(output)
+	status := parseHostapdStatus(statusOutput)
+	for i, network := range status.Networks {
+		section := sections[network.Interface]
+		status.Networks[i].Clients = parseHostapdStations(section)
+		status.Networks[i].ChannelUtilization = parseSurveyUtilization(section)
+	}
+	return status, nil
+}
+
+func (ap *HostapdAccessPoint) GenerateTeamWifiConfig(red1, red2, red3, blue1, blue2, blue3 *model.Team) (string,
+	error) {
+	return ap.generateTeamConfig(red1, red2, red3, blue1, blue2, blue3)
+}
+
+func (ap *HostapdAccessPoint) GetTeamWifiConfig() (string, error) {
+	// Ignore a missing file since it just means that no team networks have been configured yet.
+	return ap.runCommand("cat /etc/hostapd/team.conf 2>/dev/null || true")
+}
+
+// Generates the hostapd config for the team radio, or an empty string if there are no teams to configure.
+func (ap *HostapdAccessPoint) generateTeamConfig(red1, red2, red3, blue1, blue2, blue3 *model.Team) (string,
+	error) {
+	networkMap, err := getTeamNetworks(red1, red2, red3, blue1, blue2, blue3)
+	if err != nil {
+		return "", err
+	}
+	if len(networkMap) == 0 {
+		return "", nil
+	}
+
+	// Order the networks by VLAN so that the config is deterministic.
+	var networks []hostapdNetwork
+	for vlan, team := range networkMap {
+		networks = append(networks, hostapdNetwork{vlan, team})
+	}
+	sort.Slice(networks, func(i, j int) bool {
+		return networks[i].Vlan < networks[j].Vlan
+	})
+
+	return ap.generateConfig("templates/hostapd_team.conf", networks)
+}
+
+func (ap *HostapdAccessPoint) generateConfig(templatePath string, networks []hostapdNetwork) (string, error) {
+	template, err := template.ParseFiles(filepath.Join(model.BaseDir, templatePath))
+	if err != nil {
+		return "", err
+	}
+	data := struct {
+		Networks       []hostapdNetwork
+		TeamInterface  string
+		TeamChannel    int
+		AdminInterface string
+		AdminChannel   int
+		AdminVlan      int
+		AdminWpaKey    string
+	}{networks, hostapdTeamInterface, ap.teamChannel, hostapdAdminInterface, ap.adminChannel, adminVlan,
+		ap.adminWpaKey}
+	var configFile bytes.Buffer
+	err = template.Execute(&configFile, data)
+	if err != nil {
+		return "", err
+	}
+
+	return configFile.String(), nil
+}
+
+// Parses the concatenated output of one or more "hostapd_cli status" commands into the list of active networks.
+func parseHostapdStatus(output string) *AccessPointStatus {
+	status := new(AccessPointStatus)
+	channel := 0
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), "=", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		key, value := fields[0], fields[1]
+		if key == "channel" {
+			channel, _ = strconv.Atoi(value)
+		} else if hostapdBssRe.MatchString(key) {
+			status.Networks = append(status.Networks, AccessPointNetwork{Interface: value, Channel: channel})
+		} else if hostapdSsidRe.MatchString(key) && len(status.Networks) > 0 {
+			status.Networks[len(status.Networks)-1].Ssid = value
+		}
+	}
+	return status
+}
+
+// Parses the output of the "hostapd_cli all_sta" command into the list of associated clients.
+func parseHostapdStations(output string) []AccessPointClient {
+	var clients []AccessPointClient
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if hostapdStationRe.MatchString(line) {
+			clients = append(clients, AccessPointClient{MacAddress: strings.ToUpper(line)})
+			continue
+		}
+		fields := strings.SplitN(line, "=", 2)
+		if len(fields) != 2 || len(clients) == 0 {
+			continue
+		}
+		client := &clients[len(clients)-1]
+		switch fields[0] {
+		case "signal":
+			client.SignalDbm, _ = strconv.Atoi(fields[1])
+		case "rx_rate_info", "tx_rate_info":
+			// Rates are given in units of 100 kbit/s, optionally followed by the modulation details.
+			var rate int
+			fmt.Sscanf(fields[1], "%d", &rate)
+			if fields[0] == "rx_rate_info" {
+				client.RxRateMbps = float64(rate) / 10
+			} else {
+				client.TxRateMbps = float64(rate) / 10
+			}
+		}
+	}
+	return clients
+}
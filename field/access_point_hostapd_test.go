@@ -0,0 +1,107 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+func TestConfigureHostapdAccessPoint(t *testing.T) {
+	model.BaseDir = ".."
+
+	bssRe := regexp.MustCompile("(?m)^bss=(\\w+)$")
+	ssidRe := regexp.MustCompile("(?m)^ssid=(.+)$")
+	wpaKeyRe := regexp.MustCompile("(?m)^wpa_passphrase=(.+)$")
+	bridgeRe := regexp.MustCompile("(?m)^bridge=br-vlan(\\d+)$")
+	ap := HostapdAccessPoint{sshAccessPoint{teamChannel: 1234, adminChannel: 4321, adminWpaKey: "blorpy"}}
+
+	// Should not generate a team config if there are no teams.
+	config, err := ap.generateTeamConfig(nil, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "", config)
+
+	// Should configure one BSS per team, with the first on the base interface.
+	config, err = ap.generateTeamConfig(&model.Team{Id: 254, WpaKey: "aaaaaaaa"}, nil, nil, nil,
+		&model.Team{Id: 1678, WpaKey: "cccccccc"}, &model.Team{Id: 1114, WpaKey: "bbbbbbbb"})
+	assert.Nil(t, err)
+	assert.Contains(t, config, "interface=wlan0\n")
+	assert.Contains(t, config, "channel=1234\n")
+	assert.Equal(t, [][]string{{"bss=wlan0_1", "wlan0_1"}, {"bss=wlan0_2", "wlan0_2"}},
+		bssRe.FindAllStringSubmatch(config, -1))
+	ssids := ssidRe.FindAllStringSubmatch(config, -1)
+	wpaKeys := wpaKeyRe.FindAllStringSubmatch(config, -1)
+	bridges := bridgeRe.FindAllStringSubmatch(config, -1)
+	if assert.Equal(t, 3, len(ssids)) && assert.Equal(t, 3, len(wpaKeys)) && assert.Equal(t, 3, len(bridges)) {
+		assert.Equal(t, "254", ssids[0][1])
+		assert.Equal(t, "aaaaaaaa", wpaKeys[0][1])
+		assert.Equal(t, "10", bridges[0][1])
+		assert.Equal(t, "1678", ssids[1][1])
+		assert.Equal(t, "cccccccc", wpaKeys[1][1])
+		assert.Equal(t, "50", bridges[1][1])
+		assert.Equal(t, "1114", ssids[2][1])
+		assert.Equal(t, "bbbbbbbb", wpaKeys[2][1])
+		assert.Equal(t, "60", bridges[2][1])
+	}
+
+	// Should reject a missing WPA key.
+	_, err = ap.generateTeamConfig(&model.Team{Id: 254}, nil, nil, nil, nil, nil)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Invalid WPA key")
+	}
+
+	// Should configure the admin network on its own radio.
+	config, err = ap.generateConfig("templates/hostapd_admin.conf", nil)
+	assert.Nil(t, err)
+	assert.Contains(t, config, "interface=wlan1\n")
+	assert.Contains(t, config, "channel=4321\n")
+	assert.Contains(t, config, "bridge=br-vlan100\n")
+	assert.Contains(t, config, "ssid=Cheesy Arena\n")
+	assert.Contains(t, config, "wpa_passphrase=blorpy\n")
+}
+
+func TestParseHostapdStatus(t *testing.T) {
+	output := `state=ENABLED
+phy=phy0
+freq=5785
+channel=157
+bss[0]=wlan0
+bssid[0]=60:38:e0:12:6b:16
+ssid[0]=254
+num_sta[0]=1
+bss[1]=wlan0_1
+bssid[1]=62:38:e0:12:6b:18
+ssid[1]=1114
+num_sta[1]=0
+state=ENABLED
+phy=phy1
+channel=11
+bss[0]=wlan1
+ssid[0]=Cheesy Arena
+`
+	status := parseHostapdStatus(output)
+	assert.Equal(t, []AccessPointNetwork{{"wlan0", "254", 157, 0, nil}, {"wlan0_1", "1114", 157, 0, nil},
+		{"wlan1", "Cheesy Arena", 11, 0, nil}}, status.Networks)
+}
+
+func TestParseHostapdStations(t *testing.T) {
+	output := `00:80:2f:17:e7:a1
+flags=[AUTH][ASSOC][AUTHORIZED][WMM][HT]
+aid=1
+signal=-48
+rx_rate_info=540
+tx_rate_info=722 mcs 7 shortGI
+connected_time=35
+00:80:2f:17:e7:b2
+flags=[AUTH][ASSOC]
+signal=-80
+rx_rate_info=60
+tx_rate_info=
+`
+	assert.Equal(t, []AccessPointClient{{"00:80:2F:17:E7:A1", -48, 72.2, 54}, {"00:80:2F:17:E7:B2", -80, 0, 6}},
+		parseHostapdStations(output))
+	assert.Empty(t, parseHostapdStations("FAIL\n"))
+}
@@ -0,0 +1,83 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for polling the access point for the robot radios associated with each team network.
+
+package field
+
+import (
+	"log"
+	"strconv"
+	"time"
+)
+
+const accessPointMonitorIntervalMs = 2000
+
+// What the access point reports about the network of the team in an alliance station, which distinguishes a robot
+// radio that never joined from one that is connected but not talking to the driver station.
+type WirelessStatus struct {
+	SsidActive         bool
+	Associated         bool
+	MacAddress         string
+	SignalDbm          int
+	TxRateMbps         float64
+	RxRateMbps         float64
+	ChannelUtilization float64
+}
+
+// Loops indefinitely to query the access point for the status of the team networks.
+func (arena *Arena) monitorAccessPoint() {
+	for {
+		if err := arena.pollAccessPoint(); err != nil {
+			log.Printf("Access point monitoring error: %v", err)
+		}
+		time.Sleep(time.Millisecond * accessPointMonitorIntervalMs)
+	}
+}
+
+// Queries the access point once and updates the wireless status of each alliance station.
+func (arena *Arena) pollAccessPoint() error {
+	if !arena.EventSettings.NetworkSecurityEnabled {
+		// The team networks aren't being managed, so there is nothing to report.
+		arena.updateWirelessStatuses(nil)
+		return nil
+	}
+	status, err := arena.accessPoint.GetStatus()
+	if err != nil {
+		arena.updateWirelessStatuses(nil)
+		return err
+	}
+	arena.updateWirelessStatuses(status)
+	return nil
+}
+
+// Sets the wireless status of each alliance station from the given access point status, or clears it if nil. Runs on
+// the monitor goroutine, so it holds the same lock as the network setup while touching the alliance stations.
+func (arena *Arena) updateWirelessStatuses(status *AccessPointStatus) {
+	arena.networkStatusMutex.Lock()
+	defer arena.networkStatusMutex.Unlock()
+	for _, allianceStation := range arena.AllianceStations {
+		var wireless WirelessStatus
+		if status != nil && allianceStation.Team != nil {
+			for _, network := range status.Networks {
+				if network.Ssid != strconv.Itoa(allianceStation.Team.Id) {
+					continue
+				}
+				wireless.SsidActive = true
+				wireless.ChannelUtilization = network.ChannelUtilization
+
+				// Only the robot radio should be associated, but if there are others report the strongest one.
+				for _, client := range network.Clients {
+					if !wireless.Associated || client.SignalDbm > wireless.SignalDbm {
+						wireless.Associated = true
+						wireless.MacAddress = client.MacAddress
+						wireless.SignalDbm = client.SignalDbm
+						wireless.TxRateMbps = client.TxRateMbps
+						wireless.RxRateMbps = client.RxRateMbps
+					}
+				}
+			}
+		}
+		allianceStation.Wireless = wireless
+	}
+}
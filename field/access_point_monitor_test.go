@@ -0,0 +1,42 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPollAccessPoint(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	arena.Database.CreateTeam(&model.Team{Id: 1114})
+	arena.Database.CreateTeam(&model.Team{Id: 2056})
+	arena.assignTeam(254, "R1")
+	arena.assignTeam(1114, "B2")
+	arena.assignTeam(2056, "B3")
+	arena.accessPoint = &fakeAccessPoint{status: &AccessPointStatus{Networks: []AccessPointNetwork{
+		{Ssid: "Cheesy Arena", Clients: []AccessPointClient{{MacAddress: "00:11:22:33:44:55", SignalDbm: -30}}},
+		{Ssid: "254", ChannelUtilization: 42, Clients: []AccessPointClient{{"00:80:2F:17:E7:A1", -71, 13, 6},
+			{"00:80:2F:17:E7:B2", -52, 65, 54}}},
+		{Ssid: "1114", ChannelUtilization: 42},
+	}}}
+
+	// Nothing should be reported unless the team networks are being managed.
+	assert.Nil(t, arena.pollAccessPoint())
+	assert.Equal(t, WirelessStatus{}, arena.AllianceStations["R1"].Wireless)
+
+	arena.EventSettings.NetworkSecurityEnabled = true
+	assert.Nil(t, arena.pollAccessPoint())
+	assert.Equal(t, WirelessStatus{true, true, "00:80:2F:17:E7:B2", -52, 65, 54, 42},
+		arena.AllianceStations["R1"].Wireless)
+	assert.Equal(t, WirelessStatus{SsidActive: true, ChannelUtilization: 42}, arena.AllianceStations["B2"].Wireless)
+	assert.Equal(t, WirelessStatus{}, arena.AllianceStations["B3"].Wireless)
+	assert.Equal(t, WirelessStatus{}, arena.AllianceStations["R2"].Wireless)
+
+	arena.EventSettings.NetworkSecurityEnabled = false
+	assert.Nil(t, arena.pollAccessPoint())
+	assert.Equal(t, WirelessStatus{}, arena.AllianceStations["R1"].Wireless)
+}
@@ -0,0 +1,151 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Access point driver for a Linksys WRT1900ACS running OpenWRT.
+
+package field
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+type OpenWrtAccessPoint struct {
+	sshAccessPoint
+}
+
+// Lists the active networks, followed by the associated clients and channel survey of each one.
+const openWrtStatusCommand = "iwinfo; for iface in $(iwinfo | awk '/ESSID/ {print $1}'); do " +
+	"echo \"" + statusSectionPrefix + "$iface\"; iwinfo $iface assoclist; iw dev $iface survey dump; done"
+
+var openWrtInterfaceRe = regexp.MustCompile("(?m)^(\\S+)\\s+ESSID: \"(.*)\"$")
+var openWrtChannelRe = regexp.MustCompile("Channel: (\\d+)")
+var openWrtClientRe = regexp.MustCompile("^([0-9A-Fa-f:]{17})\\s+(-?\\d+) dBm")
+var openWrtRateRe = regexp.MustCompile("^(RX|TX): ([\\d.]+) MBit/s")
+
+func (ap *OpenWrtAccessPoint) ConfigureTeamWifi(red1, red2, red3, blue1, blue2, blue3 *model.Team) error {
+	// Make sure multiple configurations aren't being set at the same time.
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+
+	config, err := ap.generateAccessPointConfig(red1, red2, red3, blue1, blue2, blue3)
+	if err != nil {
+		return err
+	}
+	command := fmt.Sprintf("cat <<ENDCONFIG > /etc/config/wireless && wifi radio0\n%sENDCONFIG\n", config)
+	_, err = ap.runCommand(command)
+	return err
+}
+
+func (ap *OpenWrtAccessPoint) ConfigureAdminWifi() error {
+	config, err := ap.generateAccessPointConfig(nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	command := fmt.Sprintf("cat <<ENDCONFIG > /etc/config/wireless && wifi radio1\n%sENDCONFIG\n", config)
+	_, err = ap.runCommand(command)
+	return err
+}
+
+func (ap *OpenWrtAccessPoint) GetStatus() (*AccessPointStatus, error) {
+	output, err := ap.runCommand(openWrtStatusCommand)
+	if err != nil {
+		return nil, err
+	}
+	return parseOpenWrtStatus(output), nil
+}
+
+func (ap *OpenWrtAccessPoint) GenerateTeamWifiConfig(red1, red2, red3, blue1, blue2, blue3 *model.Team) (string,
+	error) {
+	return ap.generateAccessPointConfig(red1, red2, red3, blue1, blue2, blue3)
+}
+
+func (ap *OpenWrtAccessPoint) GetTeamWifiConfig() (string, error) {
+	return ap.runCommand("cat /etc/config/wireless")
+}
+
+func (ap *OpenWrtAccessPoint) generateAccessPointConfig(red1, red2, red3, blue1, blue2, blue3 *model.Team) (string,
+	error) {
+	// Determine what new SSIDs are needed.
+	networks, err := getTeamNetworks(red1, red2, red3, blue1, blue2, blue3)
+	if err != nil {
+		return "", err
+	}
+
+	// Generate the config file to be uploaded to the AP.
+	template, err := template.ParseFiles(filepath.Join(model.BaseDir, "templates/access_point.cfg"))
+	if err != nil {
+		return "", err
+	}
+	data := struct {
+		Networks     map[int]*model.Team
+		TeamChannel  int
+		AdminChannel int
+		AdminWpaKey  string
+	}{networks, ap.teamChannel, ap.adminChannel, ap.adminWpaKey}
+	var configFile bytes.Buffer
+	err = template.Execute(&configFile, data)
+	if err != nil {
+		return "", err
+	}
+
+	return configFile.String(), nil
+}
+
+// Parses the output of the OpenWRT iwinfo command into the list of active networks.
+func parseIwinfoStatus(output string) *AccessPointStatus {
+	status := new(AccessPointStatus)
+	matches := openWrtInterfaceRe.FindAllStringSubmatchIndex(output, -1)
+	for i, match := range matches {
+		network := AccessPointNetwork{Interface: output[match[2]:match[3]], Ssid: output[match[4]:match[5]]}
+
+		// The details of each interface run until the start of the next one.
+		end := len(output)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		if channelMatch := openWrtChannelRe.FindStringSubmatch(output[match[1]:end]); channelMatch != nil {
+			network.Channel, _ = strconv.Atoi(channelMatch[1])
+		}
+		status.Networks = append(status.Networks, network)
+	}
+	return status
+}
+
+// Parses the output of the OpenWRT status command into the list of active networks and their clients.
+func parseOpenWrtStatus(output string) *AccessPointStatus {
+	iwinfoOutput, sections := splitStatusSections(output)
+	status := parseIwinfoStatus(iwinfoOutput)
+	for i, network := range status.Networks {
+		section := sections[network.Interface]
+		status.Networks[i].Clients = parseIwinfoAssoclist(section)
+		status.Networks[i].ChannelUtilization = parseSurveyUtilization(section)
+	}
+	return status
+}
+
+// Parses the output of the OpenWRT "iwinfo <interface> assoclist" command into the list of associated clients.
+func parseIwinfoAssoclist(output string) []AccessPointClient {
+	var clients []AccessPointClient
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if match := openWrtClientRe.FindStringSubmatch(line); match != nil {
+			signal, _ := strconv.Atoi(match[2])
+			clients = append(clients, AccessPointClient{MacAddress: strings.ToUpper(match[1]), SignalDbm: signal})
+		} else if match := openWrtRateRe.FindStringSubmatch(line); match != nil && len(clients) > 0 {
+			rate, _ := strconv.ParseFloat(match[2], 64)
+			if match[1] == "RX" {
+				clients[len(clients)-1].RxRateMbps = rate
+			} else {
+				clients[len(clients)-1].TxRateMbps = rate
+			}
+		}
+	}
+	return clients
+}
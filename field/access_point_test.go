@@ -11,7 +11,7 @@ import (
 	"testing"
 )
 
-func TestConfigureAccessPoint(t *testing.T) {
+func TestConfigureOpenWrtAccessPoint(t *testing.T) {
 	model.BaseDir = ".."
 
 	radioRe := regexp.MustCompile("option device 'radio0'")
@@ -19,7 +19,7 @@ func TestConfigureAccessPoint(t *testing.T) {
 	wpaKeyRe := regexp.MustCompile("option key '([-\\w ]+)'")
 	vlanRe := regexp.MustCompile("option network 'vlan(\\d+)'")
 	channelRe := regexp.MustCompile("option channel '(\\d+)'")
-	ap := AccessPoint{teamChannel: 1234, adminChannel: 4321, adminWpaKey: "blorpy"}
+	ap := OpenWrtAccessPoint{sshAccessPoint{teamChannel: 1234, adminChannel: 4321, adminWpaKey: "blorpy"}}
 
 	// Should not configure any team SSIDs if there are no teams.
 	config, _ := ap.generateAccessPointConfig(nil, nil, nil, nil, nil, nil)
@@ -88,3 +88,77 @@ func TestConfigureAccessPoint(t *testing.T) {
 		assert.Contains(t, err.Error(), "Invalid WPA key")
 	}
 }
+
+func TestParseIwinfoStatus(t *testing.T) {
+	output := `wlan0     ESSID: "Cheesy Arena"
+          Access Point: 62:38:E0:12:6B:17
+          Mode: Master  Channel: 11 (2.462 GHz)
+          Tx-Power: 20 dBm  Link Quality: unknown/70
+
+wlan1     ESSID: "254"
+          Access Point: 60:38:E0:12:6B:16
+          Mode: Master  Channel: 157 (5.785 GHz)
+
+wlan1-1   ESSID: "1114"
+          Access Point: 62:38:E0:12:6B:18
+          Mode: Master  Channel: 157 (5.785 GHz)
+`
+	status := parseIwinfoStatus(output)
+	assert.Equal(t, []AccessPointNetwork{{"wlan0", "Cheesy Arena", 11, 0, nil}, {"wlan1", "254", 157, 0, nil},
+		{"wlan1-1", "1114", 157, 0, nil}}, status.Networks)
+
+	assert.Empty(t, parseIwinfoStatus("").Networks)
+}
+
+func TestParseOpenWrtStatus(t *testing.T) {
+	output := `wlan1     ESSID: "254"
+          Access Point: 60:38:E0:12:6B:16
+          Mode: Master  Channel: 157 (5.785 GHz)
+
+wlan1-1   ESSID: "1114"
+          Access Point: 62:38:E0:12:6B:18
+          Mode: Master  Channel: 157 (5.785 GHz)
+
+### wlan1
+00:80:2f:17:e7:a1  -52 dBm / -95 dBm (SNR 43)  10 ms ago
+	RX: 54.0 MBit/s                                  1234 Pkts.
+	TX: 65.0 MBit/s, MCS 7, 20MHz                     567 Pkts.
+	expected throughput: unknown
+
+00:80:2F:17:E7:B2  -71 dBm / -95 dBm (SNR 24)  1200 ms ago
+	RX: 6.0 MBit/s                                     12 Pkts.
+	TX: 13.0 MBit/s, MCS 1, 20MHz                      5 Pkts.
+	expected throughput: unknown
+
+Survey data from wlan1
+	frequency:			5765 MHz
+	noise:				-95 dBm
+	channel active time:		2000 ms
+	channel busy time:		100 ms
+Survey data from wlan1
+	frequency:			5785 MHz [in use]
+	noise:				-92 dBm
+	channel active time:		1000 ms
+	channel busy time:		350 ms
+### wlan1-1
+No station connected
+`
+	status := parseOpenWrtStatus(output)
+	if assert.Equal(t, 2, len(status.Networks)) {
+		assert.Equal(t, []AccessPointClient{{"00:80:2F:17:E7:A1", -52, 65, 54}, {"00:80:2F:17:E7:B2", -71, 13, 6}},
+			status.Networks[0].Clients)
+		assert.Equal(t, 35.0, status.Networks[0].ChannelUtilization)
+		assert.Equal(t, "1114", status.Networks[1].Ssid)
+		assert.Empty(t, status.Networks[1].Clients)
+		assert.Equal(t, 0.0, status.Networks[1].ChannelUtilization)
+	}
+}
+
+func TestNewAccessPoint(t *testing.T) {
+	_, ok := NewAccessPoint("openwrt", "", "", "", 1, 2, "").(*OpenWrtAccessPoint)
+	assert.True(t, ok)
+	_, ok = NewAccessPoint("hostapd", "", "", "", 1, 2, "").(*HostapdAccessPoint)
+	assert.True(t, ok)
+	_, ok = NewAccessPoint("", "", "", "", 1, 2, "").(*OpenWrtAccessPoint)
+	assert.True(t, ok)
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/partner"
 	"log"
+	"strconv"
+	"sync"
 	"time"
 )
 
@@ -18,8 +20,11 @@ const (
 	arenaLoopPeriodMs     = 10
 	dsPacketPeriodMs      = 250
 	matchEndScoreDwellSec = 3
+	networkVerifyAttempts = 5
 )
 
+var networkVerifyInterval = 2 * time.Second // Mutable for testing
+
 // Progression of match states.
 const (
 	PreMatch      = 0
@@ -34,7 +39,7 @@ const (
 type Arena struct {
 	Database                       *model.Database
 	EventSettings                  *model.EventSettings
-	accessPoint                    *AccessPoint
+	accessPoint                    AccessPoint
 	networkSwitch                  *NetworkSwitch
 	Plc                            Plc
 	TbaClient                      *partner.TbaClient
@@ -49,6 +54,7 @@ type Arena struct {
 	BlueRealtimeScore              *RealtimeScore
 	lastDsPacketTime               time.Time
 	FieldReset                     bool
+	lastPlayedMatch                *model.Match
 	AudienceDisplayScreen          string
 	SavedMatch                     *model.Match
 	SavedMatchResult               *model.MatchResult
@@ -57,12 +63,18 @@ type Arena struct {
 	MuteMatchSounds                bool
 	FieldTestMode                  string
 	matchAborted                   bool
+	manualScoringAvailable         bool
+	networkSetupCount              int
+	networkStatusMutex             sync.Mutex
 	matchStateNotifier             *Notifier
 	MatchTimeNotifier              *Notifier
 	RobotStatusNotifier            *Notifier
 	MatchLoadTeamsNotifier         *Notifier
 	ScoringStatusNotifier          *Notifier
 	RealtimeScoreNotifier          *Notifier
+	ScoreOverridesNotifier         *Notifier
+	ScorerDisagreementNotifier     *Notifier
+	ManualScoringNotifier          *Notifier
 	ScorePostedNotifier            *Notifier
 	AudienceDisplayNotifier        *Notifier
 	PlaySoundNotifier              *Notifier
@@ -80,11 +92,49 @@ type ArenaStatus struct {
 	FieldEstop       bool
 }
 
+// Summary of the conditions that must be met before the next match can start, and the resulting state of the field
+// stack light and driver station ready lights.
+type FieldReadyStatus struct {
+	MatchState        int
+	StationsReady     map[string]bool
+	RedAllianceReady  bool
+	BlueAllianceReady bool
+	EstopsClear       bool
+	FieldReset        bool
+	ScoresCommitted   bool
+	CanStartMatch     bool
+	StackLight        StackLight
+	StationLights     map[string]bool
+}
+
+type StackLight struct {
+	Red    bool
+	Blue   bool
+	Orange bool
+	Green  bool
+}
+
+// Progression of the team network configuration for an alliance station.
+const (
+	NetworkPending    = "pending"
+	NetworkApplying   = "applying"
+	NetworkConfigured = "configured"
+	NetworkFailed     = "failed"
+)
+
+type NetworkStatus struct {
+	State string
+	Error string
+}
+
 type AllianceStation struct {
-	DsConn *DriverStationConnection
-	Estop  bool
-	Bypass bool
-	Team   *model.Team
+	DsConn         *DriverStationConnection
+	Estop          bool
+	Bypass         bool
+	Team           *model.Team
+	WifiStatus     NetworkStatus
+	EthernetStatus NetworkStatus
+	Wireless       WirelessStatus
 }
 
 // Creates the arena and sets it to its initial state.
@@ -115,6 +165,9 @@ func NewArena(dbPath string) (*Arena, error) {
 	arena.MatchLoadTeamsNotifier = NewNotifier()
 	arena.ScoringStatusNotifier = NewNotifier()
 	arena.RealtimeScoreNotifier = NewNotifier()
+	arena.ScoreOverridesNotifier = NewNotifier()
+	arena.ScorerDisagreementNotifier = NewNotifier()
+	arena.ManualScoringNotifier = NewNotifier()
 	arena.ScorePostedNotifier = NewNotifier()
 	arena.AudienceDisplayNotifier = NewNotifier()
 	arena.PlaySoundNotifier = NewNotifier()
@@ -148,10 +201,19 @@ func (arena *Arena) LoadSettings() error {
 	arena.EventSettings = settings
 
 	// Initialize the components that depend on settings.
-	arena.accessPoint = NewAccessPoint(settings.ApAddress, settings.ApUsername, settings.ApPassword,
+	arena.accessPoint = NewAccessPoint(settings.ApType, settings.ApAddress, settings.ApUsername, settings.ApPassword,
 		settings.ApTeamChannel, settings.ApAdminChannel, settings.ApAdminWpaKey)
-	arena.networkSwitch = NewNetworkSwitch(settings.SwitchAddress, settings.SwitchPassword)
+	arena.networkSwitch = NewNetworkSwitch(settings.SwitchType, settings.SwitchTransport, settings.SwitchAddress,
+		settings.SwitchUsername, settings.SwitchPassword, settings.SwitchDryRun)
 	arena.Plc.SetAddress(settings.PlcAddress)
+	sensorFilters, err := arena.Database.GetAllSensorFilters()
+	if err != nil {
+		return err
+	}
+	arena.Plc.SetSensorFilters(sensorFilters)
+	if err = arena.LoadRules(); err != nil {
+		return err
+	}
 	arena.TbaClient = partner.NewTbaClient(settings.TbaEventCode, settings.TbaSecretId, settings.TbaSecret)
 	arena.StemTvClient = partner.NewStemTvClient(settings.StemTvEventCode)
 
@@ -163,12 +225,33 @@ func (arena *Arena) LoadSettings() error {
 	return nil
 }
 
+// Loads or reloads the foul point values and the rule catalogue used in scoring.
+func (arena *Arena) LoadRules() error {
+	rules, err := arena.Database.GetAllRules()
+	if err != nil {
+		return err
+	}
+	rankingPointRules := make(map[game.Rule]bool)
+	for _, rule := range rules {
+		if rule.AwardsRankingPoint {
+			rankingPointRules[rule.GameRule()] = true
+		}
+	}
+	game.FoulPenalties.FoulPointValue = arena.EventSettings.FoulPointValue
+	game.FoulPenalties.TechFoulPointValue = arena.EventSettings.TechFoulPointValue
+	game.FoulPenalties.RankingPointRules = rankingPointRules
+	return nil
+}
+
 // Sets up the arena for the given match.
 func (arena *Arena) LoadMatch(match *model.Match) error {
 	if arena.MatchState != PreMatch {
 		return fmt.Errorf("Cannot load match while there is a match still in progress or with results pending.")
 	}
 
+	// Make sure the previous match's milestones are recorded before its realtime scores and field reset are cleared.
+	arena.recordMatchMilestones()
+
 	arena.CurrentMatch = match
 	err := arena.assignTeam(match.Red1, "R1")
 	if err != nil {
@@ -277,6 +360,9 @@ func (arena *Arena) StartMatch() error {
 	if err == nil {
 		// Save the match start time to the database for posterity.
 		arena.CurrentMatch.StartedAt = time.Now()
+		arena.CurrentMatch.EndedAt = time.Time{}
+		arena.CurrentMatch.ScoresCommittedAt = time.Time{}
+		arena.CurrentMatch.FieldResetAt = time.Time{}
 		if arena.CurrentMatch.Type != "test" {
 			arena.Database.SaveMatch(arena.CurrentMatch)
 		}
@@ -303,6 +389,7 @@ func (arena *Arena) AbortMatch() error {
 	}
 	arena.MatchState = PostMatch
 	arena.matchAborted = true
+	arena.saveBandwidthLogs()
 	arena.AudienceDisplayScreen = "blank"
 	arena.AudienceDisplayNotifier.Notify(nil)
 	if !arena.MuteMatchSounds {
@@ -407,6 +494,7 @@ func (arena *Arena) Update() {
 			auto = false
 			enabled = false
 			sendDsPacket = true
+			arena.saveBandwidthLogs()
 			go func() {
 				// Leave the scores on the screen briefly at the end of the match.
 				time.Sleep(time.Second * matchEndScoreDwellSec)
@@ -439,9 +527,23 @@ func (arena *Arena) Update() {
 		arena.RobotStatusNotifier.Notify(nil)
 	}
 
+	// Let the scoring displays know to offer manual scoring when the field sensors become unavailable or come back.
+	if manualScoringAvailable := arena.ManualScoringAvailable(); manualScoringAvailable != arena.manualScoringAvailable {
+		arena.manualScoringAvailable = manualScoringAvailable
+		arena.ManualScoringNotifier.Notify(nil)
+	}
+
 	// Handle field sensors/lights/motors.
 	arena.handlePlcInput()
 	arena.handlePlcOutput()
+	arena.updateScoringTimelines()
+	arena.recordMatchMilestones()
+}
+
+// Returns true if the field sensors can't be relied on to count the score, either because there is no PLC configured
+// or because it isn't responding.
+func (arena *Arena) ManualScoringAvailable() bool {
+	return arena.EventSettings.PlcAddress == "" || !arena.Plc.IsHealthy
 }
 
 // Loops indefinitely to track and update the arena components.
@@ -450,6 +552,7 @@ func (arena *Arena) Run() {
 	go arena.listenForDriverStations()
 	go arena.listenForDsUdpPackets()
 	go arena.monitorBandwidth()
+	go arena.monitorAccessPoint()
 	go arena.Plc.Run()
 
 	for {
@@ -471,8 +574,56 @@ func (arena *Arena) BlueScoreSummary() *game.ScoreSummary {
 }
 
 func (arena *Arena) GetStatus() *ArenaStatus {
-	return &ArenaStatus{arena.AllianceStations, arena.MatchState, arena.checkCanStartMatch() == nil,
-		arena.Plc.IsHealthy, arena.Plc.GetFieldEstop()}
+	// Copy the alliance stations since their network statuses are updated in the background.
+	allianceStations := make(map[string]*AllianceStation)
+	arena.networkStatusMutex.Lock()
+	for station, allianceStation := range arena.AllianceStations {
+		allianceStationCopy := *allianceStation
+		allianceStations[station] = &allianceStationCopy
+	}
+	arena.networkStatusMutex.Unlock()
+	return &ArenaStatus{allianceStations, arena.MatchState, arena.checkCanStartMatch() == nil, arena.Plc.IsHealthy,
+		arena.Plc.GetFieldEstop()}
+}
+
+// Returns the readiness of the field and the stack light state derived from it. Before a match, the red and blue lights
+// show alliances whose robots are not yet connected or bypassed and green shows that the match can be started. After a
+// match, orange shows that the scores have not yet been committed and green shows that the referee has signalled that
+// the field is safe to reset.
+func (arena *Arena) GetFieldReadyStatus() *FieldReadyStatus {
+	status := &FieldReadyStatus{MatchState: arena.MatchState, StationsReady: make(map[string]bool),
+		StationLights: make(map[string]bool)}
+	for station := range arena.AllianceStations {
+		status.StationsReady[station] = arena.checkAllianceStationsReady(station) == nil
+		status.StationLights[station] = arena.MatchState == PreMatch && status.StationsReady[station]
+	}
+	status.RedAllianceReady = arena.checkAllianceStationsReady("R1", "R2", "R3") == nil
+	status.BlueAllianceReady = arena.checkAllianceStationsReady("B1", "B2", "B3") == nil
+	redEstop := arena.AllianceStations["R1"].Estop || arena.AllianceStations["R2"].Estop ||
+		arena.AllianceStations["R3"].Estop
+	blueEstop := arena.AllianceStations["B1"].Estop || arena.AllianceStations["B2"].Estop ||
+		arena.AllianceStations["B3"].Estop
+	status.EstopsClear = !redEstop && !blueEstop && !arena.Plc.GetFieldEstop()
+	status.FieldReset = arena.FieldReset
+	status.ScoresCommitted = arena.RedRealtimeScore.FoulsCommitted && arena.BlueRealtimeScore.FoulsCommitted &&
+		arena.RedRealtimeScore.TeleopCommitted && arena.BlueRealtimeScore.TeleopCommitted
+	status.CanStartMatch = arena.checkCanStartMatch() == nil
+
+	switch arena.MatchState {
+	case PreMatch:
+		status.StackLight.Red = !status.RedAllianceReady
+		status.StackLight.Blue = !status.BlueAllianceReady
+		status.StackLight.Green = status.CanStartMatch
+	case PostMatch:
+		status.StackLight.Orange = !status.ScoresCommitted
+		status.StackLight.Green = status.FieldReset
+	default:
+		// Only show emergency stops while the match is in progress.
+		status.StackLight.Red = redEstop
+		status.StackLight.Blue = blueEstop
+	}
+
+	return status
 }
 
 // Loads a team into an alliance station, cleaning up the previous team there if there is one.
@@ -489,13 +640,13 @@ func (arena *Arena) assignTeam(teamId int, station string) error {
 	}
 	if dsConn != nil {
 		dsConn.close()
-		arena.AllianceStations[station].Team = nil
+		arena.setStationTeam(station, nil)
 		arena.AllianceStations[station].DsConn = nil
 	}
 
 	// Leave the station empty if the team number is zero.
 	if teamId == 0 {
-		arena.AllianceStations[station].Team = nil
+		arena.setStationTeam(station, nil)
 		return nil
 	}
 
@@ -508,46 +659,191 @@ func (arena *Arena) assignTeam(teamId int, station string) error {
 		team = &model.Team{Id: teamId}
 	}
 
+	arena.setStationTeam(station, team)
+	return nil
+}
+
+// Sets the team in the given alliance station while holding the lock that the access point monitor takes to read it.
+func (arena *Arena) setStationTeam(station string, team *model.Team) {
+	arena.networkStatusMutex.Lock()
+	defer arena.networkStatusMutex.Unlock()
 	arena.AllianceStations[station].Team = team
+}
+
+// Returns the most recent wireless status of the given alliance station, which is updated in the background by the
+// access point monitor.
+func (arena *Arena) stationWireless(station string) WirelessStatus {
+	arena.networkStatusMutex.Lock()
+	defer arena.networkStatusMutex.Unlock()
+	return arena.AllianceStations[station].Wireless
+}
+
+// Reconfigures the team networks for the current match again, such as after a previous attempt failed.
+func (arena *Arena) RetryNetworkSetup() error {
+	if arena.MatchState != PreMatch {
+		return fmt.Errorf("Cannot reconfigure the network while there is a match still in progress or with results " +
+			"pending.")
+	}
+	if !arena.EventSettings.NetworkSecurityEnabled {
+		return fmt.Errorf("Advanced network security is not enabled.")
+	}
+	arena.setupNetwork()
 	return nil
 }
 
-// Asynchronously reconfigures the networking hardware for the new set of teams.
+// Asynchronously reconfigures the networking hardware for the new set of teams, then reads back the configuration to
+// verify it and records the outcome for each alliance station.
 func (arena *Arena) setupNetwork() {
-	if arena.EventSettings.NetworkSecurityEnabled {
+	// Keep track of which attempt this is so that a stale one can't overwrite the status of a newer one.
+	arena.networkStatusMutex.Lock()
+	arena.networkSetupCount++
+	setupCount := arena.networkSetupCount
+	arena.networkStatusMutex.Unlock()
+	teams := make(map[string]*model.Team)
+	for station, allianceStation := range arena.AllianceStations {
+		teams[station] = allianceStation.Team
+	}
+
+	if !arena.EventSettings.NetworkSecurityEnabled {
+		// Clear out any status left over from when it was enabled.
+		arena.setNetworkStatus(setupCount, teams, true, "", nil)
+		arena.setNetworkStatus(setupCount, teams, false, "", nil)
+	} else {
+		arena.setNetworkStatus(setupCount, teams, true, NetworkPending, nil)
+		arena.setNetworkStatus(setupCount, teams, false, NetworkPending, nil)
+
 		go func() {
-			err := arena.accessPoint.ConfigureTeamWifi(arena.AllianceStations["R1"].Team,
-				arena.AllianceStations["R2"].Team, arena.AllianceStations["R3"].Team, arena.AllianceStations["B1"].Team,
-				arena.AllianceStations["B2"].Team, arena.AllianceStations["B3"].Team)
+			arena.setNetworkStatus(setupCount, teams, true, NetworkApplying, nil)
+			err := arena.accessPoint.ConfigureTeamWifi(teams["R1"], teams["R2"], teams["R3"], teams["B1"],
+				teams["B2"], teams["B3"])
 			if err != nil {
 				log.Printf("Failed to configure team WiFi: %s", err.Error())
+				arena.setNetworkStatus(setupCount, teams, true, NetworkFailed, err)
+				return
+			}
+
+			// The access point takes some time to bring up the new networks, so allow a few attempts.
+			var stationErrs map[string]error
+			for i := 0; i < networkVerifyAttempts; i++ {
+				time.Sleep(networkVerifyInterval)
+				if stationErrs = arena.verifyTeamWifi(teams); len(stationErrs) == 0 {
+					break
+				}
 			}
+			arena.setStationNetworkStatuses(setupCount, teams, true, stationErrs)
 		}()
 		go func() {
-			err := arena.networkSwitch.ConfigureTeamEthernet(arena.AllianceStations["R1"].Team,
-				arena.AllianceStations["R2"].Team, arena.AllianceStations["R3"].Team, arena.AllianceStations["B1"].Team,
-				arena.AllianceStations["B2"].Team, arena.AllianceStations["B3"].Team)
+			arena.setNetworkStatus(setupCount, teams, false, NetworkApplying, nil)
+			err := arena.networkSwitch.ConfigureTeamEthernet(teams["R1"], teams["R2"], teams["R3"], teams["B1"],
+				teams["B2"], teams["B3"])
 			if err != nil {
 				log.Printf("Failed to configure team Ethernet: %s", err.Error())
+				arena.setNetworkStatus(setupCount, teams, false, NetworkFailed, err)
+				return
 			}
+			if arena.networkSwitch.DryRun {
+				arena.setNetworkStatus(setupCount, teams, false, NetworkConfigured,
+					fmt.Errorf("Switch is in dry run mode; the configuration was not applied."))
+				return
+			}
+			arena.setStationNetworkStatuses(setupCount, teams, false, arena.verifyTeamEthernet(teams))
 		}()
 	}
 }
 
+// Returns an error for each station whose team's SSID isn't being broadcast by the access point.
+func (arena *Arena) verifyTeamWifi(teams map[string]*model.Team) map[string]error {
+	stationErrs := make(map[string]error)
+	status, err := arena.accessPoint.GetStatus()
+	for station, team := range teams {
+		if team == nil {
+			continue
+		}
+		if err != nil {
+			stationErrs[station] = err
+			continue
+		}
+		stationErrs[station] = fmt.Errorf("SSID %d is not active on the access point.", team.Id)
+		for _, network := range status.Networks {
+			if network.Ssid == strconv.Itoa(team.Id) {
+				delete(stationErrs, station)
+				break
+			}
+		}
+	}
+	return stationErrs
+}
+
+// Returns an error for each station whose team isn't configured on the correct VLAN on the switch.
+func (arena *Arena) verifyTeamEthernet(teams map[string]*model.Team) map[string]error {
+	stationErrs := make(map[string]error)
+	teamVlans, err := arena.networkSwitch.GetTeamVlans()
+	for station, team := range teams {
+		if team == nil {
+			continue
+		}
+		if err != nil {
+			stationErrs[station] = err
+		} else if teamVlans[team.Id] != stationVlans[station] {
+			stationErrs[station] = fmt.Errorf("Team %d is not configured on VLAN %d on the switch.", team.Id,
+				stationVlans[station])
+		}
+	}
+	return stationErrs
+}
+
+// Sets the WiFi or Ethernet status of every station that has a team to the given state and optional error message.
+func (arena *Arena) setNetworkStatus(setupCount int, teams map[string]*model.Team, isWifi bool, state string,
+	err error) {
+	status := NetworkStatus{State: state}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	for station, team := range teams {
+		arena.setStationNetworkStatus(setupCount, station, team, isWifi, status)
+	}
+}
+
+// Sets the WiFi or Ethernet status of every station that has a team to configured, or to failed if there is an error
+// given for it.
+func (arena *Arena) setStationNetworkStatuses(setupCount int, teams map[string]*model.Team, isWifi bool,
+	stationErrs map[string]error) {
+	for station, team := range teams {
+		status := NetworkStatus{State: NetworkConfigured}
+		if err := stationErrs[station]; err != nil {
+			status = NetworkStatus{State: NetworkFailed, Error: err.Error()}
+		}
+		arena.setStationNetworkStatus(setupCount, station, team, isWifi, status)
+	}
+}
+
+// Records the given network status for the station, as long as the given setup attempt is still the latest one. The
+// status of a station without a team is always left blank. Safe to call from the background setup goroutines.
+func (arena *Arena) setStationNetworkStatus(setupCount int, station string, team *model.Team, isWifi bool,
+	status NetworkStatus) {
+	arena.networkStatusMutex.Lock()
+	defer arena.networkStatusMutex.Unlock()
+	if setupCount != arena.networkSetupCount {
+		return
+	}
+	if team == nil {
+		status = NetworkStatus{}
+	}
+	if isWifi {
+		arena.AllianceStations[station].WifiStatus = status
+	} else {
+		arena.AllianceStations[station].EthernetStatus = status
+	}
+}
+
 // Returns nil if the match can be started, and an error otherwise.
 func (arena *Arena) checkCanStartMatch() error {
 	if arena.MatchState != PreMatch {
 		return fmt.Errorf("Cannot start match while there is a match still in progress or with results pending.")
 	}
-	for _, allianceStation := range arena.AllianceStations {
-		if allianceStation.Estop {
-			return fmt.Errorf("Cannot start match while an emergency stop is active.")
-		}
-		if !allianceStation.Bypass {
-			if allianceStation.DsConn == nil || !allianceStation.DsConn.RobotLinked {
-				return fmt.Errorf("Cannot start match until all robots are connected or bypassed.")
-			}
-		}
+	err := arena.checkAllianceStationsReady("R1", "R2", "R3", "B1", "B2", "B3")
+	if err != nil {
+		return err
 	}
 
 	if arena.EventSettings.PlcAddress != "" {
@@ -562,6 +858,25 @@ func (arena *Arena) checkCanStartMatch() error {
 	return nil
 }
 
+// Returns nil if the given alliance stations are clear of emergency stops and have their robots connected or
+// bypassed, and an error otherwise.
+func (arena *Arena) checkAllianceStationsReady(stations ...string) error {
+	for _, station := range stations {
+		if arena.AllianceStations[station].Estop {
+			return fmt.Errorf("Cannot start match while an emergency stop is active.")
+		}
+	}
+	for _, station := range stations {
+		allianceStation := arena.AllianceStations[station]
+		if !allianceStation.Bypass {
+			if allianceStation.DsConn == nil || !allianceStation.DsConn.RobotLinked {
+				return fmt.Errorf("Cannot start match until all robots are connected or bypassed.")
+			}
+		}
+	}
+	return nil
+}
+
 func (arena *Arena) sendDsPacket(auto bool, enabled bool) {
 	for _, allianceStation := range arena.AllianceStations {
 		dsConn := allianceStation.DsConn
@@ -653,11 +968,74 @@ func (arena *Arena) handlePlcInput() {
 	redScore.Takeoffs = game.CountTouchpads(&arena.RedRealtimeScore.touchpads, currentTime)
 	blueScore.Takeoffs = game.CountTouchpads(&arena.BlueRealtimeScore.touchpads, currentTime)
 
+	// Discard the sensor counts for anything that is being scored by hand.
+	arena.RedRealtimeScore.discardSensorCounts(&oldRedScore)
+	arena.BlueRealtimeScore.discardSensorCounts(&oldBlueScore)
+
+	// Don't let the sensors overwrite values that the head referee has corrected.
+	arena.RedRealtimeScore.applyOverrides()
+	arena.BlueRealtimeScore.applyOverrides()
+
 	if !oldRedScore.Equals(redScore) || !oldBlueScore.Equals(blueScore) {
 		arena.RealtimeScoreNotifier.Notify(nil)
 	}
 }
 
+// Records any changes to the realtime scores since the last loop iteration as timestamped events, once the match has
+// started.
+func (arena *Arena) updateScoringTimelines() {
+	if arena.CurrentMatch == nil {
+		return
+	}
+	timeInMatchSec := arena.MatchTimeSec()
+	if arena.MatchState == PostMatch {
+		// Keep counting after the end of the match so that late sensor counts and scorer edits are placed correctly.
+		timeInMatchSec = time.Since(arena.MatchStartTime).Seconds()
+	}
+	record := arena.MatchState != PreMatch && arena.MatchState != StartMatch
+	currentTime := time.Now()
+	arena.RedRealtimeScore.updateTimeline(timeInMatchSec, currentTime, record)
+	arena.BlueRealtimeScore.updateTimeline(timeInMatchSec, currentTime, record)
+}
+
+// Timestamps the end of the match, the committing of the scores and the signalling of the field reset the first time
+// each happens, and saves them to the database for the cycle time report. The commit and the reset are recorded
+// against the last match to have been played for as long as it stays loaded, even once it has been reset.
+func (arena *Arena) recordMatchMilestones() {
+	currentTime := time.Now()
+	changed := false
+	if arena.CurrentMatch != nil && arena.MatchState == PostMatch {
+		if arena.CurrentMatch.Type == "test" {
+			arena.lastPlayedMatch = nil
+		} else {
+			arena.lastPlayedMatch = arena.CurrentMatch
+			if arena.CurrentMatch.EndedAt.IsZero() {
+				arena.CurrentMatch.EndedAt = currentTime
+				changed = true
+			}
+		}
+	}
+	match := arena.lastPlayedMatch
+	if match == nil || match != arena.CurrentMatch {
+		return
+	}
+	if match.ScoresCommittedAt.IsZero() && arena.RedRealtimeScore.FoulsCommitted &&
+		arena.BlueRealtimeScore.FoulsCommitted && arena.RedRealtimeScore.TeleopCommitted &&
+		arena.BlueRealtimeScore.TeleopCommitted {
+		match.ScoresCommittedAt = currentTime
+		changed = true
+	}
+	if match.FieldResetAt.IsZero() && arena.FieldReset {
+		match.FieldResetAt = currentTime
+		changed = true
+	}
+	if changed {
+		if err := arena.Database.SaveMatchMilestones(match); err != nil {
+			log.Printf("Failed to save milestones for match %s: %s", match.DisplayName, err.Error())
+		}
+	}
+}
+
 // Writes light/motor commands to the field PLC.
 func (arena *Arena) handlePlcOutput() {
 	if arena.FieldTestMode != "" {
@@ -721,6 +1099,14 @@ func (arena *Arena) handlePlcOutput() {
 		}
 	}
 	arena.Plc.SetTouchpadLights(redTouchpads, blueTouchpads)
+
+	// Handle the stack light and driver station ready lights.
+	fieldReadyStatus := arena.GetFieldReadyStatus()
+	stackLight := fieldReadyStatus.StackLight
+	arena.Plc.SetStackLights(stackLight.Red, stackLight.Blue, stackLight.Orange, stackLight.Green)
+	stationLights := fieldReadyStatus.StationLights
+	arena.Plc.SetStationLights([3]bool{stationLights["R1"], stationLights["R2"], stationLights["R3"]},
+		[3]bool{stationLights["B1"], stationLights["B2"], stationLights["B3"]})
 }
 
 func (arena *Arena) handleEstop(station string, state bool) {
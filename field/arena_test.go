@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"github.com/Team254/cheesy-arena/game"
 	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/tournament"
 	"github.com/stretchr/testify/assert"
 	"log"
 	"testing"
@@ -359,6 +360,123 @@ func TestMatchStartRobotLinkEnforcement(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestFieldReadyStatus(t *testing.T) {
+	arena := setupTestArena(t)
+
+	// Check with no robots connected.
+	status := arena.GetFieldReadyStatus()
+	assert.False(t, status.RedAllianceReady)
+	assert.False(t, status.BlueAllianceReady)
+	assert.False(t, status.CanStartMatch)
+	assert.Equal(t, StackLight{Red: true, Blue: true}, status.StackLight)
+	assert.False(t, status.StationLights["R1"])
+
+	// Check with only the red alliance ready.
+	arena.AllianceStations["R1"].Bypass = true
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["R3"].DsConn = &DriverStationConnection{RobotLinked: true}
+	status = arena.GetFieldReadyStatus()
+	assert.True(t, status.RedAllianceReady)
+	assert.False(t, status.BlueAllianceReady)
+	assert.True(t, status.StationsReady["R3"])
+	assert.True(t, status.StationLights["R3"])
+	assert.False(t, status.StationsReady["B1"])
+	assert.Equal(t, StackLight{Blue: true}, status.StackLight)
+
+	// Check with both alliances ready.
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B2"].Bypass = true
+	arena.AllianceStations["B3"].Bypass = true
+	status = arena.GetFieldReadyStatus()
+	assert.True(t, status.CanStartMatch)
+	assert.True(t, status.EstopsClear)
+	assert.Equal(t, StackLight{Green: true}, status.StackLight)
+
+	// Check with an e-stop active.
+	arena.AllianceStations["B2"].Estop = true
+	status = arena.GetFieldReadyStatus()
+	assert.False(t, status.CanStartMatch)
+	assert.False(t, status.EstopsClear)
+	assert.False(t, status.BlueAllianceReady)
+	assert.Equal(t, StackLight{Blue: true}, status.StackLight)
+	arena.AllianceStations["B2"].Estop = false
+
+	// Check after the match while scores are pending.
+	arena.MatchState = PostMatch
+	status = arena.GetFieldReadyStatus()
+	assert.False(t, status.ScoresCommitted)
+	assert.False(t, status.StationLights["R3"])
+	assert.Equal(t, StackLight{Orange: true}, status.StackLight)
+	arena.RedRealtimeScore.TeleopCommitted = true
+	arena.RedRealtimeScore.FoulsCommitted = true
+	arena.BlueRealtimeScore.TeleopCommitted = true
+	arena.BlueRealtimeScore.FoulsCommitted = true
+	arena.FieldReset = true
+	status = arena.GetFieldReadyStatus()
+	assert.True(t, status.ScoresCommitted)
+	assert.Equal(t, StackLight{Green: true}, status.StackLight)
+}
+
+func TestMatchMilestones(t *testing.T) {
+	arena := setupTestArena(t)
+
+	match := model.Match{Type: "qualification", DisplayName: "1"}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+
+	// Nothing should be recorded while the match is yet to finish.
+	arena.recordMatchMilestones()
+	assert.True(t, arena.CurrentMatch.EndedAt.IsZero())
+
+	arena.MatchState = PostMatch
+	arena.recordMatchMilestones()
+	assert.False(t, arena.CurrentMatch.EndedAt.IsZero())
+	assert.True(t, arena.CurrentMatch.ScoresCommittedAt.IsZero())
+	assert.True(t, arena.CurrentMatch.FieldResetAt.IsZero())
+	endedAt := arena.CurrentMatch.EndedAt
+
+	arena.RedRealtimeScore.TeleopCommitted = true
+	arena.RedRealtimeScore.FoulsCommitted = true
+	arena.BlueRealtimeScore.TeleopCommitted = true
+	arena.recordMatchMilestones()
+	assert.True(t, arena.CurrentMatch.ScoresCommittedAt.IsZero())
+	arena.BlueRealtimeScore.FoulsCommitted = true
+	arena.FieldReset = true
+	arena.recordMatchMilestones()
+	assert.Equal(t, endedAt, arena.CurrentMatch.EndedAt)
+	assert.False(t, arena.CurrentMatch.ScoresCommittedAt.IsZero())
+	assert.False(t, arena.CurrentMatch.FieldResetAt.IsZero())
+
+	savedMatch, _ := arena.Database.GetMatchById(match.Id)
+	assert.Equal(t, endedAt.Unix(), savedMatch.EndedAt.Unix())
+	assert.False(t, savedMatch.ScoresCommittedAt.IsZero())
+	assert.False(t, savedMatch.FieldResetAt.IsZero())
+
+	// The field reset should still be recorded once the match has been reset, and before the next one is loaded.
+	match.FieldResetAt = time.Time{}
+	arena.FieldReset = false
+	arena.MatchState = PreMatch
+	arena.recordMatchMilestones()
+	assert.True(t, match.FieldResetAt.IsZero())
+	arena.FieldReset = true
+	nextMatch := model.Match{Type: "qualification", DisplayName: "2"}
+	arena.Database.CreateMatch(&nextMatch)
+	assert.Nil(t, arena.LoadMatch(&nextMatch))
+	assert.False(t, match.FieldResetAt.IsZero())
+	arena.FieldReset = true
+	arena.recordMatchMilestones()
+	assert.True(t, nextMatch.FieldResetAt.IsZero())
+	savedMatch, _ = arena.Database.GetMatchById(match.Id)
+	assert.False(t, savedMatch.FieldResetAt.IsZero())
+
+	// Test matches shouldn't be recorded.
+	arena.MatchState = PreMatch
+	assert.Nil(t, arena.LoadTestMatch())
+	arena.MatchState = PostMatch
+	arena.recordMatchMilestones()
+	assert.True(t, arena.CurrentMatch.EndedAt.IsZero())
+}
+
 func TestLoadNextMatch(t *testing.T) {
 	arena := setupTestArena(t)
 
@@ -410,6 +528,32 @@ func TestLoadNextMatch(t *testing.T) {
 	assert.Equal(t, qualificationMatch2.Id, arena.CurrentMatch.Id)
 }
 
+func TestLoadNextMatchAfterDoubleEliminationTie(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.EventSettings.ElimType = "double"
+	arena.Database.SaveEventSettings(arena.EventSettings)
+	tournament.CreateTestAlliances(arena.Database, 8)
+	tournament.UpdateEliminationSchedule(arena.Database, time.Unix(0, 0))
+	completeMatch := func(displayName, winner string) {
+		match, _ := arena.Database.GetMatchByName("elimination", displayName)
+		match.Status = "complete"
+		match.Winner = winner
+		arena.Database.SaveMatch(match)
+	}
+	completeMatch("M1-1", "T")
+	completeMatch("M2-1", "R")
+	completeMatch("M3-1", "R")
+	completeMatch("M4-1", "R")
+	tournament.UpdateEliminationSchedule(arena.Database, time.Unix(0, 0))
+
+	// The replay of the tied match should come before the later matches that are waiting on its result.
+	match, _ := arena.Database.GetMatchByName("elimination", "M4-1")
+	assert.Nil(t, arena.LoadMatch(match))
+	assert.Nil(t, arena.LoadNextMatch())
+	assert.Equal(t, "M1-2", arena.CurrentMatch.DisplayName)
+	assert.Equal(t, int64(0), arena.CurrentMatch.Time.Unix())
+}
+
 func TestSubstituteTeam(t *testing.T) {
 	arena := setupTestArena(t)
 
@@ -461,7 +605,7 @@ func TestSetupNetwork(t *testing.T) {
 
 	// Verify the setup ran by checking the log for the expected failure messages.
 	arena.EventSettings.NetworkSecurityEnabled = true
-	arena.accessPoint.port = 10022
+	arena.accessPoint.(*OpenWrtAccessPoint).port = 10022
 	arena.networkSwitch.port = 10023
 	arena.LoadMatch(&model.Match{Type: "test"})
 	var writer bytes.Buffer
@@ -470,3 +614,207 @@ func TestSetupNetwork(t *testing.T) {
 	assert.Contains(t, writer.String(), "Failed to configure team Ethernet")
 	assert.Contains(t, writer.String(), "Failed to configure team WiFi")
 }
+
+func TestSetupNetworkStatus(t *testing.T) {
+	arena := setupTestArena(t)
+	networkVerifyInterval = time.Millisecond
+
+	arena.EventSettings.NetworkSecurityEnabled = true
+	arena.accessPoint = &fakeAccessPoint{status: &AccessPointStatus{Networks: []AccessPointNetwork{{Ssid: "254"}}}}
+	arena.networkSwitch = NewNetworkSwitch("cisco", "telnet", "127.0.0.1", "", "password", false)
+	arena.networkSwitch.port = 9080
+	var commands []string
+	mockTelnet(t, arena.networkSwitch.port, []string{"", "", "interface Vlan10\nip address 10.2.54.61\n"}, &commands)
+	arena.Database.CreateTeam(&model.Team{Id: 254, WpaKey: "aaaaaaaa"})
+	arena.Database.CreateTeam(&model.Team{Id: 1114, WpaKey: "bbbbbbbb"})
+	err := arena.LoadMatch(&model.Match{Type: "test", Red1: 254, Blue3: 1114})
+	assert.Nil(t, err)
+	assert.Equal(t, NetworkPending, arena.GetStatus().AllianceStations["R1"].EthernetStatus.State)
+	assert.Equal(t, NetworkStatus{}, arena.GetStatus().AllianceStations["R2"].WifiStatus)
+
+	// Wait for the asynchronous configuration and verification to finish.
+	for i := 0; i < 100; i++ {
+		if arena.GetStatus().AllianceStations["B3"].WifiStatus.State == NetworkFailed &&
+			arena.GetStatus().AllianceStations["B3"].EthernetStatus.State == NetworkFailed {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, NetworkStatus{NetworkConfigured, ""}, arena.GetStatus().AllianceStations["R1"].WifiStatus)
+	assert.Equal(t, NetworkStatus{NetworkConfigured, ""}, arena.GetStatus().AllianceStations["R1"].EthernetStatus)
+	assert.Equal(t, NetworkStatus{NetworkFailed, "SSID 1114 is not active on the access point."},
+		arena.GetStatus().AllianceStations["B3"].WifiStatus)
+	assert.Equal(t, NetworkStatus{NetworkFailed, "Team 1114 is not configured on VLAN 60 on the switch."},
+		arena.GetStatus().AllianceStations["B3"].EthernetStatus)
+	assert.Equal(t, NetworkStatus{}, arena.GetStatus().AllianceStations["B1"].EthernetStatus)
+	assert.Equal(t, 2, len(commands))
+
+	// Check that a retry is only allowed before the match.
+	arena.MatchState = AutoPeriod
+	assert.NotNil(t, arena.RetryNetworkSetup())
+	arena.MatchState = PreMatch
+	arena.EventSettings.NetworkSecurityEnabled = false
+	err = arena.RetryNetworkSetup()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "not enabled")
+	}
+
+	// Check that the status is cleared if network security is disabled.
+	arena.LoadMatch(&model.Match{Type: "test", Red1: 254})
+	assert.Equal(t, NetworkStatus{}, arena.GetStatus().AllianceStations["R1"].WifiStatus)
+	assert.Equal(t, NetworkStatus{}, arena.GetStatus().AllianceStations["R1"].EthernetStatus)
+}
+
+type fakeAccessPoint struct {
+	status        *AccessPointStatus
+	config        string
+	currentConfig string
+}
+
+func (ap *fakeAccessPoint) ConfigureTeamWifi(red1, red2, red3, blue1, blue2, blue3 *model.Team) error {
+	return nil
+}
+
+func (ap *fakeAccessPoint) ConfigureAdminWifi() error {
+	return nil
+}
+
+func (ap *fakeAccessPoint) GetStatus() (*AccessPointStatus, error) {
+	return ap.status, nil
+}
+
+func (ap *fakeAccessPoint) GenerateTeamWifiConfig(red1, red2, red3, blue1, blue2, blue3 *model.Team) (string,
+	error) {
+	return ap.config, nil
+}
+
+func (ap *fakeAccessPoint) GetTeamWifiConfig() (string, error) {
+	return ap.currentConfig, nil
+}
+
+func TestLoadRules(t *testing.T) {
+	arena := setupTestArena(t)
+	defer func() {
+		game.FoulPenalties.FoulPointValue = 5
+		game.FoulPenalties.TechFoulPointValue = 25
+		game.FoulPenalties.RankingPointRules = map[game.Rule]bool{}
+	}()
+
+	arena.EventSettings.FoulPointValue = 3
+	arena.EventSettings.TechFoulPointValue = 10
+	rule, _ := arena.Database.GetRuleById(1)
+	rule.AwardsRankingPoint = true
+	assert.Nil(t, arena.Database.SaveRule(rule))
+	assert.Nil(t, arena.LoadRules())
+	foul := game.Foul{Rule: rule.GameRule()}
+	assert.Equal(t, 3, foul.PointValue())
+	assert.True(t, foul.AwardsRankingPoint())
+	foul.IsTechnical = true
+	assert.Equal(t, 10, foul.PointValue())
+	assert.False(t, foul.AwardsRankingPoint())
+}
+
+func TestRealtimeScoreTimeline(t *testing.T) {
+	realtimeScore := NewRealtimeScore()
+	matchStartTime := time.Unix(1000, 0)
+
+	// Changes before the match starts shouldn't be recorded.
+	realtimeScore.CurrentScore.AutoFuelHigh = 3
+	realtimeScore.updateTimeline(0, matchStartTime, false)
+	assert.Empty(t, realtimeScore.Timeline)
+
+	realtimeScore.CurrentScore.AutoFuelHigh = 5
+	realtimeScore.CurrentScore.AutoRotors = 1
+	realtimeScore.updateTimeline(7.5, matchStartTime.Add(7500*time.Millisecond), true)
+	realtimeScore.updateTimeline(8, matchStartTime.Add(8*time.Second), true)
+	if assert.Equal(t, 2, len(realtimeScore.Timeline)) {
+		assert.Equal(t, game.ScoringEvent{7.5, game.SensorSource, "AutoFuelHigh", 5, "Auto high fuel: 5"},
+			realtimeScore.Timeline[0])
+		assert.Equal(t, "Rotor 1 turning (auto)", realtimeScore.Timeline[1].Description)
+	}
+
+	realtimeScore.touchpads[1].UpdateState(true, matchStartTime, matchStartTime.Add(140*time.Second))
+	realtimeScore.updateTimeline(140, matchStartTime.Add(140*time.Second), true)
+	realtimeScore.updateTimeline(141.2, matchStartTime.Add(141200*time.Millisecond), true)
+	realtimeScore.CurrentScore.Fouls = append(realtimeScore.CurrentScore.Fouls,
+		game.Foul{Rule: game.Rule{"G22", false}, TeamId: 254})
+	realtimeScore.updateTimeline(142, matchStartTime.Add(142*time.Second), true)
+	realtimeScore.updateTimeline(143, matchStartTime.Add(143*time.Second), true)
+	if assert.Equal(t, 4, len(realtimeScore.Timeline)) {
+		assert.Equal(t, game.ScoringEvent{141.2, game.SensorSource, "Touchpad", 2, "Touchpad 2 held"},
+			realtimeScore.Timeline[2])
+		assert.Equal(t, game.ScoringEvent{142, game.RefereeSource, "Foul", 254, "Foul G22 on 254"},
+			realtimeScore.Timeline[3])
+	}
+}
+
+func TestScoreOverridesSurviveSensorUpdates(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.LoadTestMatch()
+
+	redScore := &arena.RedRealtimeScore.CurrentScore
+	redScore.FuelLow = 5
+	redScore.Takeoffs = 1
+	assert.Nil(t, arena.RedRealtimeScore.OverrideScore("Takeoffs", 2, "Touchpad light out", 150, false))
+	assert.NotNil(t, arena.RedRealtimeScore.OverrideScore("AutoMobility", 2, "Not a sensor", 150, false))
+	arena.handlePlcInput()
+	assert.Equal(t, 0, redScore.FuelLow)
+	assert.Equal(t, 2, redScore.Takeoffs)
+
+	// The original value should follow the sensors, and be kept when the same element is overridden again.
+	assert.Equal(t, 0, arena.RedRealtimeScore.Overrides[0].OriginalValue)
+	assert.Nil(t, arena.RedRealtimeScore.OverrideScore("Takeoffs", 3, "Touchpad light out", 151, true))
+	arena.handlePlcInput()
+	assert.Equal(t, 3, redScore.Takeoffs)
+	if assert.Equal(t, 1, len(arena.RedRealtimeScore.Overrides)) {
+		assert.Equal(t, game.ScoreOverride{"Takeoffs", 3, 0, "Touchpad light out", 151, true},
+			arena.RedRealtimeScore.Overrides[0])
+	}
+	arena.RedRealtimeScore.updateTimeline(151, time.Now(), true)
+	if assert.Equal(t, 1, len(arena.RedRealtimeScore.Timeline)) {
+		assert.Equal(t, game.RefereeSource, arena.RedRealtimeScore.Timeline[0].Source)
+		assert.Equal(t, "Takeoffs: 3 (override: Touchpad light out)", arena.RedRealtimeScore.Timeline[0].Description)
+	}
+
+	arena.RedRealtimeScore.ClearOverride("Takeoffs")
+	assert.Equal(t, 0, redScore.Takeoffs)
+	assert.Empty(t, arena.RedRealtimeScore.Overrides)
+}
+
+func TestManualScoring(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.LoadTestMatch()
+	assert.True(t, arena.ManualScoringAvailable())
+	arena.EventSettings.PlcAddress = "10.0.100.10"
+	arena.Plc.IsHealthy = true
+	assert.False(t, arena.ManualScoringAvailable())
+	arena.Plc.IsHealthy = false
+	assert.True(t, arena.ManualScoringAvailable())
+	arena.EventSettings.PlcAddress = ""
+
+	// The sensor counts should be discarded for an alliance that is being scored by hand.
+	blueScore := &arena.BlueRealtimeScore.CurrentScore
+	arena.BlueRealtimeScore.SetManualScoring(true)
+	arena.BlueRealtimeScore.UpdateScorer("1", func(scorer *ScorerState) { scorer.ScoreManually("AutoFuelHigh", 12) })
+	arena.RedRealtimeScore.CurrentScore.AutoFuelHigh = 12
+	arena.handlePlcInput()
+	assert.Equal(t, 12, blueScore.AutoFuelHigh)
+	assert.Equal(t, 0, arena.RedRealtimeScore.CurrentScore.AutoFuelHigh)
+	arena.BlueRealtimeScore.updateTimeline(10, time.Now(), true)
+	if assert.Equal(t, 1, len(arena.BlueRealtimeScore.Timeline)) {
+		assert.Equal(t, game.ManualSource, arena.BlueRealtimeScore.Timeline[0].Source)
+	}
+
+	// Elements counted by hand should keep their manual counts and sources after returning to the sensors, while the
+	// rest go back to being counted by the sensors.
+	assert.Nil(t, arena.BlueRealtimeScore.OverrideScore("Takeoffs", 2, "Touchpad light out", 150, false))
+	arena.BlueRealtimeScore.SetManualScoring(false)
+	arena.handlePlcInput()
+	assert.Equal(t, 12, blueScore.AutoFuelHigh)
+	assert.Equal(t, 2, blueScore.Takeoffs)
+	sources := arena.BlueRealtimeScore.ScoreSources()
+	assert.Equal(t, game.ManualSource, sources["AutoFuelHigh"])
+	assert.Equal(t, game.RefereeSource, sources["Takeoffs"])
+	assert.Equal(t, game.SensorSource, sources["FuelHigh"])
+	assert.Equal(t, game.SensorSource, arena.RedRealtimeScore.ScoreSources()["FuelLow"])
+}
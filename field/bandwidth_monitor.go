@@ -7,6 +7,7 @@ package field
 
 import (
 	"fmt"
+	"github.com/Team254/cheesy-arena/model"
 	"github.com/cdevr/WapSNMP"
 	"log"
 	"time"
@@ -16,16 +17,11 @@ const (
 	monitoringIntervalMs = 1000
 	toRobotBytesOid      = ".1.3.6.1.2.1.2.2.1.10"
 	fromRobotBytesOid    = ".1.3.6.1.2.1.2.2.1.16"
-	red1Port             = 6
-	red2Port             = 8
-	red3Port             = 10
-	blue1Port            = 12
-	blue2Port            = 14
-	blue3Port            = 16
 )
 
 type BandwidthMonitor struct {
-	allianceStations   *map[string]*AllianceStation
+	arena              *Arena
+	interfaceIndexes   []int
 	snmpClient         *wapsnmp.WapSNMP
 	toRobotOids        []wapsnmp.Oid
 	fromRobotOids      []wapsnmp.Oid
@@ -34,42 +30,76 @@ type BandwidthMonitor struct {
 	lastBytesTime      time.Time
 }
 
+var bandwidthMonitorSnmpPort = 161 // Mutable for testing
+
 // Loops indefinitely to query the managed switch via SNMP (Simple Network Management Protocol).
 func (arena *Arena) monitorBandwidth() {
-	monitor := BandwidthMonitor{allianceStations: &arena.AllianceStations}
-
-	for _, port := range []int{red1Port, red2Port, red3Port, blue1Port, blue2Port, blue3Port} {
-		toOid := fmt.Sprintf("%s.%d", toRobotBytesOid, 10100+port)
-		fromOid := fmt.Sprintf("%s.%d", fromRobotBytesOid, 10100+port)
-		monitor.toRobotOids = append(monitor.toRobotOids, wapsnmp.MustParseOid(toOid))
-		monitor.fromRobotOids = append(monitor.fromRobotOids, wapsnmp.MustParseOid(fromOid))
-	}
+	monitor := BandwidthMonitor{arena: arena}
 
 	for {
-		if monitor.snmpClient != nil && monitor.snmpClient.Target != arena.EventSettings.SwitchAddress {
-			// Switch address has changed; must re-create the SNMP client.
-			monitor.snmpClient.Close()
-			monitor.snmpClient = nil
+		if err := monitor.poll(); err != nil {
+			log.Println(err)
 		}
+		time.Sleep(time.Millisecond * monitoringIntervalMs)
+	}
+}
+
+// Queries the switch once for the latest traffic counters, first re-creating the SNMP client if the switch address
+// has changed.
+func (monitor *BandwidthMonitor) poll() error {
+	settings := monitor.arena.EventSettings
+	monitor.updateOids()
+
+	target := fmt.Sprintf("%s:%d", settings.SwitchAddress, bandwidthMonitorSnmpPort)
+	if monitor.snmpClient != nil && monitor.snmpClient.Target != target {
+		// Switch address has changed; must re-create the SNMP client.
+		monitor.snmpClient.Close()
+		monitor.snmpClient = nil
+	}
 
-		if monitor.snmpClient == nil {
-			var err error
-			monitor.snmpClient, err = wapsnmp.NewWapSNMP(arena.EventSettings.SwitchAddress,
-				arena.EventSettings.SwitchPassword, wapsnmp.SNMPv2c, 2*time.Second, 0)
-			if err != nil {
-				log.Printf("Error starting bandwidth monitoring: %v", err)
-				continue
-			}
+	if monitor.snmpClient == nil {
+		var err error
+		monitor.snmpClient, err = wapsnmp.NewWapSNMP(target, settings.SwitchPassword, wapsnmp.SNMPv2c,
+			2*time.Second, 0)
+		if err != nil {
+			return fmt.Errorf("Error starting bandwidth monitoring: %v", err)
 		}
+	}
 
-		if arena.EventSettings.NetworkSecurityEnabled && arena.EventSettings.BandwidthMonitoringEnabled {
-			err := monitor.updateBandwidth()
-			if err != nil {
-				log.Printf("Bandwidth monitoring error: %v", err)
-			}
+	if settings.NetworkSecurityEnabled && settings.BandwidthMonitoringEnabled {
+		if err := monitor.updateBandwidth(); err != nil {
+			return fmt.Errorf("Bandwidth monitoring error: %v", err)
 		}
-		time.Sleep(time.Millisecond * monitoringIntervalMs)
 	}
+	return nil
+}
+
+// Rebuilds the list of OIDs to query if the switch type or the ports that the alliance stations are connected to have
+// changed.
+func (monitor *BandwidthMonitor) updateOids() {
+	settings := monitor.arena.EventSettings
+	var interfaceIndexes []int
+	for _, port := range []int{settings.Red1SwitchPort, settings.Red2SwitchPort, settings.Red3SwitchPort,
+		settings.Blue1SwitchPort, settings.Blue2SwitchPort, settings.Blue3SwitchPort} {
+		interfaceIndexes = append(interfaceIndexes, monitor.arena.networkSwitch.SnmpInterfaceIndex(port))
+	}
+	if fmt.Sprint(interfaceIndexes) == fmt.Sprint(monitor.interfaceIndexes) {
+		return
+	}
+
+	monitor.interfaceIndexes = interfaceIndexes
+	monitor.toRobotOids = nil
+	monitor.fromRobotOids = nil
+	for _, interfaceIndex := range interfaceIndexes {
+		toOid := fmt.Sprintf("%s.%d", toRobotBytesOid, interfaceIndex)
+		fromOid := fmt.Sprintf("%s.%d", fromRobotBytesOid, interfaceIndex)
+		monitor.toRobotOids = append(monitor.toRobotOids, wapsnmp.MustParseOid(toOid))
+		monitor.fromRobotOids = append(monitor.fromRobotOids, wapsnmp.MustParseOid(fromOid))
+	}
+
+	// Discard the previous readings since they were for different ports.
+	monitor.lastToRobotBytes = nil
+	monitor.lastFromRobotBytes = nil
 }
 
 func (monitor *BandwidthMonitor) updateBandwidth() error {
@@ -99,7 +129,8 @@ func (monitor *BandwidthMonitor) updateBandwidth() error {
 
 func (monitor *BandwidthMonitor) updateStationBandwidth(station string, oidIndex int, toRobotBytes map[string]interface{},
 	fromRobotBytes map[string]interface{}) {
-	dsConn := (*monitor.allianceStations)[station].DsConn
+	allianceStation := monitor.arena.AllianceStations[station]
+	dsConn := allianceStation.DsConn
 	if dsConn == nil {
 		// No team assigned; just skip it.
 		return
@@ -131,4 +162,52 @@ func (monitor *BandwidthMonitor) updateStationBandwidth(station string, oidIndex
 	}
 	lastFromRobotBytesForPort := uint32(monitor.lastFromRobotBytes[fromOid].(wapsnmp.Counter))
 	dsConn.MBpsFromRobot = float64(fromRobotBytesForPort-lastFromRobotBytesForPort) / 1024 / 128 / secondsSinceLast
+
+	monitor.arena.recordBandwidth(allianceStation)
+}
+
+// Checks the latest bandwidth reading of the given station against the team's limit and adds it to the match history
+// if a match is in progress.
+func (arena *Arena) recordBandwidth(allianceStation *AllianceStation) {
+	dsConn := allianceStation.DsConn
+	limit := arena.EventSettings.BandwidthAlertMbps
+	if allianceStation.Team != nil && allianceStation.Team.BandwidthLimitMbps > 0 {
+		limit = allianceStation.Team.BandwidthLimitMbps
+	}
+	dsConn.BandwidthAlert = limit > 0 && dsConn.MBpsToRobot+dsConn.MBpsFromRobot > limit
+
+	if arena.MatchState > PreMatch && arena.MatchState < PostMatch {
+		dsConn.bandwidthSamplesMutex.Lock()
+		dsConn.bandwidthSamples = append(dsConn.bandwidthSamples,
+			model.BandwidthSample{MatchTimeSec: arena.MatchTimeSec(), MBpsToRobot: dsConn.MBpsToRobot,
+				MBpsFromRobot: dsConn.MBpsFromRobot})
+		dsConn.bandwidthSamplesMutex.Unlock()
+	}
+}
+
+// Saves the bandwidth usage history of each team in the match that just ended.
+func (arena *Arena) saveBandwidthLogs() {
+	if arena.CurrentMatch.Type == "test" {
+		return
+	}
+	for station, allianceStation := range arena.AllianceStations {
+		dsConn := allianceStation.DsConn
+		if dsConn == nil {
+			continue
+		}
+		dsConn.bandwidthSamplesMutex.Lock()
+		samples := dsConn.bandwidthSamples
+		if samples != nil {
+			samples = append([]model.BandwidthSample{}, samples...)
+		}
+		dsConn.bandwidthSamplesMutex.Unlock()
+		if samples == nil {
+			continue
+		}
+		bandwidthLog := model.BandwidthLog{MatchId: arena.CurrentMatch.Id, TeamId: dsConn.TeamId,
+			AllianceStation: station, Samples: samples}
+		if err := arena.Database.SaveBandwidthLog(&bandwidthLog); err != nil {
+			log.Printf("Failed to save bandwidth log for team %d: %s", dsConn.TeamId, err.Error())
+		}
+	}
 }
@@ -0,0 +1,69 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRecordBandwidth(t *testing.T) {
+	arena := setupTestArena(t)
+
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	arena.Database.CreateTeam(&model.Team{Id: 1114, BandwidthLimitMbps: 2})
+	arena.assignTeam(254, "R1")
+	arena.assignTeam(1114, "B2")
+	red1 := &DriverStationConnection{TeamId: 254}
+	blue2 := &DriverStationConnection{TeamId: 1114}
+	arena.AllianceStations["R1"].DsConn = red1
+	arena.AllianceStations["B2"].DsConn = blue2
+
+	// Check the alerts against the event-wide and per-team limits.
+	red1.MBpsToRobot, red1.MBpsFromRobot = 4, 2.5
+	blue2.MBpsToRobot, blue2.MBpsFromRobot = 1.5, 1
+	arena.recordBandwidth(arena.AllianceStations["R1"])
+	arena.recordBandwidth(arena.AllianceStations["B2"])
+	assert.False(t, red1.BandwidthAlert)
+	assert.True(t, blue2.BandwidthAlert)
+	assert.Nil(t, red1.bandwidthSamples)
+	red1.MBpsFromRobot = 3.5
+	arena.recordBandwidth(arena.AllianceStations["R1"])
+	assert.True(t, red1.BandwidthAlert)
+	arena.EventSettings.BandwidthAlertMbps = 0
+	arena.recordBandwidth(arena.AllianceStations["R1"])
+	assert.False(t, red1.BandwidthAlert)
+
+	// Check that samples are only recorded while the match is running and saved at the end.
+	match := model.Match{Type: "qualification", DisplayName: "1"}
+	arena.Database.CreateMatch(&match)
+	arena.CurrentMatch = &match
+	red1.bandwidthSamples = []model.BandwidthSample{}
+	blue2.bandwidthSamples = []model.BandwidthSample{}
+	arena.MatchState = TeleopPeriod
+	arena.recordBandwidth(arena.AllianceStations["R1"])
+	arena.recordBandwidth(arena.AllianceStations["B2"])
+	red1.MBpsToRobot = 5
+	arena.recordBandwidth(arena.AllianceStations["R1"])
+	if assert.Equal(t, 2, len(red1.bandwidthSamples)) {
+		assert.Equal(t, 5.0, red1.bandwidthSamples[1].MBpsToRobot)
+	}
+	arena.AbortMatch()
+	arena.recordBandwidth(arena.AllianceStations["R1"])
+	assert.Equal(t, 2, len(red1.bandwidthSamples))
+	bandwidthLogs, err := arena.Database.GetBandwidthLogsForMatch(match.Id)
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(bandwidthLogs)) {
+		for _, bandwidthLog := range bandwidthLogs {
+			if bandwidthLog.AllianceStation == "R1" {
+				assert.Equal(t, 254, bandwidthLog.TeamId)
+				assert.Equal(t, 5.0, bandwidthLog.Summarize().PeakMBpsToRobot)
+			} else {
+				assert.Equal(t, 1114, bandwidthLog.TeamId)
+				assert.Equal(t, 1, len(bandwidthLog.Samples))
+			}
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"net"
 	"regexp"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -41,6 +42,7 @@ type DriverStationConnection struct {
 	MissedPacketCount         int
 	MBpsToRobot               float64
 	MBpsFromRobot             float64
+	BandwidthAlert            bool
 	SecondsSinceLastRobotLink float64
 	lastPacketTime            time.Time
 	lastRobotLinkedTime       time.Time
@@ -49,6 +51,8 @@ type DriverStationConnection struct {
 	tcpConn                   net.Conn
 	udpConn                   net.Conn
 	log                       *TeamMatchLog
+	bandwidthSamples          []model.BandwidthSample
+	bandwidthSamplesMutex     sync.Mutex // Samples are recorded by the bandwidth monitor and saved by the arena loop.
 }
 
 var allianceStationPositionMap = map[string]byte{"R1": 0, "R2": 1, "R3": 2, "B1": 3, "B2": 4, "B3": 5}
@@ -122,6 +126,7 @@ func (dsConn *DriverStationConnection) update(arena *Arena) error {
 		dsConn.BatteryVoltage = 0
 		dsConn.MBpsToRobot = 0
 		dsConn.MBpsFromRobot = 0
+		dsConn.BandwidthAlert = false
 	}
 	dsConn.SecondsSinceLastRobotLink = time.Since(dsConn.lastRobotLinkedTime).Seconds()
 
@@ -144,6 +149,9 @@ func (dsConn *DriverStationConnection) close() {
 func (dsConn *DriverStationConnection) signalMatchStart(match *model.Match) error {
 	// Zero out missed packet count and begin logging.
 	dsConn.missedPacketOffset = dsConn.MissedPacketCount
+	dsConn.bandwidthSamplesMutex.Lock()
+	dsConn.bandwidthSamples = []model.BandwidthSample{}
+	dsConn.bandwidthSamplesMutex.Unlock()
 	var err error
 	dsConn.log, err = NewTeamMatchLog(dsConn.TeamId, match)
 	return err
@@ -383,7 +391,7 @@ func (dsConn *DriverStationConnection) handleTcpConnection(arena *Arena) {
 		// Log the packet if the match is in progress.
 		matchTimeSec := arena.MatchTimeSec()
 		if matchTimeSec > 0 && dsConn.log != nil {
-			dsConn.log.LogDsPacket(matchTimeSec, packetType, dsConn)
+			dsConn.log.LogDsPacket(matchTimeSec, packetType, dsConn, arena.stationWireless(dsConn.AllianceStation))
 		}
 	}
 }
@@ -0,0 +1,201 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// BER tags used in SNMPv2c messages.
+const (
+	berInteger      = 0x02
+	berOctetString  = 0x04
+	berNull         = 0x05
+	berOid          = 0x06
+	berSequence     = 0x30
+	berCounter32    = 0x41
+	berNoSuchObject = 0x80
+	berGetRequest   = 0xa0
+	berGetResponse  = 0xa2
+)
+
+// A tiny SNMPv2c agent that answers GET requests for a set of 32-bit counters, such as the ifInOctets and ifOutOctets
+// of each switch port.
+type fakeSnmpAgent struct {
+	port      int
+	community string
+	counters  map[string]uint32
+	conn      *net.UDPConn
+	mutex     sync.Mutex
+}
+
+type berValue struct {
+	tag     byte
+	content []byte
+}
+
+func newFakeSnmpAgent(t *testing.T, community string) *fakeSnmpAgent {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	assert.Nil(t, err)
+	agent := &fakeSnmpAgent{port: conn.LocalAddr().(*net.UDPAddr).Port, community: community,
+		counters: make(map[string]uint32), conn: conn}
+	go func() {
+		var buffer [1500]byte
+		for {
+			length, address, err := conn.ReadFromUDP(buffer[:])
+			if err != nil {
+				return
+			}
+			if response, err := agent.handleRequest(buffer[:length]); err == nil {
+				conn.WriteToUDP(response, address)
+			}
+		}
+	}()
+	return agent
+}
+
+// Sets the value of the counter with the given OID (e.g. ".1.3.6.1.2.1.2.2.1.10.10106").
+func (agent *fakeSnmpAgent) setCounter(oid string, value uint32) {
+	agent.mutex.Lock()
+	defer agent.mutex.Unlock()
+	agent.counters[oid] = value
+}
+
+func (agent *fakeSnmpAgent) close() {
+	agent.conn.Close()
+}
+
+// Decodes a GET request and encodes the response to it. Requests with the wrong community are ignored, as a real
+// agent would do.
+func (agent *fakeSnmpAgent) handleRequest(request []byte) ([]byte, error) {
+	message, _, err := decodeBer(request)
+	if err != nil || message.tag != berSequence {
+		return nil, fmt.Errorf("Invalid SNMP message.")
+	}
+	fields, err := decodeBerSequence(message.content)
+	if err != nil || len(fields) != 3 || fields[2].tag != berGetRequest {
+		return nil, fmt.Errorf("Invalid SNMP message.")
+	}
+	if string(fields[1].content) != agent.community {
+		return nil, fmt.Errorf("Invalid SNMP community.")
+	}
+	pduFields, err := decodeBerSequence(fields[2].content)
+	if err != nil || len(pduFields) != 4 {
+		return nil, fmt.Errorf("Invalid SNMP PDU.")
+	}
+	varBinds, err := decodeBerSequence(pduFields[3].content)
+	if err != nil {
+		return nil, err
+	}
+
+	agent.mutex.Lock()
+	defer agent.mutex.Unlock()
+	var responseVarBinds []byte
+	for _, varBind := range varBinds {
+		varBindFields, err := decodeBerSequence(varBind.content)
+		if err != nil || len(varBindFields) != 2 || varBindFields[0].tag != berOid {
+			return nil, fmt.Errorf("Invalid SNMP variable binding.")
+		}
+		value := encodeBer(berNoSuchObject, nil)
+		if counter, ok := agent.counters[decodeBerOid(varBindFields[0].content)]; ok {
+			value = encodeBer(berCounter32, encodeBerUnsigned(uint64(counter)))
+		}
+		responseVarBinds = append(responseVarBinds,
+			encodeBer(berSequence, append(encodeBer(berOid, varBindFields[0].content), value...))...)
+	}
+
+	pdu := encodeBer(pduFields[0].tag, pduFields[0].content)
+	pdu = append(pdu, encodeBer(berInteger, []byte{0})...)
+	pdu = append(pdu, encodeBer(berInteger, []byte{0})...)
+	pdu = append(pdu, encodeBer(berSequence, responseVarBinds)...)
+	response := encodeBer(berInteger, fields[0].content)
+	response = append(response, encodeBer(berOctetString, fields[1].content)...)
+	response = append(response, encodeBer(berGetResponse, pdu)...)
+	return encodeBer(berSequence, response), nil
+}
+
+// Decodes the first tag-length-value item in the given data and returns it along with the remaining data.
+func decodeBer(data []byte) (berValue, []byte, error) {
+	if len(data) < 2 {
+		return berValue{}, nil, fmt.Errorf("BER value is truncated.")
+	}
+	tag, length, offset := data[0], int(data[1]), 2
+	if length&0x80 != 0 {
+		numBytes := length & 0x7f
+		if len(data) < 2+numBytes {
+			return berValue{}, nil, fmt.Errorf("BER length is truncated.")
+		}
+		length = 0
+		for _, b := range data[2 : 2+numBytes] {
+			length = length<<8 | int(b)
+		}
+		offset += numBytes
+	}
+	if len(data) < offset+length {
+		return berValue{}, nil, fmt.Errorf("BER value is truncated.")
+	}
+	return berValue{tag, data[offset : offset+length]}, data[offset+length:], nil
+}
+
+func decodeBerSequence(data []byte) ([]berValue, error) {
+	var values []berValue
+	for len(data) > 0 {
+		value, rest, err := decodeBer(data)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		data = rest
+	}
+	return values, nil
+}
+
+func decodeBerOid(content []byte) string {
+	if len(content) == 0 {
+		return ""
+	}
+	parts := []string{strconv.Itoa(int(content[0]) / 40), strconv.Itoa(int(content[0]) % 40)}
+	value := 0
+	for _, b := range content[1:] {
+		value = value<<7 | int(b&0x7f)
+		if b&0x80 == 0 {
+			parts = append(parts, strconv.Itoa(value))
+			value = 0
+		}
+	}
+	return "." + strings.Join(parts, ".")
+}
+
+func encodeBer(tag byte, content []byte) []byte {
+	length := len(content)
+	var header []byte
+	if length < 0x80 {
+		header = []byte{tag, byte(length)}
+	} else {
+		var lengthBytes []byte
+		for ; length > 0; length >>= 8 {
+			lengthBytes = append([]byte{byte(length)}, lengthBytes...)
+		}
+		header = append([]byte{tag, 0x80 | byte(len(lengthBytes))}, lengthBytes...)
+	}
+	return append(header, content...)
+}
+
+// Encodes the given value as a minimal big-endian integer, with a leading zero if needed to keep it positive.
+func encodeBerUnsigned(value uint64) []byte {
+	var content []byte
+	for ; value > 0; value >>= 8 {
+		content = append([]byte{byte(value)}, content...)
+	}
+	if len(content) == 0 || content[0]&0x80 != 0 {
+		content = append([]byte{0}, content...)
+	}
+	return content
+}
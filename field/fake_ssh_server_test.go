@@ -0,0 +1,192 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+	"io"
+	"io/ioutil"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Handles a single command or interactive shell session on a fake SSH server. The command is empty for a shell
+// session, in which case the script is read from stdin. Returns the exit status.
+type fakeSshHandler func(command string, stdin io.Reader, stdout io.Writer) int
+
+// An in-process SSH server that accepts password logins and passes each command to the given handler.
+type fakeSshServer struct {
+	port     int
+	listener net.Listener
+	handler  fakeSshHandler
+	commands []string
+	mutex    sync.Mutex
+}
+
+func newFakeSshServer(t *testing.T, username, password string, handler fakeSshHandler) *fakeSshServer {
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, attempt []byte) (*ssh.Permissions, error) {
+			if conn.User() == username && string(attempt) == password {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("Invalid login for user '%s'.", conn.User())
+		},
+	}
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t, err)
+	signer, err := ssh.NewSignerFromKey(hostKey)
+	assert.Nil(t, err)
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	server := &fakeSshServer{port: listener.Addr().(*net.TCPAddr).Port, listener: listener, handler: handler}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.handleConnection(conn, config)
+		}
+	}()
+	return server
+}
+
+// Returns the commands (or shell scripts) run on the server so far.
+func (server *fakeSshServer) getCommands() []string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return append([]string(nil), server.commands...)
+}
+
+func (server *fakeSshServer) close() {
+	server.listener.Close()
+}
+
+func (server *fakeSshServer) handleConnection(conn net.Conn, config *ssh.ServerConfig) {
+	defer conn.Close()
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "Only sessions are supported.")
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go server.handleSession(channel, channelRequests)
+	}
+}
+
+func (server *fakeSshServer) handleSession(channel ssh.Channel, requests <-chan *ssh.Request) {
+	defer channel.Close()
+	for request := range requests {
+		var command string
+		switch request.Type {
+		case "exec":
+			var payload struct{ Command string }
+			if err := ssh.Unmarshal(request.Payload, &payload); err != nil {
+				request.Reply(false, nil)
+				continue
+			}
+			command = payload.Command
+		case "shell":
+		default:
+			request.Reply(false, nil)
+			continue
+		}
+		request.Reply(true, nil)
+
+		var stdin io.Reader = channel
+		if command == "" {
+			// Read the whole script up front so that it can be recorded.
+			script, _ := ioutil.ReadAll(channel)
+			stdin = bytes.NewReader(script)
+			command = string(script)
+		}
+		server.mutex.Lock()
+		server.commands = append(server.commands, command)
+		server.mutex.Unlock()
+
+		status := server.handler(command, stdin, channel)
+		channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{uint32(status)}))
+		return
+	}
+}
+
+// Mimics the shell of an OpenWRT access point, keeping the wireless configuration file in memory. The associated
+// clients are given as the "iwinfo assoclist" output for each SSID.
+type fakeOpenWrt struct {
+	wirelessConfig string
+	assocLists     map[string]string
+	mutex          sync.Mutex
+}
+
+var fakeOpenWrtUploadRe = regexp.MustCompile("(?s)^cat <<ENDCONFIG > /etc/config/wireless && wifi radio\\d\n(.*)" +
+	"ENDCONFIG\n$")
+var fakeOpenWrtDeviceRe = regexp.MustCompile("(?s)config wifi-device '(radio\\d)'.*?option channel '(\\d+)'")
+var fakeOpenWrtIfaceRe = regexp.MustCompile("(?s)config wifi-iface\\s+option device '(radio\\d)'.*?" +
+	"option ssid '(.*?)'")
+
+func (ap *fakeOpenWrt) handleCommand(command string, stdin io.Reader, stdout io.Writer) int {
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+
+	if match := fakeOpenWrtUploadRe.FindStringSubmatch(command); match != nil {
+		ap.wirelessConfig = match[1]
+		return 0
+	}
+	switch command {
+	case "cat /etc/config/wireless":
+		fmt.Fprint(stdout, ap.wirelessConfig)
+		return 0
+	case "iwinfo", openWrtStatusCommand:
+		channels := make(map[string]string)
+		for _, match := range fakeOpenWrtDeviceRe.FindAllStringSubmatch(ap.wirelessConfig, -1) {
+			channels[match[1]] = match[2]
+		}
+
+		// Number the interfaces on each radio the same way that OpenWRT does.
+		interfaceCounts := make(map[string]int)
+		var sections string
+		for _, match := range fakeOpenWrtIfaceRe.FindAllStringSubmatch(ap.wirelessConfig, -1) {
+			radio := strings.TrimPrefix(match[1], "radio")
+			iface := "wlan" + radio
+			if interfaceCounts[radio] > 0 {
+				iface = fmt.Sprintf("wlan%s-%d", radio, interfaceCounts[radio])
+			}
+			interfaceCounts[radio]++
+			fmt.Fprintf(stdout, "%s     ESSID: \"%s\"\n          Access Point: 62:38:E0:12:6B:17\n"+
+				"          Mode: Master  Channel: %s\n\n", iface, match[2], channels[match[1]])
+
+			assocList := ap.assocLists[match[2]]
+			if assocList == "" {
+				assocList = "No station connected\n"
+			}
+			sections += fmt.Sprintf("%s%s\n%sSurvey data from %s\n\tfrequency:\t\t\t5785 MHz [in use]\n"+
+				"\tchannel active time:\t\t1000 ms\n\tchannel busy time:\t\t250 ms\n", statusSectionPrefix, iface,
+				assocList, iface)
+		}
+		if command == openWrtStatusCommand {
+			fmt.Fprint(stdout, sections)
+		}
+		return 0
+	}
+	fmt.Fprintf(stdout, "ash: %s: not found\n", command)
+	return 127
+}
@@ -0,0 +1,271 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const fakeSwitchInvalidInput = "% Invalid input detected at '^' marker.\n"
+
+// Mimics the IOS command line of a Cisco switch closely enough to exercise the switch driver, keeping a running
+// configuration that reflects the commands applied to it.
+type fakeCiscoSwitch struct {
+	username          string
+	password          string
+	enablePassword    string
+	failCommand       string
+	interfaces        map[int]string
+	dhcpPools         map[string][]string
+	excludedAddresses map[string]bool
+	accessLists       map[int][]string
+	startupConfig     string
+	listener          net.Listener
+	mutex             sync.Mutex
+}
+
+var fakeSwitchInterfaceRe = regexp.MustCompile("^interface Vlan(\\d+)$")
+var fakeSwitchAccessListRe = regexp.MustCompile("^(no )?access-list (\\d+)( .*)?$")
+
+func newFakeCiscoSwitch(username, password, enablePassword string) *fakeCiscoSwitch {
+	return &fakeCiscoSwitch{username: username, password: password, enablePassword: enablePassword,
+		interfaces: map[int]string{adminVlan: "10.0.100.2 255.255.255.0"}, dhcpPools: make(map[string][]string),
+		excludedAddresses: make(map[string]bool), accessLists: make(map[int][]string)}
+}
+
+// Starts accepting Telnet connections on a free local port and returns the port number.
+func (sw *fakeCiscoSwitch) serveTelnet(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	sw.listener = listener
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.SetDeadline(time.Now().Add(time.Second))
+				sw.runSession(conn, conn, true)
+			}()
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// Starts an SSH server on a free local port whose shell sessions run the switch CLI.
+func (sw *fakeCiscoSwitch) serveSsh(t *testing.T) *fakeSshServer {
+	return newFakeSshServer(t, sw.username, sw.password, func(command string, stdin io.Reader, stdout io.Writer) int {
+		sw.runSession(stdin, stdout, false)
+		return 0
+	})
+}
+
+func (sw *fakeCiscoSwitch) close() {
+	if sw.listener != nil {
+		sw.listener.Close()
+	}
+}
+
+// Runs a CLI session, reading commands until the session is exited. Telnet sessions have to log in and then enter
+// privileged mode, whereas SSH sessions are already authenticated with privileged access.
+func (sw *fakeCiscoSwitch) runSession(input io.Reader, output io.Writer, login bool) {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+
+	reader := bufio.NewReader(input)
+	readLine := func() (string, bool) {
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return "", false
+		}
+		return strings.TrimSpace(line), true
+	}
+
+	if login {
+		if sw.username != "" {
+			fmt.Fprint(output, "Username: ")
+			if username, _ := readLine(); username != sw.username {
+				fmt.Fprint(output, "\n% Login invalid\n")
+				return
+			}
+		}
+		fmt.Fprint(output, "Password: ")
+		if password, _ := readLine(); password != sw.password {
+			fmt.Fprint(output, "\n% Login invalid\n")
+			return
+		}
+	}
+
+	privileged := !login
+	mode := "exec"
+	var currentVlan int
+	var currentPool string
+	for {
+		line, ok := readLine()
+		if !ok {
+			return
+		}
+		if line == "" {
+			continue
+		}
+		if sw.failCommand != "" && strings.HasPrefix(line, sw.failCommand) {
+			fmt.Fprint(output, fakeSwitchInvalidInput)
+			continue
+		}
+
+		if mode == "exec" {
+			switch line {
+			case "enable":
+				fmt.Fprint(output, "Password: ")
+				if password, _ := readLine(); password == sw.enablePassword {
+					privileged = true
+				} else {
+					fmt.Fprint(output, "\n% Access denied\n")
+				}
+			case "terminal length 0":
+			case "show running-config":
+				if !privileged {
+					fmt.Fprint(output, fakeSwitchInvalidInput)
+					continue
+				}
+				fmt.Fprint(output, sw.runningConfig())
+			case "config terminal":
+				if !privileged {
+					fmt.Fprint(output, fakeSwitchInvalidInput)
+					continue
+				}
+				mode = "config"
+			case "copy running-config startup-config":
+				if !privileged {
+					fmt.Fprint(output, fakeSwitchInvalidInput)
+					continue
+				}
+				fmt.Fprint(output, "Destination filename [startup-config]? ")
+				readLine()
+				sw.startupConfig = sw.runningConfig()
+			case "exit":
+				return
+			default:
+				fmt.Fprint(output, fakeSwitchInvalidInput)
+			}
+			continue
+		}
+
+		// Commands specific to the current configuration sub-mode.
+		if mode == "config-if" {
+			if line == "no ip address" {
+				sw.interfaces[currentVlan] = ""
+				continue
+			} else if strings.HasPrefix(line, "ip address ") {
+				sw.interfaces[currentVlan] = strings.TrimPrefix(line, "ip address ")
+				continue
+			}
+		} else if mode == "dhcp-config" {
+			if strings.HasPrefix(line, "network ") || strings.HasPrefix(line, "default-router ") ||
+				strings.HasPrefix(line, "lease ") {
+				sw.dhcpPools[currentPool] = append(sw.dhcpPools[currentPool], line)
+				continue
+			}
+		}
+
+		// Like IOS, accept global configuration commands from within any sub-mode.
+		if match := fakeSwitchInterfaceRe.FindStringSubmatch(line); match != nil {
+			currentVlan, _ = strconv.Atoi(match[1])
+			if _, ok := sw.interfaces[currentVlan]; !ok {
+				sw.interfaces[currentVlan] = ""
+			}
+			mode = "config-if"
+		} else if strings.HasPrefix(line, "ip dhcp pool ") {
+			currentPool = strings.TrimPrefix(line, "ip dhcp pool ")
+			sw.dhcpPools[currentPool] = nil
+			mode = "dhcp-config"
+		} else if strings.HasPrefix(line, "no ip dhcp pool ") {
+			delete(sw.dhcpPools, strings.TrimPrefix(line, "no ip dhcp pool "))
+			mode = "config"
+		} else if strings.HasPrefix(line, "ip dhcp excluded-address ") {
+			sw.excludedAddresses[strings.TrimPrefix(line, "ip dhcp excluded-address ")] = true
+			mode = "config"
+		} else if match := fakeSwitchAccessListRe.FindStringSubmatch(line); match != nil {
+			number, _ := strconv.Atoi(match[2])
+			if match[1] != "" {
+				delete(sw.accessLists, number)
+			} else {
+				sw.accessLists[number] = append(sw.accessLists[number], line)
+			}
+			mode = "config"
+		} else if line == "exit" && mode != "config" {
+			mode = "config"
+		} else if line == "end" {
+			mode = "exec"
+		} else {
+			fmt.Fprint(output, fakeSwitchInvalidInput)
+		}
+	}
+}
+
+// Renders the current state of the switch in the same format as "show running-config" on IOS.
+func (sw *fakeCiscoSwitch) runningConfig() string {
+	config := "Building configuration...\n\nCurrent configuration : 4096 bytes\n!\nhostname FieldSwitch\n!\n"
+
+	var excludedAddresses []string
+	for excludedAddress := range sw.excludedAddresses {
+		excludedAddresses = append(excludedAddresses, excludedAddress)
+	}
+	sort.Strings(excludedAddresses)
+	for _, excludedAddress := range excludedAddresses {
+		config += fmt.Sprintf("ip dhcp excluded-address %s\n", excludedAddress)
+	}
+	config += "!\n"
+
+	var pools []string
+	for pool := range sw.dhcpPools {
+		pools = append(pools, pool)
+	}
+	sort.Strings(pools)
+	for _, pool := range pools {
+		config += fmt.Sprintf("ip dhcp pool %s\n", pool)
+		for _, line := range sw.dhcpPools[pool] {
+			config += fmt.Sprintf(" %s\n", line)
+		}
+		config += "!\n"
+	}
+
+	var vlans []int
+	for vlan := range sw.interfaces {
+		vlans = append(vlans, vlan)
+	}
+	sort.Ints(vlans)
+	for _, vlan := range vlans {
+		config += fmt.Sprintf("interface Vlan%d\n", vlan)
+		if address := sw.interfaces[vlan]; address != "" {
+			config += fmt.Sprintf(" ip address %s\n", address)
+		} else {
+			config += " no ip address\n"
+		}
+		config += "!\n"
+	}
+
+	var accessLists []int
+	for accessList := range sw.accessLists {
+		accessLists = append(accessLists, accessList)
+	}
+	sort.Ints(accessLists)
+	for _, accessList := range accessLists {
+		config += strings.Join(sw.accessLists[accessList], "\n") + "\n"
+	}
+	return config + "!\nend\n"
+}
@@ -0,0 +1,217 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpenWrtAccessPointOverSsh(t *testing.T) {
+	model.BaseDir = ".."
+	fakeAp := &fakeOpenWrt{}
+	server := newFakeSshServer(t, "root", "password", fakeAp.handleCommand)
+	defer server.close()
+	ap := NewAccessPoint("openwrt", "127.0.0.1", "root", "password", 157, 149, "blorpy").(*OpenWrtAccessPoint)
+	ap.port = server.port
+
+	assert.Nil(t, ap.ConfigureTeamWifi(&model.Team{Id: 254, WpaKey: "aaaaaaaa"}, nil, nil, nil, nil,
+		&model.Team{Id: 1114, WpaKey: "bbbbbbbb"}))
+	commands := server.getCommands()
+	if assert.Equal(t, 1, len(commands)) {
+		assert.True(t, strings.HasPrefix(commands[0], "cat <<ENDCONFIG > /etc/config/wireless && wifi radio0\n"))
+	}
+	assert.Contains(t, fakeAp.wirelessConfig, "option ssid '254'")
+
+	config, err := ap.GetTeamWifiConfig()
+	assert.Nil(t, err)
+	assert.Equal(t, fakeAp.wirelessConfig, config)
+	status, err := ap.GetStatus()
+	assert.Nil(t, err)
+	var ssids []string
+	for _, network := range status.Networks {
+		ssids = append(ssids, network.Ssid)
+		if network.Ssid == "254" {
+			assert.Equal(t, 157, network.Channel)
+		}
+	}
+	assert.Equal(t, []string{"Cheesy Arena", "254", "1114"}, ssids)
+
+	// A command that fails on the access point should be reported.
+	_, err = ap.runCommand("reboot now")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "127")
+	}
+
+	// A bad password should be rejected before any command is run.
+	ap.password = "wrong"
+	assert.NotNil(t, ap.ConfigureAdminWifi())
+	assert.Equal(t, 4, len(server.getCommands()))
+}
+
+func TestCiscoSwitchOverTelnet(t *testing.T) {
+	fakeSwitch := newFakeCiscoSwitch("", "password", "password")
+	port := fakeSwitch.serveTelnet(t)
+	defer fakeSwitch.close()
+	ns := NewNetworkSwitch("cisco", "telnet", "127.0.0.1", "", "password", false)
+	ns.port = port
+
+	assert.Nil(t, ns.ConfigureTeamEthernet(&model.Team{Id: 254}, nil, nil, nil, &model.Team{Id: 1114}, nil))
+	teamVlans, err := ns.GetTeamVlans()
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]int{254: 10, 1114: 50}, teamVlans)
+	assert.Contains(t, fakeSwitch.dhcpPools, "dhcp10")
+	assert.Equal(t, "10.0.100.2 255.255.255.0", fakeSwitch.interfaces[adminVlan])
+	assert.Equal(t, fakeSwitch.runningConfig(), fakeSwitch.startupConfig)
+
+	// A failed command should cause the affected VLANs to be rolled back.
+	fakeSwitch.failCommand = "access-list 130 permit"
+	err = ns.ConfigureTeamEthernet(&model.Team{Id: 254}, nil, &model.Team{Id: 3310}, nil, nil, nil)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "rolled back to the previous configuration")
+	}
+	fakeSwitch.failCommand = ""
+	teamVlans, err = ns.GetTeamVlans()
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]int{254: 10, 1114: 50}, teamVlans)
+
+	// A bad password should be reported without changing anything.
+	ns.password = "wrong"
+	assert.NotNil(t, ns.ConfigureTeamEthernet(&model.Team{Id: 118}, nil, nil, nil, nil, nil))
+	assert.Equal(t, "10.2.54.61 255.255.255.0", fakeSwitch.interfaces[10])
+}
+
+func TestCiscoSwitchOverSsh(t *testing.T) {
+	fakeSwitch := newFakeCiscoSwitch("admin", "password", "")
+	server := fakeSwitch.serveSsh(t)
+	defer server.close()
+	ns := NewNetworkSwitch("cisco", "ssh", "127.0.0.1", "admin", "password", false)
+	ns.port = server.port
+
+	assert.Nil(t, ns.ConfigureTeamEthernet(nil, &model.Team{Id: 1503}, nil, nil, nil, &model.Team{Id: 148}))
+	teamVlans, err := ns.GetTeamVlans()
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]int{1503: 20, 148: 60}, teamVlans)
+	commands := server.getCommands()
+	if assert.Equal(t, 3, len(commands)) {
+		assert.True(t, strings.HasPrefix(commands[1], "terminal length 0\nconfig terminal\n"))
+	}
+}
+
+func TestBandwidthMonitorOverSnmp(t *testing.T) {
+	arena := setupTestArena(t)
+	agent := newFakeSnmpAgent(t, "password")
+	defer agent.close()
+	bandwidthMonitorSnmpPort = agent.port
+	arena.EventSettings.SwitchAddress = "127.0.0.1"
+	arena.EventSettings.SwitchPassword = "password"
+	arena.EventSettings.NetworkSecurityEnabled = true
+	arena.EventSettings.BandwidthMonitoringEnabled = true
+	red1 := &DriverStationConnection{TeamId: 254}
+	arena.AllianceStations["R1"].DsConn = red1
+	for i, port := range []int{6, 8, 10, 12, 14, 16} {
+		interfaceIndex := arena.networkSwitch.SnmpInterfaceIndex(port)
+		agent.setCounter(fmt.Sprintf("%s.%d", toRobotBytesOid, interfaceIndex), uint32(1000*i))
+		agent.setCounter(fmt.Sprintf("%s.%d", fromRobotBytesOid, interfaceIndex), uint32(2000*i))
+	}
+	monitor := BandwidthMonitor{arena: arena}
+
+	// The first reading only establishes a baseline.
+	assert.Nil(t, monitor.poll())
+	assert.Equal(t, 0.0, red1.MBpsToRobot)
+
+	interfaceIndex := arena.networkSwitch.SnmpInterfaceIndex(arena.EventSettings.Red1SwitchPort)
+	agent.setCounter(fmt.Sprintf("%s.%d", toRobotBytesOid, interfaceIndex), 1024*128)
+	agent.setCounter(fmt.Sprintf("%s.%d", fromRobotBytesOid, interfaceIndex), 1024*128*4)
+	time.Sleep(100 * time.Millisecond)
+	assert.Nil(t, monitor.poll())
+	assert.True(t, red1.MBpsToRobot > 1 && red1.MBpsToRobot <= 10)
+	assert.InDelta(t, 4*red1.MBpsToRobot, red1.MBpsFromRobot, 0.001)
+	assert.True(t, red1.BandwidthAlert)
+}
+
+func TestSetupNetworkEndToEnd(t *testing.T) {
+	arena := setupTestArena(t)
+	networkVerifyInterval = time.Millisecond
+	fakeAp := &fakeOpenWrt{}
+	apServer := newFakeSshServer(t, "root", "password", fakeAp.handleCommand)
+	defer apServer.close()
+	fakeSwitch := newFakeCiscoSwitch("", "password", "password")
+	switchPort := fakeSwitch.serveTelnet(t)
+	defer fakeSwitch.close()
+
+	arena.EventSettings.NetworkSecurityEnabled = true
+	arena.EventSettings.ApAddress = "127.0.0.1"
+	arena.EventSettings.ApUsername = "root"
+	arena.EventSettings.ApPassword = "password"
+	arena.EventSettings.SwitchAddress = "127.0.0.1"
+	arena.EventSettings.SwitchPassword = "password"
+	assert.Nil(t, arena.Database.SaveEventSettings(arena.EventSettings))
+	assert.Nil(t, arena.LoadSettings())
+	arena.accessPoint.(*OpenWrtAccessPoint).port = apServer.port
+	arena.networkSwitch.port = switchPort
+	for _, teamId := range []int{254, 1114, 2056, 148, 1503, 3310, 118} {
+		assert.Nil(t, arena.Database.CreateTeam(&model.Team{Id: teamId, WpaKey: fmt.Sprintf("%08d", teamId)}))
+	}
+
+	loadMatchAndWait := func(match *model.Match) {
+		assert.Nil(t, arena.Database.CreateMatch(match))
+		assert.Nil(t, arena.LoadMatch(match))
+		for i := 0; i < 200; i++ {
+			done := true
+			for _, allianceStation := range arena.GetStatus().AllianceStations {
+				if allianceStation.Team == nil {
+					continue
+				}
+				for _, status := range []NetworkStatus{allianceStation.WifiStatus, allianceStation.EthernetStatus} {
+					if status.State != NetworkConfigured && status.State != NetworkFailed {
+						done = false
+					}
+				}
+			}
+			if done {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	loadMatchAndWait(&model.Match{Type: "practice", DisplayName: "1", Red1: 254, Red2: 1114, Red3: 2056,
+		Blue1: 148, Blue2: 1503, Blue3: 3310})
+	for station, allianceStation := range arena.GetStatus().AllianceStations {
+		assert.Equal(t, NetworkStatus{NetworkConfigured, ""}, allianceStation.WifiStatus, station)
+		assert.Equal(t, NetworkStatus{NetworkConfigured, ""}, allianceStation.EthernetStatus, station)
+	}
+	for _, teamId := range []int{254, 1114, 2056, 148, 1503, 3310} {
+		assert.Contains(t, fakeAp.wirelessConfig, fmt.Sprintf("option ssid '%d'", teamId))
+	}
+	teamVlans, err := arena.networkSwitch.GetTeamVlans()
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]int{254: 10, 1114: 20, 2056: 30, 148: 40, 1503: 50, 3310: 60}, teamVlans)
+
+	// Check that the robot radios associated with the team networks are picked up.
+	fakeAp.mutex.Lock()
+	fakeAp.assocLists = map[string]string{"1114": "00:80:2F:17:E7:A1  -58 dBm / -95 dBm (SNR 37)  0 ms ago\n" +
+		"\tRX: 24.0 MBit/s    100 Pkts.\n\tTX: 65.0 MBit/s, MCS 7, 20MHz    200 Pkts.\n"}
+	fakeAp.mutex.Unlock()
+	assert.Nil(t, arena.pollAccessPoint())
+	assert.Equal(t, WirelessStatus{true, true, "00:80:2F:17:E7:A1", -58, 65, 24, 25},
+		arena.AllianceStations["R2"].Wireless)
+	assert.Equal(t, WirelessStatus{SsidActive: true, ChannelUtilization: 25}, arena.AllianceStations["R1"].Wireless)
+
+	// Loading the next match should replace only the teams that changed and remove those that are gone.
+	loadMatchAndWait(&model.Match{Type: "practice", DisplayName: "2", Red1: 254, Red2: 118, Blue1: 148})
+	assert.Equal(t, NetworkStatus{NetworkConfigured, ""}, arena.GetStatus().AllianceStations["R2"].WifiStatus)
+	assert.Equal(t, NetworkStatus{NetworkConfigured, ""}, arena.GetStatus().AllianceStations["R2"].EthernetStatus)
+	assert.Equal(t, NetworkStatus{}, arena.GetStatus().AllianceStations["B3"].EthernetStatus)
+	assert.NotContains(t, fakeAp.wirelessConfig, "option ssid '3310'")
+	teamVlans, err = arena.networkSwitch.GetTeamVlans()
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]int{254: 10, 118: 20, 148: 40}, teamVlans)
+	assert.Equal(t, "", fakeSwitch.interfaces[60])
+}
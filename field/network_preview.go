@@ -0,0 +1,186 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for previewing the team network configuration for a match and comparing it against what is currently on
+// the networking hardware, without applying anything.
+
+package field
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"strings"
+)
+
+const (
+	DiffUnchanged = " "
+	DiffAdded     = "+"
+	DiffRemoved   = "-"
+)
+
+// Everything that would be sent to the access point and switch for a given set of teams, along with how it differs
+// from the current state of each device.
+type NetworkConfigPreview struct {
+	Teams              map[string]*model.Team
+	AccessPointConfig  string
+	AccessPointCurrent string
+	AccessPointDiff    []ConfigDiffLine
+	AccessPointError   string
+	SwitchCommands     string
+	SwitchCurrent      string
+	SwitchDiff         []ConfigDiffLine
+	SwitchError        string
+}
+
+type ConfigDiffLine struct {
+	Change string
+	Text   string
+}
+
+// Generates the network configuration for the teams in the given match, or for the teams currently in the alliance
+// stations if the match is nil. Device errors are recorded in the preview rather than returned, so that as much as
+// possible can still be shown.
+func (arena *Arena) PreviewNetworkConfig(match *model.Match) (*NetworkConfigPreview, error) {
+	preview := &NetworkConfigPreview{Teams: make(map[string]*model.Team)}
+	if match == nil {
+		for station, allianceStation := range arena.AllianceStations {
+			preview.Teams[station] = allianceStation.Team
+		}
+	} else {
+		stationTeamIds := map[string]int{"R1": match.Red1, "R2": match.Red2, "R3": match.Red3, "B1": match.Blue1,
+			"B2": match.Blue2, "B3": match.Blue3}
+		for station, teamId := range stationTeamIds {
+			if teamId == 0 {
+				preview.Teams[station] = nil
+				continue
+			}
+			team, err := arena.Database.GetTeamById(teamId)
+			if err != nil {
+				return nil, err
+			}
+			if team == nil {
+				team = &model.Team{Id: teamId}
+			}
+			preview.Teams[station] = team
+		}
+	}
+	teams := preview.Teams
+
+	// Generate the access point config and compare it to the file currently on the device.
+	var err error
+	preview.AccessPointConfig, err = arena.accessPoint.GenerateTeamWifiConfig(teams["R1"], teams["R2"], teams["R3"],
+		teams["B1"], teams["B2"], teams["B3"])
+	if err != nil {
+		preview.AccessPointError = err.Error()
+	} else {
+		preview.AccessPointCurrent, err = arena.accessPoint.GetTeamWifiConfig()
+		if err != nil {
+			preview.AccessPointError = fmt.Sprintf("Unable to read the current configuration: %s", err.Error())
+		}
+		preview.AccessPointDiff = diffConfigs(preview.AccessPointCurrent, preview.AccessPointConfig)
+	}
+
+	// Generate the full switch session and compare it to the running configuration read back from the device.
+	preview.SwitchCurrent, preview.SwitchCommands, err = arena.networkSwitch.PreviewTeamEthernet(teams["R1"],
+		teams["R2"], teams["R3"], teams["B1"], teams["B2"], teams["B3"])
+	if err != nil {
+		preview.SwitchError = fmt.Sprintf("Unable to read the current configuration; the commands shown assume "+
+			"that no team VLANs are configured: %s", err.Error())
+	}
+	preview.SwitchDiff = diffConfigs(preview.SwitchCurrent, preview.SwitchCommands)
+
+	return preview, nil
+}
+
+// Returns the preview as plain text suitable for saving or printing.
+func (preview *NetworkConfigPreview) ExportText() string {
+	var buffer bytes.Buffer
+	buffer.WriteString("### Teams\n")
+	for _, station := range []string{"R1", "R2", "R3", "B1", "B2", "B3"} {
+		if team := preview.Teams[station]; team != nil {
+			buffer.WriteString(fmt.Sprintf("%s: %d (VLAN %d)\n", station, team.Id, stationVlans[station]))
+		} else {
+			buffer.WriteString(fmt.Sprintf("%s: empty\n", station))
+		}
+	}
+
+	buffer.WriteString("\n### Access point configuration\n")
+	if preview.AccessPointError != "" {
+		buffer.WriteString(fmt.Sprintf("Error: %s\n", preview.AccessPointError))
+	}
+	buffer.WriteString(preview.AccessPointConfig)
+	buffer.WriteString("\n### Access point changes\n")
+	writeDiff(&buffer, preview.AccessPointDiff)
+
+	buffer.WriteString("\n### Switch commands\n")
+	if preview.SwitchError != "" {
+		buffer.WriteString(fmt.Sprintf("Error: %s\n", preview.SwitchError))
+	}
+	buffer.WriteString(preview.SwitchCommands)
+	buffer.WriteString("\n### Switch changes\n")
+	writeDiff(&buffer, preview.SwitchDiff)
+
+	return buffer.String()
+}
+
+func writeDiff(buffer *bytes.Buffer, diff []ConfigDiffLine) {
+	for _, line := range diff {
+		buffer.WriteString(fmt.Sprintf("%s %s\n", line.Change, line.Text))
+	}
+}
+
+// Returns a line-by-line diff that transforms the old text into the new text, based on their longest common
+// subsequence of lines.
+func diffConfigs(oldText, newText string) []ConfigDiffLine {
+	oldLines := splitLines(oldText)
+	newLines := splitLines(newText)
+
+	// Build the table of longest common subsequence lengths for every pair of suffixes.
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var diff []ConfigDiffLine
+	i, j := 0, 0
+	for i < len(oldLines) && j < len(newLines) {
+		if oldLines[i] == newLines[j] {
+			diff = append(diff, ConfigDiffLine{DiffUnchanged, oldLines[i]})
+			i++
+			j++
+		} else if lcs[i+1][j] >= lcs[i][j+1] {
+			diff = append(diff, ConfigDiffLine{DiffRemoved, oldLines[i]})
+			i++
+		} else {
+			diff = append(diff, ConfigDiffLine{DiffAdded, newLines[j]})
+			j++
+		}
+	}
+	for ; i < len(oldLines); i++ {
+		diff = append(diff, ConfigDiffLine{DiffRemoved, oldLines[i]})
+	}
+	for ; j < len(newLines); j++ {
+		diff = append(diff, ConfigDiffLine{DiffAdded, newLines[j]})
+	}
+	return diff
+}
+
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(strings.Replace(text, "\r\n", "\n", -1), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
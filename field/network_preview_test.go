@@ -0,0 +1,72 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	assert.Nil(t, diffConfigs("", ""))
+	assert.Equal(t, []ConfigDiffLine{{DiffAdded, "a"}, {DiffAdded, "b"}}, diffConfigs("", "a\nb\n"))
+	assert.Equal(t, []ConfigDiffLine{{DiffRemoved, "a"}, {DiffRemoved, "b"}}, diffConfigs("a\nb\n", ""))
+	assert.Equal(t, []ConfigDiffLine{{DiffUnchanged, "a"}, {DiffRemoved, "b"}, {DiffAdded, "x"}, {DiffUnchanged, "c"},
+		{DiffAdded, "d"}}, diffConfigs("a\nb\nc\n", "a\nx\nc\nd"))
+	assert.Equal(t, []ConfigDiffLine{{DiffUnchanged, "a"}, {DiffUnchanged, "b"}}, diffConfigs("a\r\nb\r\n", "a\nb\n"))
+}
+
+func TestPreviewNetworkConfig(t *testing.T) {
+	arena := setupTestArena(t)
+
+	arena.accessPoint = &fakeAccessPoint{config: "ssid 254\nssid 1114\n", currentConfig: "ssid 254\nssid 9999\n"}
+	arena.networkSwitch = NewNetworkSwitch("cisco", "telnet", "127.0.0.1", "", "password", false)
+	arena.networkSwitch.port = 9050
+	var commands []string
+	mockTelnet(t, arena.networkSwitch.port, []string{"interface Vlan10\nip address 10.2.54.61\ninterface Vlan60\n" +
+		"ip address 10.99.99.61\n"}, &commands)
+	arena.Database.CreateTeam(&model.Team{Id: 254, WpaKey: "aaaaaaaa"})
+	match := model.Match{Type: "qualification", DisplayName: "1", Red1: 254, Blue3: 1114}
+	arena.Database.CreateMatch(&match)
+
+	preview, err := arena.PreviewNetworkConfig(&match)
+	assert.Nil(t, err)
+	assert.Equal(t, 254, preview.Teams["R1"].Id)
+	assert.Equal(t, 1114, preview.Teams["B3"].Id)
+	assert.Nil(t, preview.Teams["R2"])
+	assert.Equal(t, "ssid 254\nssid 1114\n", preview.AccessPointConfig)
+	assert.Equal(t, []ConfigDiffLine{{DiffUnchanged, "ssid 254"}, {DiffRemoved, "ssid 9999"}, {DiffAdded, "ssid 1114"}},
+		preview.AccessPointDiff)
+	assert.Equal(t, "", preview.AccessPointError)
+	assert.True(t, strings.HasPrefix(preview.SwitchCommands, "enable\n********\nterminal length 0\nconfig terminal\n"))
+	assert.Contains(t, preview.SwitchCommands, "interface Vlan60\nip address 10.11.14.61")
+	assert.Contains(t, preview.SwitchCommands, "end\ncopy running-config startup-config\n")
+	assert.NotContains(t, preview.SwitchCommands, "interface Vlan10\n")
+	assert.NotContains(t, preview.SwitchCommands, "password")
+	assert.Equal(t, "interface Vlan10\nip address 10.2.54.61\ninterface Vlan60\nip address 10.99.99.61\n",
+		preview.SwitchCurrent)
+	assert.Contains(t, preview.SwitchDiff, ConfigDiffLine{DiffRemoved, "interface Vlan10"})
+	assert.Contains(t, preview.SwitchDiff, ConfigDiffLine{DiffRemoved, "ip address 10.99.99.61"})
+	assert.Contains(t, preview.SwitchDiff, ConfigDiffLine{DiffUnchanged, "interface Vlan60"})
+	assert.Contains(t, preview.SwitchDiff, ConfigDiffLine{DiffAdded, "ip address 10.11.14.61 255.255.255.0"})
+	assert.Equal(t, "", preview.SwitchError)
+	assert.Empty(t, commands)
+
+	// Check that the switch commands are still generated from scratch if it can't be reached.
+	arena.AllianceStations["R2"].Team = &model.Team{Id: 148}
+	preview, err = arena.PreviewNetworkConfig(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 148, preview.Teams["R2"].Id)
+	assert.Contains(t, preview.SwitchError, "Unable to read the current configuration")
+	assert.Contains(t, preview.SwitchCommands, "interface Vlan20\nip address 10.1.48.61")
+	assert.Equal(t, "", preview.SwitchCurrent)
+	if assert.NotEmpty(t, preview.SwitchDiff) {
+		assert.Equal(t, ConfigDiffLine{DiffAdded, "enable"}, preview.SwitchDiff[0])
+	}
+	text := preview.ExportText()
+	assert.Contains(t, text, "R2: 148 (VLAN 20)\nR3: empty\n")
+	assert.Contains(t, text, "### Switch changes\n+ enable\n+ ********\n")
+}
@@ -1,7 +1,8 @@
 // Copyright 2014 Team 254. All Rights Reserved.
 // Author: pat@patfairbank.com (Patrick Fairbank)
 //
-// Methods for configuring a Cisco Switch 3500-series switch for team VLANs.
+// Methods for configuring a managed switch for team VLANs. The vendor-specific CLI syntax is handled by a driver and
+// the switch can be reached over either Telnet or SSH.
 
 package field
 
@@ -10,26 +11,91 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/Team254/cheesy-arena/model"
+	"golang.org/x/crypto/ssh"
+	"log"
 	"net"
-	"regexp"
-	"strconv"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 )
 
-const switchTelnetPort = 23
+const (
+	switchTelnetPort = 23
+	switchSshPort    = 22
+)
+
+// Types of switch hardware that have drivers, with their descriptions.
+var SwitchTypes = map[string]string{
+	"cisco":    "Cisco Catalyst 3500-series (IOS)",
+	"procurve": "Aruba/HP ProCurve (ArubaOS-Switch)",
+}
+
+// Encapsulates the vendor-specific CLI syntax for a type of switch.
+type switchDriver interface {
+	// Wraps the given commands into a complete CLI session to be run right after login.
+	sessionScript(commands string) string
+
+	// Wraps the given commands into a complete CLI session that runs them in global configuration mode.
+	configScript(commands string) string
+
+	// Returns the command that dumps the running configuration.
+	showConfigCommand() string
+
+	// Parses the running configuration into a map of currently-configured teams to VLANs.
+	parseTeamVlans(config string) map[int]int
+
+	// Returns the configuration commands to set up the given team's network on the given VLAN.
+	addTeamVlanCommands(teamId, vlan int) string
+
+	// Returns the configuration commands to remove any team network from the given VLAN.
+	removeTeamVlanCommands(vlan int) string
+
+	// Returns an error if the given CLI session output indicates that any command failed.
+	checkOutput(output string) error
+
+	// Returns the SNMP interface index (ifIndex) of the given physical port.
+	snmpInterfaceIndex(port int) int
+}
 
 type NetworkSwitch struct {
-	address  string
-	port     int
-	password string
-	mutex    sync.Mutex
+	address   string
+	port      int
+	username  string
+	password  string
+	transport string
+	driver    switchDriver
+	DryRun    bool
+	mutex     sync.Mutex
 }
 
-func NewNetworkSwitch(address, password string) *NetworkSwitch {
-	return &NetworkSwitch{address: address, port: switchTelnetPort, password: password}
+// Creates a switch of the given type that is reached over the given transport ("telnet" or "ssh"), defaulting to a
+// Cisco switch over Telnet.
+func NewNetworkSwitch(switchType, transport, address, username, password string, dryRun bool) *NetworkSwitch {
+	ns := &NetworkSwitch{address: address, port: switchTelnetPort, username: username, password: password,
+		transport: "telnet", DryRun: dryRun}
+	if transport == "ssh" {
+		ns.transport = transport
+		ns.port = switchSshPort
+	}
+
+	switch switchType {
+	case "procurve":
+		ns.driver = &proCurveSwitchDriver{}
+	default:
+		// The enable password needs to be given when logging in over Telnet, whereas the SSH user is expected to
+		// already have privileged access.
+		enablePassword := password
+		if ns.transport == "ssh" {
+			enablePassword = ""
+		}
+		ns.driver = &ciscoSwitchDriver{enablePassword: enablePassword}
+	}
+	return ns
 }
 
-// Sets up wired networks for the given set of teams.
+// Sets up wired networks for the given set of teams. If applying the changes fails partway, the affected VLANs are
+// rolled back to their previous configuration.
 func (ns *NetworkSwitch) ConfigureTeamEthernet(red1, red2, red3, blue1, blue2, blue3 *model.Team) error {
 	// Make sure multiple configurations aren't being set at the same time.
 	ns.mutex.Lock()
@@ -40,28 +106,80 @@ func (ns *NetworkSwitch) ConfigureTeamEthernet(red1, red2, red3, blue1, blue2, b
 	if err != nil {
 		return err
 	}
+	command, rollbackCommand := ns.generateTeamEthernetCommands(oldTeamVlans, red1, red2, red3, blue1, blue2, blue3)
+	if len(command) == 0 {
+		return nil
+	}
+	if ns.DryRun {
+		log.Printf("Switch dry run; would have applied the following configuration:\n%s", command)
+		return nil
+	}
+
+	// Run the overall command to do everything in a single session.
+	output, err := ns.runCommand(ns.driver.configScript(command))
+	if err == nil {
+		err = ns.driver.checkOutput(output)
+	}
+	if err != nil {
+		rollbackOutput, rollbackErr := ns.runCommand(ns.driver.configScript(rollbackCommand))
+		if rollbackErr == nil {
+			rollbackErr = ns.driver.checkOutput(rollbackOutput)
+		}
+		if rollbackErr != nil {
+			return fmt.Errorf("Failed to configure switch (%s) and then failed to roll back (%s).", err.Error(),
+				rollbackErr.Error())
+		}
+		return fmt.Errorf("Failed to configure switch and rolled back to the previous configuration: %s",
+			err.Error())
+	}
+
+	return nil
+}
+
+// Returns the running configuration read back from the switch and the complete CLI session that would be sent to
+// reconfigure it for the given set of teams, without applying it. The password is masked in the session. If the switch
+// can't be reached, the session is generated as if it had no team VLANs configured and the error is returned alongside
+// it.
+func (ns *NetworkSwitch) PreviewTeamEthernet(red1, red2, red3, blue1, blue2, blue3 *model.Team) (string, string,
+	error) {
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	config, err := ns.getRunningConfig()
+	command, _ := ns.generateTeamEthernetCommands(ns.driver.parseTeamVlans(config), red1, red2, red3, blue1, blue2,
+		blue3)
+	if len(command) == 0 {
+		return config, "", err
+	}
+	script := ns.driver.configScript(command)
+	if ns.password != "" {
+		script = strings.Replace(script, "\n"+ns.password+"\n", "\n********\n", -1)
+	}
+	return config, script, err
+}
+
+// Returns the commands to reconfigure the switch from the given current team VLANs to the given set of teams, as well
+// as the commands to restore the affected VLANs to their current state.
+func (ns *NetworkSwitch) generateTeamEthernetCommands(oldTeamVlans map[int]int, red1, red2, red3, blue1, blue2,
+	blue3 *model.Team) (string, string) {
+	oldVlanTeams := make(map[int]int)
+	remainingTeamVlans := make(map[int]int)
+	for team, vlan := range oldTeamVlans {
+		oldVlanTeams[vlan] = team
+		remainingTeamVlans[team] = vlan
+	}
+
 	addTeamVlansCommand := ""
+	var changedVlans []int
 	replaceTeamVlan := func(team *model.Team, vlan int) {
 		if team == nil {
 			return
 		}
-		if oldTeamVlans[team.Id] == vlan {
-			delete(oldTeamVlans, team.Id)
+		if remainingTeamVlans[team.Id] == vlan {
+			delete(remainingTeamVlans, team.Id)
 		} else {
-			addTeamVlansCommand += fmt.Sprintf(
-				"ip dhcp excluded-address 10.%d.%d.1 10.%d.%d.100\n"+
-					"no ip dhcp pool dhcp%d\n"+
-					"ip dhcp pool dhcp%d\n"+
-					"network 10.%d.%d.0 255.255.255.0\n"+
-					"default-router 10.%d.%d.61\n"+
-					"lease 7\n"+
-					"no access-list 1%d\n"+
-					"access-list 1%d permit ip 10.%d.%d.0 0.0.0.255 host %s\n"+
-					"access-list 1%d permit udp any eq bootpc any eq bootps\n"+
-					"interface Vlan%d\nip address 10.%d.%d.61 255.255.255.0\n",
-				team.Id/100, team.Id%100, team.Id/100, team.Id%100, vlan, vlan, team.Id/100, team.Id%100, team.Id/100,
-				team.Id%100, vlan, vlan, team.Id/100, team.Id%100, driverStationTcpListenAddress, vlan, vlan,
-				team.Id/100, team.Id%100)
+			addTeamVlansCommand += ns.driver.addTeamVlanCommands(team.Id, vlan)
+			changedVlans = append(changedVlans, vlan)
 		}
 	}
 	replaceTeamVlan(red1, red1Vlan)
@@ -72,54 +190,70 @@ func (ns *NetworkSwitch) ConfigureTeamEthernet(red1, red2, red3, blue1, blue2, b
 	replaceTeamVlan(blue3, blue3Vlan)
 
 	// Build the command to remove the team VLANs that are no longer needed.
+	var removedVlans []int
+	for _, vlan := range remainingTeamVlans {
+		removedVlans = append(removedVlans, vlan)
+	}
+	sort.Ints(removedVlans)
 	removeTeamVlansCommand := ""
-	for _, vlan := range oldTeamVlans {
-		removeTeamVlansCommand += fmt.Sprintf("interface Vlan%d\nno ip address\nno access-list 1%d\n", vlan, vlan)
+	for _, vlan := range removedVlans {
+		removeTeamVlansCommand += ns.driver.removeTeamVlanCommands(vlan)
 	}
 
-	// Build and run the overall command to do everything in a single telnet session.
-	command := removeTeamVlansCommand + addTeamVlansCommand
-	if len(command) > 0 {
-		_, err = ns.runConfigCommand(removeTeamVlansCommand + addTeamVlansCommand)
-		if err != nil {
-			return err
+	// Build the command to restore every affected VLAN to the team it previously had, if any.
+	changedVlans = append(changedVlans, removedVlans...)
+	sort.Ints(changedVlans)
+	rollbackCommand := ""
+	for i, vlan := range changedVlans {
+		if i > 0 && changedVlans[i-1] == vlan {
+			// The VLAN was both removed from its old team and given to a new one.
+			continue
+		}
+		rollbackCommand += ns.driver.removeTeamVlanCommands(vlan)
+		if team, ok := oldVlanTeams[vlan]; ok {
+			rollbackCommand += ns.driver.addTeamVlanCommands(team, vlan)
 		}
 	}
 
-	return nil
+	return removeTeamVlansCommand + addTeamVlansCommand, rollbackCommand
+}
+
+// Reads back the switch configuration and returns a map of currently-configured teams to VLANs.
+func (ns *NetworkSwitch) GetTeamVlans() (map[int]int, error) {
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+	return ns.getTeamVlans()
 }
 
 // Returns a map of currently-configured teams to VLANs.
 func (ns *NetworkSwitch) getTeamVlans() (map[int]int, error) {
-	// Get the entire config dump.
-	config, err := ns.runCommand("show running-config\n")
+	config, err := ns.getRunningConfig()
 	if err != nil {
 		return nil, err
 	}
+	return ns.driver.parseTeamVlans(config), nil
+}
 
-	// Parse out the team IDs and VLANs from the config dump.
-	re := regexp.MustCompile("(?s)interface Vlan(\\d\\d)\\s+ip address 10\\.(\\d+)\\.(\\d+)\\.61")
-	teamVlanMatches := re.FindAllStringSubmatch(config, -1)
-	if teamVlanMatches == nil {
-		// There are probably no teams currently configured.
-		return nil, nil
-	}
+// Returns the entire running configuration dump of the switch.
+func (ns *NetworkSwitch) getRunningConfig() (string, error) {
+	return ns.runCommand(ns.driver.sessionScript(ns.driver.showConfigCommand()))
+}
 
-	// Build the map of team to VLAN.
-	teamVlans := make(map[int]int)
-	for _, match := range teamVlanMatches {
-		team100s, _ := strconv.Atoi(match[2])
-		team1s, _ := strconv.Atoi(match[3])
-		team := int(team100s)*100 + team1s
-		vlan, _ := strconv.Atoi(match[1])
-		teamVlans[team] = vlan
+// Returns the SNMP interface index (ifIndex) used to query the traffic counters of the given physical port.
+func (ns *NetworkSwitch) SnmpInterfaceIndex(port int) int {
+	return ns.driver.snmpInterfaceIndex(port)
+}
+
+// Logs into the switch using the configured transport, runs the given CLI session script and returns its output.
+func (ns *NetworkSwitch) runCommand(script string) (string, error) {
+	if ns.transport == "ssh" {
+		return ns.runSshCommand(script)
 	}
-	return teamVlans, nil
+	return ns.runTelnetCommand(script)
 }
 
-// Logs into the switch via Telnet and runs the given command in user exec mode. Reads the output and
-// returns it as a string.
-func (ns *NetworkSwitch) runCommand(command string) (string, error) {
+// Logs into the switch via Telnet and runs the given CLI session script. Reads the output and returns it as a string.
+func (ns *NetworkSwitch) runTelnetCommand(script string) (string, error) {
 	// Open a Telnet connection to the switch.
 	conn, err := net.Dial("tcp", fmt.Sprintf("%s:%d", ns.address, ns.port))
 	if err != nil {
@@ -127,10 +261,13 @@ func (ns *NetworkSwitch) runCommand(command string) (string, error) {
 	}
 	defer conn.Close()
 
-	// Login to the AP, send the command, and log out all at once.
+	// Login to the switch, send the command, and log out all at once.
+	login := ns.password + "\n"
+	if ns.username != "" {
+		login = ns.username + "\n" + login
+	}
 	writer := bufio.NewWriter(conn)
-	_, err = writer.WriteString(fmt.Sprintf("%s\nenable\n%s\nterminal length 0\n%sexit\n", ns.password, ns.password,
-		command))
+	_, err = writer.WriteString(login + script)
 	if err != nil {
 		return "", err
 	}
@@ -148,8 +285,40 @@ func (ns *NetworkSwitch) runCommand(command string) (string, error) {
 	return reader.String(), nil
 }
 
-// Logs into the switch via Telnet and runs the given command in global configuration mode. Reads the output
-// and returns it as a string.
-func (ns *NetworkSwitch) runConfigCommand(command string) (string, error) {
-	return ns.runCommand(fmt.Sprintf("config terminal\n%send\ncopy running-config startup-config\n\n", command))
+// Logs into the switch via SSH and runs the given CLI session script in an interactive shell. Reads the output and
+// returns it as a string.
+func (ns *NetworkSwitch) runSshCommand(script string) (string, error) {
+	config := &ssh.ClientConfig{User: ns.username,
+		Auth:            []ssh.AuthMethod{ssh.Password(ns.password)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         connectTimeoutSec * time.Second}
+	conn, err := ssh.Dial("tcp", fmt.Sprintf("%s:%d", ns.address, ns.port), config)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	session, err := conn.NewSession()
+	if err != nil {
+		return "", err
+	}
+	defer session.Close()
+	var output bytes.Buffer
+	session.Stdin = strings.NewReader(script)
+	session.Stdout = &output
+
+	// Run the shell with a timeout; the script is responsible for logging out so that the shell exits.
+	commandChan := make(chan error, 1)
+	go func() {
+		err := session.Shell()
+		if err == nil {
+			err = session.Wait()
+		}
+		commandChan <- err
+	}()
+	select {
+	case err = <-commandChan:
+		return output.String(), err
+	case <-time.After(commandTimeoutSec * time.Second):
+		return "", fmt.Errorf("Switch SSH command timed out after %d seconds", commandTimeoutSec)
+	}
 }
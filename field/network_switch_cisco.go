@@ -0,0 +1,84 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Switch driver for a Cisco Catalyst 3500-series switch running IOS.
+
+package field
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type ciscoSwitchDriver struct {
+	enablePassword string
+}
+
+var ciscoTeamVlanRe = regexp.MustCompile("(?s)interface Vlan(\\d\\d)\\s+ip address 10\\.(\\d+)\\.(\\d+)\\.61")
+
+func (driver *ciscoSwitchDriver) sessionScript(commands string) string {
+	enable := ""
+	if driver.enablePassword != "" {
+		enable = fmt.Sprintf("enable\n%s\n", driver.enablePassword)
+	}
+	return fmt.Sprintf("%sterminal length 0\n%sexit\n", enable, commands)
+}
+
+// Saves the configuration once the changes have been applied so that they survive a restart of the switch.
+func (driver *ciscoSwitchDriver) configScript(commands string) string {
+	return driver.sessionScript(fmt.Sprintf("config terminal\n%send\ncopy running-config startup-config\n\n",
+		commands))
+}
+
+func (driver *ciscoSwitchDriver) showConfigCommand() string {
+	return "show running-config\n"
+}
+
+func (driver *ciscoSwitchDriver) parseTeamVlans(config string) map[int]int {
+	teamVlans := make(map[int]int)
+	for _, match := range ciscoTeamVlanRe.FindAllStringSubmatch(config, -1) {
+		team100s, _ := strconv.Atoi(match[2])
+		team1s, _ := strconv.Atoi(match[3])
+		vlan, _ := strconv.Atoi(match[1])
+		teamVlans[team100s*100+team1s] = vlan
+	}
+	return teamVlans
+}
+
+func (driver *ciscoSwitchDriver) addTeamVlanCommands(teamId, vlan int) string {
+	return fmt.Sprintf(
+		"ip dhcp excluded-address 10.%d.%d.1 10.%d.%d.100\n"+
+			"no ip dhcp pool dhcp%d\n"+
+			"ip dhcp pool dhcp%d\n"+
+			"network 10.%d.%d.0 255.255.255.0\n"+
+			"default-router 10.%d.%d.61\n"+
+			"lease 7\n"+
+			"no access-list 1%d\n"+
+			"access-list 1%d permit ip 10.%d.%d.0 0.0.0.255 host %s\n"+
+			"access-list 1%d permit udp any eq bootpc any eq bootps\n"+
+			"interface Vlan%d\nip address 10.%d.%d.61 255.255.255.0\n",
+		teamId/100, teamId%100, teamId/100, teamId%100, vlan, vlan, teamId/100, teamId%100, teamId/100,
+		teamId%100, vlan, vlan, teamId/100, teamId%100, driverStationTcpListenAddress, vlan, vlan, teamId/100,
+		teamId%100)
+}
+
+func (driver *ciscoSwitchDriver) removeTeamVlanCommands(vlan int) string {
+	return fmt.Sprintf("interface Vlan%d\nno ip address\nno access-list 1%d\n", vlan, vlan)
+}
+
+// IOS numbers the Gigabit Ethernet interfaces on the first switch in the stack starting at 10101.
+func (driver *ciscoSwitchDriver) snmpInterfaceIndex(port int) int {
+	return 10100 + port
+}
+
+// IOS prefixes any error message with a percent sign.
+func (driver *ciscoSwitchDriver) checkOutput(output string) error {
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "% ") {
+			return fmt.Errorf("Switch command failed: %s", strings.TrimSpace(line))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Switch driver for an Aruba/HP ProCurve switch running ArubaOS-Switch, using its built-in DHCP server.
+
+package field
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type proCurveSwitchDriver struct{}
+
+var proCurveVlanRe = regexp.MustCompile("(?m)^vlan (\\d\\d)\\r?$((?:\\r?\\n +.*)*)")
+var proCurveIpAddressRe = regexp.MustCompile("ip address 10\\.(\\d+)\\.(\\d+)\\.61")
+
+// Dismisses the login banner and disables paging before the commands, and answers yes to confirm logging out.
+func (driver *proCurveSwitchDriver) sessionScript(commands string) string {
+	return fmt.Sprintf("\nno page\n%slogout\ny\n", commands)
+}
+
+// Saves the configuration once the changes have been applied so that they survive a restart of the switch.
+func (driver *proCurveSwitchDriver) configScript(commands string) string {
+	return driver.sessionScript(fmt.Sprintf("configure\n%sexit\nwrite memory\n", commands))
+}
+
+func (driver *proCurveSwitchDriver) showConfigCommand() string {
+	return "show running-config\n"
+}
+
+// Finds each VLAN context block in the config and looks for a team address within it.
+func (driver *proCurveSwitchDriver) parseTeamVlans(config string) map[int]int {
+	teamVlans := make(map[int]int)
+	for _, match := range proCurveVlanRe.FindAllStringSubmatch(config, -1) {
+		ipMatch := proCurveIpAddressRe.FindStringSubmatch(match[2])
+		if ipMatch == nil {
+			continue
+		}
+		team100s, _ := strconv.Atoi(ipMatch[1])
+		team1s, _ := strconv.Atoi(ipMatch[2])
+		vlan, _ := strconv.Atoi(match[1])
+		teamVlans[team100s*100+team1s] = vlan
+	}
+	return teamVlans
+}
+
+func (driver *proCurveSwitchDriver) addTeamVlanCommands(teamId, vlan int) string {
+	return fmt.Sprintf(
+		"no ip access-list extended 1%d\n"+
+			"ip access-list extended 1%d\n"+
+			"permit ip 10.%d.%d.0 0.0.0.255 %s 0.0.0.0\n"+
+			"permit udp any eq 68 any eq 67\n"+
+			"exit\n"+
+			"no dhcp-server pool dhcp%d\n"+
+			"dhcp-server pool dhcp%d\n"+
+			"network 10.%d.%d.0 255.255.255.0\n"+
+			"default-router 10.%d.%d.61\n"+
+			"range 10.%d.%d.101 10.%d.%d.254\n"+
+			"lease 07:00:00\n"+
+			"exit\n"+
+			"vlan %d\nip address 10.%d.%d.61 255.255.255.0\nip access-group 1%d vlan-in\ndhcp-server\nexit\n",
+		vlan, vlan, teamId/100, teamId%100, driverStationTcpListenAddress, vlan, vlan, teamId/100, teamId%100,
+		teamId/100, teamId%100, teamId/100, teamId%100, teamId/100, teamId%100, vlan, teamId/100, teamId%100, vlan)
+}
+
+func (driver *proCurveSwitchDriver) removeTeamVlanCommands(vlan int) string {
+	return fmt.Sprintf("vlan %d\nno ip address\nno ip access-group 1%d vlan-in\nno dhcp-server\nexit\n"+
+		"no ip access-list extended 1%d\nno dhcp-server pool dhcp%d\n", vlan, vlan, vlan, vlan)
+}
+
+// ArubaOS-Switch uses the port number directly as the interface index.
+func (driver *proCurveSwitchDriver) snmpInterfaceIndex(port int) int {
+	return port
+}
+
+// ArubaOS-Switch reports syntax errors with an "Invalid input" message.
+func (driver *proCurveSwitchDriver) checkOutput(output string) error {
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "Invalid input") {
+			return fmt.Errorf("Switch command failed: %s", strings.TrimSpace(line))
+		}
+	}
+	return nil
+}
@@ -14,58 +14,142 @@ import (
 )
 
 func TestConfigureSwitch(t *testing.T) {
-	ns := NewNetworkSwitch("127.0.0.1", "password")
+	ns := NewNetworkSwitch("cisco", "telnet", "127.0.0.1", "", "password", false)
 	ns.port = 9050
-	var command string
+	var commands []string
 
 	// Should do nothing if current configuration is blank.
-	mockTelnet(t, ns.port, "", &command)
+	mockTelnet(t, ns.port, []string{""}, &commands)
 	assert.Nil(t, ns.ConfigureTeamEthernet(nil, nil, nil, nil, nil, nil))
-	assert.Equal(t, "", command)
+	assert.Equal(t, 0, len(commands))
 
 	// Should remove any existing teams but not other SSIDs.
 	ns.port += 1
 	mockTelnet(t, ns.port,
-		"interface Vlan100\nip address 10.0.100.2\ninterface Vlan50\nip address 10.2.54.61\n", &command)
+		[]string{"interface Vlan100\nip address 10.0.100.2\ninterface Vlan50\nip address 10.2.54.61\n", ""}, &commands)
 	assert.Nil(t, ns.ConfigureTeamEthernet(nil, nil, nil, nil, nil, nil))
-	assert.Equal(t, "password\nenable\npassword\nterminal length 0\nconfig terminal\ninterface Vlan50\nno ip"+
-		" address\nno access-list 150\nend\ncopy running-config startup-config\n\nexit\n", command)
+	if assert.Equal(t, 1, len(commands)) {
+		assert.Equal(t, "password\nenable\npassword\nterminal length 0\nconfig terminal\ninterface Vlan50\nno ip"+
+			" address\nno access-list 150\nend\ncopy running-config startup-config\n\nexit\n", commands[0])
+	}
 
 	// Should configure new teams and leave existing ones alone if still needed.
 	ns.port += 1
-	mockTelnet(t, ns.port, "interface Vlan50\nip address 10.2.54.61\n", &command)
+	mockTelnet(t, ns.port, []string{"interface Vlan50\nip address 10.2.54.61\n", ""}, &commands)
 	assert.Nil(t, ns.ConfigureTeamEthernet(nil, &model.Team{Id: 1114}, nil, nil, &model.Team{Id: 254}, nil))
-	assert.Equal(t, "password\nenable\npassword\nterminal length 0\nconfig terminal\n"+
-		"ip dhcp excluded-address 10.11.14.1 10.11.14.100\nno ip dhcp pool dhcp20\nip dhcp pool dhcp20\n"+
-		"network 10.11.14.0 255.255.255.0\ndefault-router 10.11.14.61\nlease 7\nno access-list 120\n"+
-		"access-list 120 permit ip 10.11.14.0 0.0.0.255 host 10.0.100.5\n"+
-		"access-list 120 permit udp any eq bootpc any eq bootps\ninterface Vlan20\n"+
-		"ip address 10.11.14.61 255.255.255.0\nend\ncopy running-config startup-config\n\nexit\n", command)
+	if assert.Equal(t, 1, len(commands)) {
+		assert.Equal(t, "password\nenable\npassword\nterminal length 0\nconfig terminal\n"+
+			"ip dhcp excluded-address 10.11.14.1 10.11.14.100\nno ip dhcp pool dhcp20\nip dhcp pool dhcp20\n"+
+			"network 10.11.14.0 255.255.255.0\ndefault-router 10.11.14.61\nlease 7\nno access-list 120\n"+
+			"access-list 120 permit ip 10.11.14.0 0.0.0.255 host 10.0.100.5\n"+
+			"access-list 120 permit udp any eq bootpc any eq bootps\ninterface Vlan20\n"+
+			"ip address 10.11.14.61 255.255.255.0\nend\ncopy running-config startup-config\n\nexit\n", commands[0])
+	}
 }
 
-func mockTelnet(t *testing.T, port int, response string, command *string) {
+func TestConfigureSwitchRollback(t *testing.T) {
+	ns := NewNetworkSwitch("cisco", "telnet", "127.0.0.1", "", "password", false)
+	ns.port = 9060
+	var commands []string
+
+	// Should restore the affected VLANs to their previous teams if a command fails.
+	mockTelnet(t, ns.port, []string{"interface Vlan10\nip address 10.2.54.61\ninterface Vlan20\nip address 10.0.1.61\n",
+		"ip dhcp pool dhcp10\n% Invalid input detected at '^' marker.\n", ""}, &commands)
+	err := ns.ConfigureTeamEthernet(&model.Team{Id: 1114}, &model.Team{Id: 1}, nil, nil, nil, nil)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "rolled back to the previous configuration")
+		assert.Contains(t, err.Error(), "% Invalid input detected")
+	}
+	if assert.Equal(t, 2, len(commands)) {
+		assert.Contains(t, commands[0], "interface Vlan10\nip address 10.11.14.61 255.255.255.0\n")
+		assert.Equal(t, "password\nenable\npassword\nterminal length 0\nconfig terminal\n"+
+			"interface Vlan10\nno ip address\nno access-list 110\n"+
+			"ip dhcp excluded-address 10.2.54.1 10.2.54.100\nno ip dhcp pool dhcp10\nip dhcp pool dhcp10\n"+
+			"network 10.2.54.0 255.255.255.0\ndefault-router 10.2.54.61\nlease 7\nno access-list 110\n"+
+			"access-list 110 permit ip 10.2.54.0 0.0.0.255 host 10.0.100.5\n"+
+			"access-list 110 permit udp any eq bootpc any eq bootps\ninterface Vlan10\n"+
+			"ip address 10.2.54.61 255.255.255.0\nend\ncopy running-config startup-config\n\nexit\n", commands[1])
+	}
+
+	// Should report a failed rollback if the switch rejects any of its commands.
+	ns.port += 1
+	mockTelnet(t, ns.port, []string{"interface Vlan10\nip address 10.2.54.61\n",
+		"ip dhcp pool dhcp10\n% Invalid input detected at '^' marker.\n",
+		"ip dhcp pool dhcp10\n% Invalid input detected at '^' marker.\n"}, &commands)
+	err = ns.ConfigureTeamEthernet(&model.Team{Id: 1114}, nil, nil, nil, nil, nil)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "failed to roll back")
+	}
+}
+
+func TestConfigureSwitchDryRun(t *testing.T) {
+	ns := NewNetworkSwitch("cisco", "telnet", "127.0.0.1", "", "password", true)
+	ns.port = 9070
+	var commands []string
+
+	// Should read the current configuration but not apply any changes.
+	mockTelnet(t, ns.port, []string{"interface Vlan50\nip address 10.2.54.61\n"}, &commands)
+	assert.Nil(t, ns.ConfigureTeamEthernet(&model.Team{Id: 1114}, nil, nil, nil, nil, nil))
+	assert.Equal(t, 0, len(commands))
+}
+
+func TestNewNetworkSwitch(t *testing.T) {
+	ns := NewNetworkSwitch("cisco", "ssh", "", "admin", "password", false)
+	assert.Equal(t, switchSshPort, ns.port)
+	assert.Equal(t, "terminal length 0\nshow running-config\nexit\n",
+		ns.driver.sessionScript(ns.driver.showConfigCommand()))
+	assert.Equal(t, 10106, ns.SnmpInterfaceIndex(6))
+
+	ns = NewNetworkSwitch("procurve", "telnet", "", "", "password", false)
+	assert.Equal(t, switchTelnetPort, ns.port)
+	_, ok := ns.driver.(*proCurveSwitchDriver)
+	assert.True(t, ok)
+	assert.Equal(t, 6, ns.SnmpInterfaceIndex(6))
+}
+
+func TestProCurveSwitchDriver(t *testing.T) {
+	driver := proCurveSwitchDriver{}
+
+	config := "hostname \"Field\"\nvlan 1\n   name \"DEFAULT_VLAN\"\n   untagged 1-20\n   ip address dhcp-bootp\n" +
+		"   exit\nvlan 20\n   name \"VLAN20\"\n   untagged 2\n   ip address 10.2.54.61 255.255.255.0\n   exit\n" +
+		"vlan 50\n   name \"VLAN50\"\n   untagged 5\n   no ip address\n   exit\n" +
+		"vlan 60\n   name \"VLAN60\"\n   untagged 6\n   ip address 10.0.1.61 255.255.255.0\n   exit\n" +
+		"vlan 100\n   ip address 10.0.100.2 255.255.255.0\n   exit\n"
+	assert.Equal(t, map[int]int{254: 20, 1: 60}, driver.parseTeamVlans(config))
+	assert.Empty(t, driver.parseTeamVlans(""))
+
+	commands := driver.addTeamVlanCommands(1114, 30)
+	assert.Contains(t, commands, "permit ip 10.11.14.0 0.0.0.255 10.0.100.5 0.0.0.0\n")
+	assert.Contains(t, commands, "dhcp-server pool dhcp30\nnetwork 10.11.14.0 255.255.255.0\n")
+	assert.Contains(t, commands, "vlan 30\nip address 10.11.14.61 255.255.255.0\nip access-group 130 vlan-in\n")
+	assert.Equal(t, "\nno page\nconfigure\nvlan 30\nexit\nexit\nwrite memory\nlogout\ny\n",
+		driver.configScript("vlan 30\nexit\n"))
+
+	assert.Nil(t, driver.checkOutput("Field(config)# vlan 30\nField(vlan-30)# exit\n"))
+	assert.NotNil(t, driver.checkOutput("Field(config)# vlun 30\nInvalid input: vlun\n"))
+}
+
+// Accepts the given number of connections in sequence, replying to each with the corresponding response. The first
+// connection is expected to fetch the configuration, and the commands received on the rest are recorded.
+func mockTelnet(t *testing.T, port int, responses []string, commands *[]string) {
+	*commands = nil
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	assert.Nil(t, err)
 	go func() {
-		// Fake the first connection which should just get the configuration.
-		ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
-		assert.Nil(t, err)
 		defer ln.Close()
-		conn, err := ln.Accept()
-		assert.Nil(t, err)
-		conn.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
-		var reader bytes.Buffer
-		reader.ReadFrom(conn)
-		assert.Contains(t, reader.String(), "terminal length 0\nshow running-config\nexit\n")
-		conn.Write([]byte(response))
-		conn.Close()
-
-		// Fake the second connection which should configure stuff.
-		conn2, err := ln.Accept()
-		assert.Nil(t, err)
-		conn2.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
-		var reader2 bytes.Buffer
-		reader2.ReadFrom(conn2)
-		*command = reader2.String()
-		conn2.Close()
+		for i, response := range responses {
+			conn, err := ln.Accept()
+			assert.Nil(t, err)
+			conn.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
+			var reader bytes.Buffer
+			reader.ReadFrom(conn)
+			if i == 0 {
+				assert.Contains(t, reader.String(), "terminal length 0\nshow running-config\nexit\n")
+			} else {
+				*commands = append(*commands, reader.String())
+			}
+			conn.Write([]byte(response))
+			conn.Close()
+		}
 	}()
-	time.Sleep(100 * time.Millisecond) // Give it some time to open the socket.
 }
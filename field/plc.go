@@ -19,7 +19,9 @@ type Plc struct {
 	client           modbus.Client
 	Inputs           [15]bool
 	Counters         [10]uint16
-	Coils            [24]bool
+	Coils            [34]bool
+	inputFilters     [15]inputFilter
+	counterFilters   [10]counterFilter
 	cycleCounter     int
 	resetCountCycles int
 }
@@ -90,6 +92,16 @@ const (
 	blueTouchpadLight3
 	resetCounts
 	heartbeat
+	stackLightRed
+	stackLightBlue
+	stackLightOrange
+	stackLightGreen
+	redStationLight1
+	redStationLight2
+	redStationLight3
+	blueStationLight1
+	blueStationLight2
+	blueStationLight3
 )
 
 func (plc *Plc) SetAddress(address string) {
@@ -121,7 +133,9 @@ func (plc *Plc) Run() {
 		isHealthy = isHealthy && plc.writeCoils()
 		isHealthy = isHealthy && plc.readInputs()
 		isHealthy = isHealthy && plc.readCounters()
-		if !isHealthy {
+		if isHealthy {
+			plc.updateFilters(time.Now())
+		} else {
 			plc.resetConnection()
 		}
 		plc.IsHealthy = isHealthy
@@ -153,34 +167,35 @@ func (plc *Plc) GetTeamEstops() ([3]bool, [3]bool) {
 	return redEstops, blueEstops
 }
 
-// Returns the count of the red and blue low and high boilers.
+// Returns the filtered count of the red and blue low and high boilers.
 func (plc *Plc) GetBalls() (int, int, int, int) {
-	return int(plc.Counters[redLowBoilerCount]), int(plc.Counters[redHighBoilerCount]),
-		int(plc.Counters[blueLowBoilerCount]), int(plc.Counters[blueHighBoilerCount])
+	return plc.counterFilters[redLowBoilerCount].count, plc.counterFilters[redHighBoilerCount].count,
+		plc.counterFilters[blueLowBoilerCount].count, plc.counterFilters[blueHighBoilerCount].count
 }
 
-// Returns the state of red and blue activated rotors.
+// Returns the filtered state of red and blue activated rotors.
 func (plc *Plc) GetRotors() (bool, [3]int, bool, [3]int) {
 	var redOtherRotors, blueOtherRotors [3]int
 
-	redOtherRotors[0] = int(plc.Counters[redRotor2Count])
-	redOtherRotors[1] = int(plc.Counters[redRotor3Count])
-	redOtherRotors[2] = int(plc.Counters[redRotor4Count])
-	blueOtherRotors[0] = int(plc.Counters[blueRotor2Count])
-	blueOtherRotors[1] = int(plc.Counters[blueRotor3Count])
-	blueOtherRotors[2] = int(plc.Counters[blueRotor4Count])
+	redOtherRotors[0] = plc.counterFilters[redRotor2Count].count
+	redOtherRotors[1] = plc.counterFilters[redRotor3Count].count
+	redOtherRotors[2] = plc.counterFilters[redRotor4Count].count
+	blueOtherRotors[0] = plc.counterFilters[blueRotor2Count].count
+	blueOtherRotors[1] = plc.counterFilters[blueRotor3Count].count
+	blueOtherRotors[2] = plc.counterFilters[blueRotor4Count].count
 
-	return plc.Inputs[redRotor1], redOtherRotors, plc.Inputs[blueRotor1], blueOtherRotors
+	return plc.inputFilters[redRotor1].state, redOtherRotors, plc.inputFilters[blueRotor1].state, blueOtherRotors
 }
 
+// Returns the filtered state of the red and blue touchpads.
 func (plc *Plc) GetTouchpads() ([3]bool, [3]bool) {
 	var redTouchpads, blueTouchpads [3]bool
-	redTouchpads[0] = plc.Inputs[redTouchpad1]
-	redTouchpads[1] = plc.Inputs[redTouchpad2]
-	redTouchpads[2] = plc.Inputs[redTouchpad3]
-	blueTouchpads[0] = plc.Inputs[blueTouchpad1]
-	blueTouchpads[1] = plc.Inputs[blueTouchpad2]
-	blueTouchpads[2] = plc.Inputs[blueTouchpad3]
+	redTouchpads[0] = plc.inputFilters[redTouchpad1].state
+	redTouchpads[1] = plc.inputFilters[redTouchpad2].state
+	redTouchpads[2] = plc.inputFilters[redTouchpad3].state
+	blueTouchpads[0] = plc.inputFilters[blueTouchpad1].state
+	blueTouchpads[1] = plc.inputFilters[blueTouchpad2].state
+	blueTouchpads[2] = plc.inputFilters[blueTouchpad3].state
 	return redTouchpads, blueTouchpads
 }
 
@@ -188,6 +203,9 @@ func (plc *Plc) GetTouchpads() ([3]bool, [3]bool) {
 func (plc *Plc) ResetCounts() {
 	plc.Coils[resetCounts] = true
 	plc.resetCountCycles = 0
+	for i := range plc.counterFilters {
+		plc.counterFilters[i].reset(plc.Counters[i], time.Now())
+	}
 }
 
 func (plc *Plc) SetBoilerMotors(on bool) {
@@ -226,6 +244,24 @@ func (plc *Plc) SetTouchpadLights(redTouchpads, blueTouchpads [3]bool) {
 	plc.Coils[blueTouchpadLight3] = blueTouchpads[2]
 }
 
+// Sets the state of the field stack light columns.
+func (plc *Plc) SetStackLights(red, blue, orange, green bool) {
+	plc.Coils[stackLightRed] = red
+	plc.Coils[stackLightBlue] = blue
+	plc.Coils[stackLightOrange] = orange
+	plc.Coils[stackLightGreen] = green
+}
+
+// Sets the state of the ready indicator light at each driver station.
+func (plc *Plc) SetStationLights(redStations, blueStations [3]bool) {
+	plc.Coils[redStationLight1] = redStations[0]
+	plc.Coils[redStationLight2] = redStations[1]
+	plc.Coils[redStationLight3] = redStations[2]
+	plc.Coils[blueStationLight1] = blueStations[0]
+	plc.Coils[blueStationLight2] = blueStations[1]
+	plc.Coils[blueStationLight3] = blueStations[2]
+}
+
 func (plc *Plc) GetCycleState(max, index, duration int) bool {
 	return plc.cycleCounter/duration%max == index
 }
@@ -243,6 +279,7 @@ func (plc *Plc) connect() error {
 
 	plc.handler = handler
 	plc.client = modbus.NewClient(plc.handler)
+	plc.resetFilters()
 	plc.writeCoils() // Force initial write of the coils upon connection since they may not be triggered by a change.
 	return nil
 }
@@ -0,0 +1,228 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Debounce and filter stages applied to the raw PLC scoring inputs before they are handed to the game logic.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"math"
+	"time"
+)
+
+// Describes a PLC scoring sensor that is subject to filtering. The emergency stop inputs are deliberately excluded so
+// that they are never delayed.
+type filteredSensor struct {
+	name      string
+	index     int
+	isCounter bool
+}
+
+var filteredSensors = []filteredSensor{
+	{"redRotor1", redRotor1, false},
+	{"redTouchpad1", redTouchpad1, false},
+	{"redTouchpad2", redTouchpad2, false},
+	{"redTouchpad3", redTouchpad3, false},
+	{"blueRotor1", blueRotor1, false},
+	{"blueTouchpad1", blueTouchpad1, false},
+	{"blueTouchpad2", blueTouchpad2, false},
+	{"blueTouchpad3", blueTouchpad3, false},
+	{"redRotor2Count", redRotor2Count, true},
+	{"redRotor3Count", redRotor3Count, true},
+	{"redRotor4Count", redRotor4Count, true},
+	{"redLowBoilerCount", redLowBoilerCount, true},
+	{"redHighBoilerCount", redHighBoilerCount, true},
+	{"blueRotor2Count", blueRotor2Count, true},
+	{"blueRotor3Count", blueRotor3Count, true},
+	{"blueRotor4Count", blueRotor4Count, true},
+	{"blueLowBoilerCount", blueLowBoilerCount, true},
+	{"blueHighBoilerCount", blueHighBoilerCount, true},
+}
+
+// Thresholds used for any sensor that doesn't have its own settings saved in the database. The first rotor sensors
+// need to see the gear for two consecutive PLC cycles so that a single noisy sample can't latch a rotor.
+var defaultSensorFilters = map[string]model.SensorFilter{
+	"redRotor1":  {Sensor: "redRotor1", MinOnMs: plcLoopPeriodMs},
+	"blueRotor1": {Sensor: "blueRotor1", MinOnMs: plcLoopPeriodMs},
+}
+
+// Current raw and filtered values of a sensor, for display on the field setup page.
+type SensorFilterStatus struct {
+	model.SensorFilter
+	IsCounter     bool
+	RawValue      int
+	FilteredValue int
+}
+
+// Filters a discrete input, requiring it to be on for a minimum time before reporting it as on and ignoring any
+// dropouts shorter than the glitch rejection time.
+type inputFilter struct {
+	minOn        time.Duration
+	glitchReject time.Duration
+	state        bool
+	changeTime   *time.Time
+}
+
+// Filters a 16-bit hardware counter, accumulating its increments into a total that survives counter wraparound and
+// discarding any increments that arrive faster than the maximum count rate.
+type counterFilter struct {
+	maxCountRate  int
+	initialized   bool
+	needsBaseline bool
+	lastRaw       uint16
+	lastTime      time.Time
+	count         int
+}
+
+// Returns the filter settings that apply to the given sensor, falling back to the defaults.
+func getSensorFilter(sensor string, sensorFilters []model.SensorFilter) model.SensorFilter {
+	for _, sensorFilter := range sensorFilters {
+		if sensorFilter.Sensor == sensor {
+			return sensorFilter
+		}
+	}
+	if sensorFilter, ok := defaultSensorFilters[sensor]; ok {
+		return sensorFilter
+	}
+	return model.SensorFilter{Sensor: sensor}
+}
+
+// Returns an error if the given sensor name is unknown or if its thresholds are invalid.
+func ValidateSensorFilter(sensorFilter *model.SensorFilter) error {
+	for _, sensor := range filteredSensors {
+		if sensor.name == sensorFilter.Sensor {
+			if sensorFilter.MinOnMs < 0 || sensorFilter.GlitchRejectMs < 0 || sensorFilter.MaxCountRate < 0 {
+				return fmt.Errorf("Filter thresholds for sensor '%s' cannot be negative.", sensor.name)
+			}
+			if !sensor.isCounter && sensorFilter.MaxCountRate != 0 {
+				return fmt.Errorf("Sensor '%s' is not a counter and cannot have a count rate limit.", sensor.name)
+			}
+			if sensor.isCounter && (sensorFilter.MinOnMs != 0 || sensorFilter.GlitchRejectMs != 0) {
+				return fmt.Errorf("Sensor '%s' is a counter and cannot have debounce times.", sensor.name)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("Invalid sensor '%s'.", sensorFilter.Sensor)
+}
+
+// Applies the given filter settings to the PLC sensors, using the defaults for any that aren't specified.
+func (plc *Plc) SetSensorFilters(sensorFilters []model.SensorFilter) {
+	for _, sensor := range filteredSensors {
+		sensorFilter := getSensorFilter(sensor.name, sensorFilters)
+		if sensor.isCounter {
+			plc.counterFilters[sensor.index].maxCountRate = sensorFilter.MaxCountRate
+		} else {
+			plc.inputFilters[sensor.index].minOn = time.Duration(sensorFilter.MinOnMs) * time.Millisecond
+			plc.inputFilters[sensor.index].glitchReject = time.Duration(sensorFilter.GlitchRejectMs) * time.Millisecond
+		}
+	}
+}
+
+// Returns the current settings and values of all the filtered sensors.
+func (plc *Plc) GetSensorFilterStatuses() []SensorFilterStatus {
+	statuses := make([]SensorFilterStatus, len(filteredSensors))
+	for i, sensor := range filteredSensors {
+		statuses[i].Sensor = sensor.name
+		statuses[i].IsCounter = sensor.isCounter
+		if sensor.isCounter {
+			filter := &plc.counterFilters[sensor.index]
+			statuses[i].MaxCountRate = filter.maxCountRate
+			statuses[i].RawValue = int(plc.Counters[sensor.index])
+			statuses[i].FilteredValue = filter.count
+		} else {
+			filter := &plc.inputFilters[sensor.index]
+			statuses[i].MinOnMs = int(filter.minOn / time.Millisecond)
+			statuses[i].GlitchRejectMs = int(filter.glitchReject / time.Millisecond)
+			statuses[i].RawValue = boolToInt(plc.Inputs[sensor.index])
+			statuses[i].FilteredValue = boolToInt(filter.state)
+		}
+	}
+	return statuses
+}
+
+// Discards any debounce in progress and has the counters pick up from the next raw values without adding to their
+// totals, since the PLC may have restarted and reset its counters while disconnected.
+func (plc *Plc) resetFilters() {
+	for i := range plc.inputFilters {
+		plc.inputFilters[i].changeTime = nil
+	}
+	for i := range plc.counterFilters {
+		plc.counterFilters[i].needsBaseline = true
+	}
+}
+
+// Runs the latest raw PLC samples through the filters.
+func (plc *Plc) updateFilters(currentTime time.Time) {
+	for i := range plc.Inputs {
+		plc.inputFilters[i].update(plc.Inputs[i], currentTime)
+	}
+	for i := range plc.Counters {
+		if plc.Coils[resetCounts] {
+			// Hold the totals at zero until the hardware counters have been reset.
+			plc.counterFilters[i].reset(plc.Counters[i], currentTime)
+		} else {
+			plc.counterFilters[i].update(plc.Counters[i], currentTime)
+		}
+	}
+}
+
+func (filter *inputFilter) update(raw bool, currentTime time.Time) bool {
+	if raw == filter.state {
+		filter.changeTime = nil
+		return filter.state
+	}
+
+	if filter.changeTime == nil {
+		filter.changeTime = &currentTime
+	}
+	threshold := filter.glitchReject
+	if raw {
+		threshold = filter.minOn
+	}
+	if currentTime.Sub(*filter.changeTime) >= threshold {
+		filter.state = raw
+		filter.changeTime = nil
+	}
+	return filter.state
+}
+
+func (filter *counterFilter) update(raw uint16, currentTime time.Time) int {
+	if !filter.initialized {
+		filter.initialized = true
+		filter.count = int(raw)
+	} else if filter.needsBaseline {
+		// The PLC has reconnected, so carry on counting from the counter's new value.
+		filter.needsBaseline = false
+	} else {
+		// Unsigned subtraction yields the correct increment even if the counter has wrapped around.
+		delta := int((raw - filter.lastRaw) & 0xFFFF)
+		if filter.maxCountRate > 0 {
+			maxDelta := int(math.Ceil(float64(filter.maxCountRate) * currentTime.Sub(filter.lastTime).Seconds()))
+			if delta > maxDelta {
+				delta = maxDelta
+			}
+		}
+		filter.count += delta
+	}
+	filter.lastRaw = raw
+	filter.lastTime = currentTime
+	return filter.count
+}
+
+func (filter *counterFilter) reset(raw uint16, currentTime time.Time) {
+	filter.initialized = true
+	filter.needsBaseline = false
+	filter.count = 0
+	filter.lastRaw = raw
+	filter.lastTime = currentTime
+}
+
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -4,8 +4,10 @@
 package field
 
 import (
+	"github.com/Team254/cheesy-arena/model"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestByteToBool(t *testing.T) {
@@ -34,3 +36,105 @@ func TestBoolToByte(t *testing.T) {
 		assert.Equal(t, bools, byteToBool(bytes, len(bools)))
 	}
 }
+
+func TestInputFilter(t *testing.T) {
+	filter := inputFilter{minOn: 200 * time.Millisecond, glitchReject: 100 * time.Millisecond}
+	startTime := time.Now()
+
+	// Check that a single noisy sample is rejected.
+	assert.False(t, filter.update(true, startTime))
+	assert.False(t, filter.update(false, startTime.Add(100*time.Millisecond)))
+	assert.False(t, filter.update(true, startTime.Add(200*time.Millisecond)))
+	assert.False(t, filter.update(true, startTime.Add(300*time.Millisecond)))
+	assert.True(t, filter.update(true, startTime.Add(400*time.Millisecond)))
+
+	// Check that a short dropout is rejected.
+	assert.True(t, filter.update(false, startTime.Add(500*time.Millisecond)))
+	assert.True(t, filter.update(true, startTime.Add(550*time.Millisecond)))
+	assert.True(t, filter.update(false, startTime.Add(600*time.Millisecond)))
+	assert.False(t, filter.update(false, startTime.Add(700*time.Millisecond)))
+
+	// Check that an unconfigured filter passes samples straight through.
+	filter = inputFilter{}
+	assert.True(t, filter.update(true, startTime))
+	assert.False(t, filter.update(false, startTime))
+}
+
+func TestCounterFilter(t *testing.T) {
+	filter := counterFilter{}
+	startTime := time.Now()
+
+	assert.Equal(t, 5, filter.update(5, startTime))
+	assert.Equal(t, 12, filter.update(12, startTime.Add(100*time.Millisecond)))
+
+	// Check that the count survives 16-bit wraparound.
+	filter.reset(65530, startTime)
+	assert.Equal(t, 0, filter.count)
+	assert.Equal(t, 4, filter.update(65534, startTime.Add(100*time.Millisecond)))
+	assert.Equal(t, 10, filter.update(4, startTime.Add(200*time.Millisecond)))
+
+	// Check that increments faster than the rate limit are discarded.
+	filter.maxCountRate = 50
+	assert.Equal(t, 15, filter.update(14, startTime.Add(300*time.Millisecond)))
+	assert.Equal(t, 20, filter.update(1000, startTime.Add(400*time.Millisecond)))
+	assert.Equal(t, 21, filter.update(1001, startTime.Add(500*time.Millisecond)))
+}
+
+func TestPlcSensorFilters(t *testing.T) {
+	var plc Plc
+	plc.SetSensorFilters([]model.SensorFilter{{Sensor: "blueTouchpad2", GlitchRejectMs: 300},
+		{Sensor: "redHighBoilerCount", MaxCountRate: 10}})
+	assert.Equal(t, 100*time.Millisecond, plc.inputFilters[redRotor1].minOn)
+	assert.Equal(t, 300*time.Millisecond, plc.inputFilters[blueTouchpad2].glitchReject)
+	assert.Equal(t, time.Duration(0), plc.inputFilters[redTouchpad1].minOn)
+	assert.Equal(t, 10, plc.counterFilters[redHighBoilerCount].maxCountRate)
+
+	// Check that the getters return the filtered values.
+	startTime := time.Now()
+	plc.Inputs[redRotor1] = true
+	plc.Inputs[blueTouchpad1] = true
+	plc.Counters[redLowBoilerCount] = 7
+	plc.updateFilters(startTime)
+	redRotor1, _, _, _ := plc.GetRotors()
+	assert.False(t, redRotor1)
+	_, blueTouchpads := plc.GetTouchpads()
+	assert.Equal(t, [3]bool{true, false, false}, blueTouchpads)
+	redLow, _, _, _ := plc.GetBalls()
+	assert.Equal(t, 7, redLow)
+	plc.updateFilters(startTime.Add(100 * time.Millisecond))
+	redRotor1, _, _, _ = plc.GetRotors()
+	assert.True(t, redRotor1)
+
+	// Check that the counts are held at zero while they are being reset.
+	plc.ResetCounts()
+	redLow, _, _, _ = plc.GetBalls()
+	assert.Equal(t, 0, redLow)
+	plc.Counters[redLowBoilerCount] = 9
+	plc.updateFilters(startTime.Add(200 * time.Millisecond))
+	redLow, _, _, _ = plc.GetBalls()
+	assert.Equal(t, 0, redLow)
+
+	// Check that the counts pick up from the new raw values after the PLC reconnects.
+	plc.Coils[resetCounts] = false
+	plc.Counters[redLowBoilerCount] = 12
+	plc.updateFilters(startTime.Add(300 * time.Millisecond))
+	plc.resetFilters()
+	plc.Counters[redLowBoilerCount] = 2
+	plc.updateFilters(startTime.Add(400 * time.Millisecond))
+	redLow, _, _, _ = plc.GetBalls()
+	assert.Equal(t, 3, redLow)
+	plc.Counters[redLowBoilerCount] = 4
+	plc.updateFilters(startTime.Add(500 * time.Millisecond))
+	redLow, _, _, _ = plc.GetBalls()
+	assert.Equal(t, 5, redLow)
+
+	statuses := plc.GetSensorFilterStatuses()
+	assert.Equal(t, len(filteredSensors), len(statuses))
+	assert.Equal(t, SensorFilterStatus{model.SensorFilter{Sensor: "redRotor1", MinOnMs: 100}, false, 1, 1}, statuses[0])
+
+	assert.Nil(t, ValidateSensorFilter(&model.SensorFilter{Sensor: "redRotor1", MinOnMs: 50}))
+	assert.NotNil(t, ValidateSensorFilter(&model.SensorFilter{Sensor: "fieldEstop"}))
+	assert.NotNil(t, ValidateSensorFilter(&model.SensorFilter{Sensor: "redRotor1", MaxCountRate: 5}))
+	assert.NotNil(t, ValidateSensorFilter(&model.SensorFilter{Sensor: "redRotor2Count", MinOnMs: 5}))
+	assert.NotNil(t, ValidateSensorFilter(&model.SensorFilter{Sensor: "blueTouchpad1", GlitchRejectMs: -1}))
+}
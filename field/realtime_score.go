@@ -5,18 +5,150 @@
 
 package field
 
-import "github.com/Team254/cheesy-arena/game"
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"sync"
+	"time"
+)
 
 type RealtimeScore struct {
 	CurrentScore    game.Score
 	Cards           map[string]string
+	CardReasons     map[string]string
 	TeleopCommitted bool
 	FoulsCommitted  bool
+	Timeline        []game.ScoringEvent
+	Overrides       []game.ScoreOverride
+	ManualScoring   bool
+	scorers         map[string]*ScorerState
+	scorersMutex    sync.Mutex
 	boiler          game.Boiler
 	rotorSet        game.RotorSet
 	touchpads       [3]game.Touchpad
+	timelineScore   game.Score
+	touchpadStates  [3]int
+	manualElements  map[string]bool
+	manualBaseScore game.Score
 }
 
 func NewRealtimeScore() *RealtimeScore {
-	return &RealtimeScore{Cards: make(map[string]string)}
+	return &RealtimeScore{Cards: make(map[string]string), CardReasons: make(map[string]string),
+		scorers: make(map[string]*ScorerState), manualElements: make(map[string]bool)}
+}
+
+// Appends events to the timeline for any changes to the score since the last time it was updated. Changes are only
+// tracked and not recorded if record is false, such as before the match has started.
+func (realtimeScore *RealtimeScore) updateTimeline(timeInMatchSec float64, currentTime time.Time, record bool) {
+	if record {
+		for _, event := range game.ScoreChangeEvents(&realtimeScore.timelineScore, &realtimeScore.CurrentScore,
+			timeInMatchSec) {
+			if override := realtimeScore.override(event.Element); override != nil {
+				event.Source = game.RefereeSource
+				event.Description += fmt.Sprintf(" (override: %s)", override.Reason)
+			} else if realtimeScore.manualElements[event.Element] && event.Source == game.SensorSource {
+				event.Source = game.ManualSource
+			}
+			realtimeScore.Timeline = append(realtimeScore.Timeline, event)
+		}
+	}
+	realtimeScore.timelineScore = realtimeScore.CurrentScore
+	realtimeScore.timelineScore.Fouls = append([]game.Foul(nil), realtimeScore.CurrentScore.Fouls...)
+
+	for i := range realtimeScore.touchpads {
+		state := realtimeScore.touchpads[i].GetState(currentTime)
+		if record {
+			event := game.TouchpadChangeEvent(i, realtimeScore.touchpadStates[i], state, timeInMatchSec)
+			if event != nil {
+				realtimeScore.Timeline = append(realtimeScore.Timeline, *event)
+			}
+		}
+		realtimeScore.touchpadStates[i] = state
+	}
+}
+
+// Replaces the sensor-derived value of the given score element with the one entered by the head referee, either
+// during the match or once it is over. The value originally counted by the sensors is retained across repeated
+// overrides of the same element.
+func (realtimeScore *RealtimeScore) OverrideScore(element string, value int, reason string, timeInMatchSec float64,
+	postMatch bool) error {
+	override, err := game.NewScoreOverride(&realtimeScore.CurrentScore, element, value, reason, timeInMatchSec)
+	if err != nil {
+		return err
+	}
+	override.PostMatch = postMatch
+	if existingOverride := realtimeScore.override(element); existingOverride != nil {
+		override.OriginalValue = existingOverride.OriginalValue
+		*existingOverride = *override
+	} else {
+		realtimeScore.Overrides = append(realtimeScore.Overrides, *override)
+	}
+	override.Apply(&realtimeScore.CurrentScore)
+	return nil
+}
+
+// Removes any override of the given score element and restores the value that was counted by the sensors.
+func (realtimeScore *RealtimeScore) ClearOverride(element string) {
+	for i, override := range realtimeScore.Overrides {
+		if override.Element == element {
+			override.Revert(&realtimeScore.CurrentScore)
+			realtimeScore.Overrides = append(realtimeScore.Overrides[:i], realtimeScore.Overrides[i+1:]...)
+			return
+		}
+	}
+}
+
+// Re-applies the head referee's overrides on top of the values most recently counted by the sensors, keeping the
+// latest sensor count of each overridden element as its original value.
+func (realtimeScore *RealtimeScore) applyOverrides() {
+	for i := range realtimeScore.Overrides {
+		override := &realtimeScore.Overrides[i]
+		if !realtimeScore.ManualScoring && !realtimeScore.manualElements[override.Element] {
+			override.UpdateOriginalValue(&realtimeScore.CurrentScore)
+		}
+		override.Apply(&realtimeScore.CurrentScore)
+	}
+}
+
+func (realtimeScore *RealtimeScore) override(element string) *game.ScoreOverride {
+	for i := range realtimeScore.Overrides {
+		if realtimeScore.Overrides[i].Element == element {
+			return &realtimeScore.Overrides[i]
+		}
+	}
+	return nil
+}
+
+// Switches the counting of the sensor-derived score elements between the field sensors and the scorers, such as when
+// the field PLC is unavailable.
+func (realtimeScore *RealtimeScore) SetManualScoring(enabled bool) {
+	realtimeScore.ManualScoring = enabled
+}
+
+// Restores the values from the given previous score of any elements that the sensors shouldn't update: all of them
+// while manual scoring is on, and otherwise those that have been counted by hand.
+func (realtimeScore *RealtimeScore) discardSensorCounts(oldScore *game.Score) {
+	if realtimeScore.ManualScoring {
+		realtimeScore.CurrentScore.CopySensorElements(oldScore)
+		return
+	}
+	for element := range realtimeScore.manualElements {
+		realtimeScore.CurrentScore.CopySensorElement(element, oldScore)
+	}
+}
+
+// Returns where the final value of each sensor-derived score element came from: the field sensors, the scorers
+// counting it by hand, or a head referee override.
+func (realtimeScore *RealtimeScore) ScoreSources() map[string]string {
+	sources := make(map[string]string)
+	for element := range game.OverridableScoreElements {
+		if realtimeScore.override(element) != nil {
+			sources[element] = game.RefereeSource
+		} else if realtimeScore.manualElements[element] {
+			sources[element] = game.ManualSource
+		} else {
+			sources[element] = game.SensorSource
+		}
+	}
+	return sources
 }
@@ -0,0 +1,249 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Reconciliation of the inputs of multiple scorers independently scoring the same alliance.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"sort"
+)
+
+// Names of the score elements that are entered by the scorers and compared between them.
+var ScorerElements = map[string]string{
+	"AutoMobility": "Auto mobility",
+}
+
+// The inputs entered by one scorer for an alliance. Manual holds the scorer's own hand counts of any sensor-derived
+// elements, keyed by element name, that they have counted while the field sensors were unavailable.
+type ScorerState struct {
+	AutoMobility    int
+	Manual          map[string]int
+	TeleopCommitted bool
+}
+
+// Returns the names of all the elements that the scorers may enter, including the sensor-derived ones that they count
+// by hand.
+func AllScorerElements() map[string]string {
+	elements := make(map[string]string)
+	for element, name := range ScorerElements {
+		elements[element] = name
+	}
+	for element, name := range game.OverridableScoreElements {
+		elements[element] = name
+	}
+	return elements
+}
+
+// Adds the given scorer to those whose inputs must agree and who must all commit before the alliance's score is
+// committed, such as when their scoring display connects. Does nothing if the scorer is already known.
+func (realtimeScore *RealtimeScore) RegisterScorer(scorerId string) {
+	realtimeScore.scorersMutex.Lock()
+	defer realtimeScore.scorersMutex.Unlock()
+	realtimeScore.scorer(scorerId)
+	realtimeScore.reconcileScorers()
+}
+
+// Applies the given change to the inputs of the given scorer, creating their state if this is their first input in the
+// match, and then copies any values that the scorers now agree on into the current score.
+func (realtimeScore *RealtimeScore) UpdateScorer(scorerId string, update func(scorer *ScorerState)) {
+	realtimeScore.scorersMutex.Lock()
+	defer realtimeScore.scorersMutex.Unlock()
+	update(realtimeScore.scorer(scorerId))
+	realtimeScore.reconcileScorers()
+}
+
+// Returns a copy of the state of the given scorer, or nil if they haven't connected or entered anything in the match.
+func (realtimeScore *RealtimeScore) ScorerState(scorerId string) *ScorerState {
+	realtimeScore.scorersMutex.Lock()
+	defer realtimeScore.scorersMutex.Unlock()
+	if scorer, ok := realtimeScore.scorers[scorerId]; ok {
+		scorerCopy := scorer.copy()
+		return &scorerCopy
+	}
+	return nil
+}
+
+// Returns a copy of the states of all the scorers that have connected or entered anything in the match, keyed by
+// scorer ID.
+func (realtimeScore *RealtimeScore) ScorerStates() map[string]ScorerState {
+	realtimeScore.scorersMutex.Lock()
+	defer realtimeScore.scorersMutex.Unlock()
+	scorers := make(map[string]ScorerState)
+	for scorerId, scorer := range realtimeScore.scorers {
+		scorers[scorerId] = scorer.copy()
+	}
+	return scorers
+}
+
+// Returns the names of the score elements for which the scorers have entered different values, in sorted order.
+func (realtimeScore *RealtimeScore) Disagreements() []string {
+	realtimeScore.scorersMutex.Lock()
+	defer realtimeScore.scorersMutex.Unlock()
+	return realtimeScore.disagreements()
+}
+
+// Copies the values of the elements that all scorers agree on into the current score, leaving those that they
+// disagree on unchanged until they are resolved.
+func (realtimeScore *RealtimeScore) ReconcileScorers() {
+	realtimeScore.scorersMutex.Lock()
+	defer realtimeScore.scorersMutex.Unlock()
+	realtimeScore.reconcileScorers()
+}
+
+// Sets the value decided on by the head referee for an element that the scorers disagree on, for all scorers.
+func (realtimeScore *RealtimeScore) ResolveDisagreement(element string, value int) error {
+	if _, ok := AllScorerElements()[element]; !ok {
+		return fmt.Errorf("Invalid scorer element '%s'.", element)
+	}
+	realtimeScore.scorersMutex.Lock()
+	defer realtimeScore.scorersMutex.Unlock()
+	for _, scorer := range realtimeScore.scorers {
+		scorer.setValue(element, value)
+	}
+	realtimeScore.reconcileScorers()
+	return nil
+}
+
+// Marks the given scorer as having finished scoring the match. The alliance's score is only committed once all of
+// its scorers have done so, and not at all while they still disagree.
+func (realtimeScore *RealtimeScore) CommitScorer(scorerId string) error {
+	realtimeScore.scorersMutex.Lock()
+	defer realtimeScore.scorersMutex.Unlock()
+	scorer := realtimeScore.scorer(scorerId)
+	if disagreements := realtimeScore.disagreements(); len(disagreements) > 0 {
+		return fmt.Errorf("Cannot commit score: Scorers disagree on %s; waiting for the head referee.",
+			AllScorerElements()[disagreements[0]])
+	}
+	scorer.TeleopCommitted = true
+	for _, otherScorer := range realtimeScore.scorers {
+		if !otherScorer.TeleopCommitted {
+			return nil
+		}
+	}
+	realtimeScore.TeleopCommitted = true
+	return nil
+}
+
+// Returns the state of the given scorer, creating it if this is the first input from that scorer in the match. The
+// caller must hold the scorers mutex.
+func (realtimeScore *RealtimeScore) scorer(scorerId string) *ScorerState {
+	scorer, ok := realtimeScore.scorers[scorerId]
+	if !ok {
+		scorer = new(ScorerState)
+		realtimeScore.scorers[scorerId] = scorer
+	}
+	return scorer
+}
+
+// Returns the names of the elements that are compared between the scorers: those that they always enter and any
+// sensor-derived ones that they have started counting by hand. The caller must hold the scorers mutex.
+func (realtimeScore *RealtimeScore) scorerElements() []string {
+	var elements []string
+	for element := range ScorerElements {
+		elements = append(elements, element)
+	}
+	for element := range game.OverridableScoreElements {
+		if realtimeScore.manualElements[element] {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+func (realtimeScore *RealtimeScore) disagreements() []string {
+	var disagreements []string
+	for _, element := range realtimeScore.scorerElements() {
+		values := make(map[int]bool)
+		for _, scorer := range realtimeScore.scorers {
+			values[scorer.Value(element)] = true
+		}
+		if len(values) > 1 {
+			disagreements = append(disagreements, element)
+		}
+	}
+	sort.Strings(disagreements)
+	return disagreements
+}
+
+func (realtimeScore *RealtimeScore) reconcileScorers() {
+	if len(realtimeScore.scorers) == 0 {
+		return
+	}
+
+	// Switch any element that a scorer has started counting by hand over to manual counting, keeping the value it had
+	// at that point as the base that the scorers' counts are added to.
+	for _, scorer := range realtimeScore.scorers {
+		for element := range scorer.Manual {
+			if !realtimeScore.manualElements[element] {
+				realtimeScore.manualElements[element] = true
+				realtimeScore.manualBaseScore.CopySensorElement(element, &realtimeScore.CurrentScore)
+			}
+		}
+	}
+
+	disagreements := make(map[string]bool)
+	for _, element := range realtimeScore.disagreements() {
+		disagreements[element] = true
+	}
+	for _, element := range realtimeScore.scorerElements() {
+		if disagreements[element] {
+			continue
+		}
+		for _, scorer := range realtimeScore.scorers {
+			realtimeScore.setScoreElement(element, scorer.Value(element))
+			break
+		}
+	}
+}
+
+// Adds the given amount to the scorer's hand count of a sensor-derived score element. The count won't go below zero.
+func (scorer *ScorerState) ScoreManually(element string, delta int) {
+	scorer.setValue(element, scorer.Value(element)+delta)
+}
+
+// Returns the value the scorer has entered for the given element.
+func (scorer ScorerState) Value(element string) int {
+	if element == "AutoMobility" {
+		return scorer.AutoMobility
+	}
+	return scorer.Manual[element]
+}
+
+func (scorer *ScorerState) setValue(element string, value int) {
+	if element == "AutoMobility" {
+		scorer.AutoMobility = value
+		return
+	}
+	if value < 0 {
+		value = 0
+	}
+	if scorer.Manual == nil {
+		scorer.Manual = make(map[string]int)
+	}
+	scorer.Manual[element] = value
+}
+
+func (scorer ScorerState) copy() ScorerState {
+	if scorer.Manual != nil {
+		manual := make(map[string]int)
+		for element, value := range scorer.Manual {
+			manual[element] = value
+		}
+		scorer.Manual = manual
+	}
+	return scorer
+}
+
+// Sets the given element of the current score to the value the scorers agree on. Elements counted by hand are added to
+// the value they had when manual counting started.
+func (realtimeScore *RealtimeScore) setScoreElement(element string, value int) {
+	if element == "AutoMobility" {
+		realtimeScore.CurrentScore.AutoMobility = value
+		return
+	}
+	realtimeScore.CurrentScore.CopySensorElement(element, &realtimeScore.manualBaseScore)
+	realtimeScore.CurrentScore.AdjustSensorElement(element, value)
+}
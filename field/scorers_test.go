@@ -0,0 +1,109 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestScorerReconciliation(t *testing.T) {
+	realtimeScore := NewRealtimeScore()
+	assert.Empty(t, realtimeScore.Disagreements())
+
+	// A single scorer's inputs should be used directly.
+	realtimeScore.UpdateScorer("1", func(scorer *ScorerState) { scorer.AutoMobility = 2 })
+	assert.Equal(t, 2, realtimeScore.CurrentScore.AutoMobility)
+
+	// The score should be left alone while the scorers disagree.
+	realtimeScore.UpdateScorer("2", func(scorer *ScorerState) { scorer.AutoMobility = 3 })
+	assert.Equal(t, []string{"AutoMobility"}, realtimeScore.Disagreements())
+	assert.Equal(t, 2, realtimeScore.CurrentScore.AutoMobility)
+	realtimeScore.UpdateScorer("1", func(scorer *ScorerState) { scorer.AutoMobility = 3 })
+	assert.Empty(t, realtimeScore.Disagreements())
+	assert.Equal(t, 3, realtimeScore.CurrentScore.AutoMobility)
+
+	// The head referee should be able to settle a disagreement.
+	realtimeScore.UpdateScorer("2", func(scorer *ScorerState) { scorer.AutoMobility = 1 })
+	assert.Equal(t, []string{"AutoMobility"}, realtimeScore.Disagreements())
+	assert.NotNil(t, realtimeScore.ResolveDisagreement("Foo", 1))
+	assert.Nil(t, realtimeScore.ResolveDisagreement("AutoMobility", 1))
+	assert.Empty(t, realtimeScore.Disagreements())
+	assert.Equal(t, 1, realtimeScore.CurrentScore.AutoMobility)
+	assert.Equal(t, 1, realtimeScore.ScorerState("1").Value("AutoMobility"))
+}
+
+func TestCommitScorers(t *testing.T) {
+	realtimeScore := NewRealtimeScore()
+	realtimeScore.UpdateScorer("1", func(scorer *ScorerState) { scorer.AutoMobility = 2 })
+	realtimeScore.UpdateScorer("2", func(scorer *ScorerState) { scorer.AutoMobility = 1 })
+
+	err := realtimeScore.CommitScorer("1")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Cannot commit score: Scorers disagree on Auto mobility; waiting for the head referee.",
+			err.Error())
+	}
+	assert.False(t, realtimeScore.ScorerState("1").TeleopCommitted)
+
+	realtimeScore.ResolveDisagreement("AutoMobility", 2)
+	assert.Nil(t, realtimeScore.CommitScorer("1"))
+	assert.False(t, realtimeScore.TeleopCommitted)
+	assert.Nil(t, realtimeScore.CommitScorer("2"))
+	assert.True(t, realtimeScore.TeleopCommitted)
+
+	// A connected scorer who hasn't entered anything should still hold up the commit and be compared.
+	realtimeScore = NewRealtimeScore()
+	realtimeScore.RegisterScorer("1")
+	realtimeScore.RegisterScorer("2")
+	realtimeScore.UpdateScorer("1", func(scorer *ScorerState) { scorer.AutoMobility = 1 })
+	assert.Equal(t, []string{"AutoMobility"}, realtimeScore.Disagreements())
+	assert.Equal(t, 0, realtimeScore.CurrentScore.AutoMobility)
+	realtimeScore.UpdateScorer("1", func(scorer *ScorerState) { scorer.AutoMobility = 0 })
+	assert.Nil(t, realtimeScore.CommitScorer("1"))
+	assert.False(t, realtimeScore.TeleopCommitted)
+	realtimeScore.RegisterScorer("1")
+	assert.True(t, realtimeScore.ScorerState("1").TeleopCommitted)
+	assert.Nil(t, realtimeScore.CommitScorer("2"))
+	assert.True(t, realtimeScore.TeleopCommitted)
+
+	// A scorer committing without having entered anything counts as their input.
+	realtimeScore = NewRealtimeScore()
+	realtimeScore.UpdateScorer("1", func(scorer *ScorerState) { scorer.AutoMobility = 1 })
+	assert.NotNil(t, realtimeScore.CommitScorer("2"))
+	assert.Equal(t, []string{"AutoMobility"}, realtimeScore.Disagreements())
+}
+
+func TestScorerManualCounts(t *testing.T) {
+	realtimeScore := NewRealtimeScore()
+	realtimeScore.CurrentScore.FuelHigh = 10
+	realtimeScore.RegisterScorer("1")
+	realtimeScore.RegisterScorer("2")
+
+	// Counts from scorers counting the same element should be compared rather than added together, on top of the
+	// value the element had when counting by hand started.
+	realtimeScore.UpdateScorer("1", func(scorer *ScorerState) { scorer.ScoreManually("FuelHigh", 3) })
+	assert.Equal(t, []string{"FuelHigh"}, realtimeScore.Disagreements())
+	assert.Equal(t, 10, realtimeScore.CurrentScore.FuelHigh)
+	realtimeScore.UpdateScorer("2", func(scorer *ScorerState) { scorer.ScoreManually("FuelHigh", 3) })
+	assert.Empty(t, realtimeScore.Disagreements())
+	assert.Equal(t, 13, realtimeScore.CurrentScore.FuelHigh)
+	assert.Equal(t, game.ManualSource, realtimeScore.ScoreSources()["FuelHigh"])
+	assert.Equal(t, game.SensorSource, realtimeScore.ScoreSources()["FuelLow"])
+
+	// A scorer's count shouldn't go below zero.
+	realtimeScore.UpdateScorer("2", func(scorer *ScorerState) { scorer.ScoreManually("FuelHigh", -5) })
+	assert.Equal(t, 0, realtimeScore.ScorerState("2").Value("FuelHigh"))
+	assert.Equal(t, []string{"FuelHigh"}, realtimeScore.Disagreements())
+	assert.NotNil(t, realtimeScore.CommitScorer("1"))
+
+	// The head referee should be able to settle a disagreement over a hand count.
+	assert.Nil(t, realtimeScore.ResolveDisagreement("FuelHigh", 2))
+	assert.Empty(t, realtimeScore.Disagreements())
+	assert.Equal(t, 12, realtimeScore.CurrentScore.FuelHigh)
+
+	// The returned states shouldn't share their counts with the live ones.
+	realtimeScore.ScorerState("1").Manual["FuelHigh"] = 7
+	assert.Equal(t, 2, realtimeScore.ScorerState("1").Value("FuelHigh"))
+}
@@ -37,16 +37,20 @@ func NewTeamMatchLog(teamId int, match *model.Match) (*TeamMatchLog, error) {
 
 	log := TeamMatchLog{log.New(logFile, "", 0), logFile}
 	log.logger.Println("matchTimeSec,packetType,teamId,allianceStation,radioLinked,robotLinked,auto,enabled," +
-		"emergencyStop,batteryVoltage,missedPacketCount,dsRobotTripTimeMs")
+		"emergencyStop,batteryVoltage,missedPacketCount,dsRobotTripTimeMs,wifiSsidActive,wifiAssociated," +
+		"wifiSignalDbm,wifiTxRateMbps,wifiRxRateMbps,wifiChannelUtilization")
 
 	return &log, nil
 }
 
-// Adds a line to the log when a packet is received.
-func (log *TeamMatchLog) LogDsPacket(matchTimeSec float64, packetType int, dsConn *DriverStationConnection) {
-	log.logger.Printf("%f,%d,%d,%s,%v,%v,%v,%v,%v,%f,%d,%d", matchTimeSec, packetType, dsConn.TeamId,
-		dsConn.AllianceStation, dsConn.RadioLinked, dsConn.RobotLinked, dsConn.Auto, dsConn.Enabled, dsConn.Estop,
-		dsConn.BatteryVoltage, dsConn.MissedPacketCount, dsConn.DsRobotTripTimeMs)
+// Adds a line to the log when a packet is received, along with the latest status of the team's wireless network.
+func (log *TeamMatchLog) LogDsPacket(matchTimeSec float64, packetType int, dsConn *DriverStationConnection,
+	wireless WirelessStatus) {
+	log.logger.Printf("%f,%d,%d,%s,%v,%v,%v,%v,%v,%f,%d,%d,%v,%v,%d,%.1f,%.1f,%.1f", matchTimeSec, packetType,
+		dsConn.TeamId, dsConn.AllianceStation, dsConn.RadioLinked, dsConn.RobotLinked, dsConn.Auto, dsConn.Enabled,
+		dsConn.Estop, dsConn.BatteryVoltage, dsConn.MissedPacketCount, dsConn.DsRobotTripTimeMs,
+		wireless.SsidActive, wireless.Associated, wireless.SignalDbm, wireless.TxRateMbps, wireless.RxRateMbps,
+		wireless.ChannelUtilization)
 }
 
 func (log *TeamMatchLog) Close() {
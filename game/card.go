@@ -0,0 +1,61 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Rules for escalating the yellow and red cards issued to teams.
+
+package game
+
+import (
+	"sort"
+	"strconv"
+)
+
+const (
+	YellowCard = "yellow"
+	RedCard    = "red"
+)
+
+// Upgrades yellow cards issued to one alliance in a match to red cards where the team is already carrying a yellow
+// card from an earlier match, noting why in the card's reason. In the playoffs cards apply to the whole alliance, so
+// every team on an alliance carries its yellow card and two yellow cards in the same match are upgraded as well.
+// Returns the IDs of the teams whose cards were upgraded.
+func EscalateCards(cards map[string]string, reasons map[string]string, carriedYellowCards map[int]bool,
+	isPlayoff bool) []string {
+	allianceHasYellowCard := false
+	if isPlayoff {
+		yellowCards := 0
+		for _, card := range cards {
+			if card == YellowCard {
+				yellowCards++
+			}
+		}
+		allianceHasYellowCard = yellowCards > 1
+	}
+
+	var escalatedTeams []string
+	for _, teamId := range sortedKeys(cards) {
+		card := cards[teamId]
+		team, _ := strconv.Atoi(teamId)
+		if card == YellowCard && (carriedYellowCards[team] || allianceHasYellowCard) {
+			cards[teamId] = RedCard
+			escalatedTeams = append(escalatedTeams, teamId)
+			if reasons != nil {
+				if reasons[teamId] == "" {
+					reasons[teamId] = "Second yellow card"
+				} else {
+					reasons[teamId] = "Second yellow card: " + reasons[teamId]
+				}
+			}
+		}
+	}
+	return escalatedTeams
+}
+
+func sortedKeys(cards map[string]string) []string {
+	var keys []string
+	for key := range cards {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
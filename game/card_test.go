@@ -0,0 +1,33 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEscalateCards(t *testing.T) {
+	// A second yellow card in the qualifications is only upgraded for the team that already had one.
+	cards := map[string]string{"254": "yellow", "1114": "yellow", "2056": "red", "1678": ""}
+	reasons := map[string]string{"254": "G22 ramming", "1114": "G22 ramming"}
+	escalatedTeams := EscalateCards(cards, reasons, map[int]bool{254: true, 2056: true, 1678: true}, false)
+	assert.Equal(t, []string{"254"}, escalatedTeams)
+	assert.Equal(t, map[string]string{"254": "red", "1114": "yellow", "2056": "red", "1678": ""}, cards)
+	assert.Equal(t, "Second yellow card: G22 ramming", reasons["254"])
+	assert.Equal(t, "G22 ramming", reasons["1114"])
+
+	// Two yellow cards to the same playoff alliance in one match add up to a red card.
+	cards = map[string]string{"254": "yellow", "1114": "yellow", "2056": ""}
+	reasons = map[string]string{}
+	escalatedTeams = EscalateCards(cards, reasons, map[int]bool{}, true)
+	assert.Equal(t, []string{"1114", "254"}, escalatedTeams)
+	assert.Equal(t, map[string]string{"254": "red", "1114": "red", "2056": ""}, cards)
+	assert.Equal(t, "Second yellow card", reasons["254"])
+
+	// A single yellow card without any carried over stays yellow.
+	cards = map[string]string{"254": "yellow"}
+	assert.Empty(t, EscalateCards(cards, nil, map[int]bool{1114: true}, true))
+	assert.Equal(t, "yellow", cards["254"])
+}
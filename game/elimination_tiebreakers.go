@@ -0,0 +1,66 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Game-specific tiebreakers for deciding tied playoff matches.
+
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tiebreakers that can be chained in the event settings, keyed by the name that is recorded on a decided match.
+var ElimTiebreakers = map[string]string{
+	"foulPoints":    "Fewest foul points",
+	"autoPoints":    "Most auto points",
+	"rotorPoints":   "Most rotor points",
+	"takeoffPoints": "Most takeoff points",
+	"replay":        "Replay",
+}
+
+const DefaultElimTiebreakers = "foulPoints,autoPoints,rotorPoints,takeoffPoints,replay"
+
+// Parses the given comma-separated chain of tiebreaker names, returning an error if any of them is unknown.
+func ParseElimTiebreakers(chain string) ([]string, error) {
+	var tiebreakers []string
+	for _, tiebreaker := range strings.Split(chain, ",") {
+		tiebreaker = strings.TrimSpace(tiebreaker)
+		if tiebreaker == "" {
+			continue
+		}
+		if _, ok := ElimTiebreakers[tiebreaker]; !ok {
+			return nil, fmt.Errorf("Invalid elimination tiebreaker '%s'.", tiebreaker)
+		}
+		tiebreakers = append(tiebreakers, tiebreaker)
+	}
+	return tiebreakers, nil
+}
+
+// Applies the given chain of tiebreakers in order to a playoff match with tied scores. Returns the winner ("R", "B", or
+// "T" if the match must be replayed) and the name of the tiebreaker that decided it.
+func BreakElimTie(redSummary, blueSummary *ScoreSummary, tiebreakers []string) (string, string) {
+	for _, tiebreaker := range tiebreakers {
+		var redValue, blueValue int
+		switch tiebreaker {
+		case "foulPoints":
+			// An alliance's foul points are awarded for its opponent's fouls, so the alliance that committed fewer foul
+			// points is the one that received more.
+			redValue, blueValue = redSummary.FoulPoints, blueSummary.FoulPoints
+		case "autoPoints":
+			redValue, blueValue = redSummary.AutoPoints, blueSummary.AutoPoints
+		case "rotorPoints":
+			redValue, blueValue = redSummary.RotorPoints, blueSummary.RotorPoints
+		case "takeoffPoints":
+			redValue, blueValue = redSummary.TakeoffPoints, blueSummary.TakeoffPoints
+		case "replay":
+			return "T", tiebreaker
+		}
+		if redValue > blueValue {
+			return "R", tiebreaker
+		} else if blueValue > redValue {
+			return "B", tiebreaker
+		}
+	}
+	return "T", "replay"
+}
@@ -0,0 +1,60 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseElimTiebreakers(t *testing.T) {
+	tiebreakers, err := ParseElimTiebreakers(DefaultElimTiebreakers)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"foulPoints", "autoPoints", "rotorPoints", "takeoffPoints", "replay"}, tiebreakers)
+
+	tiebreakers, err = ParseElimTiebreakers(" takeoffPoints, autoPoints ")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"takeoffPoints", "autoPoints"}, tiebreakers)
+
+	tiebreakers, err = ParseElimTiebreakers("")
+	assert.Nil(t, err)
+	assert.Empty(t, tiebreakers)
+
+	_, err = ParseElimTiebreakers("autoPoints,coinFlip")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid elimination tiebreaker 'coinFlip'.", err.Error())
+	}
+}
+
+func TestBreakElimTie(t *testing.T) {
+	redSummary := &ScoreSummary{AutoPoints: 80, RotorPoints: 100, TakeoffPoints: 50, FoulPoints: 25, Score: 300}
+	blueSummary := &ScoreSummary{AutoPoints: 80, RotorPoints: 140, TakeoffPoints: 0, FoulPoints: 25, Score: 300}
+	chain := []string{"foulPoints", "autoPoints", "rotorPoints", "takeoffPoints", "replay"}
+
+	winner, tiebreaker := BreakElimTie(redSummary, blueSummary, chain)
+	assert.Equal(t, "B", winner)
+	assert.Equal(t, "rotorPoints", tiebreaker)
+
+	// The alliance whose opponent committed more fouls should win the first tiebreaker.
+	redSummary.FoulPoints = 30
+	winner, tiebreaker = BreakElimTie(redSummary, blueSummary, chain)
+	assert.Equal(t, "R", winner)
+	assert.Equal(t, "foulPoints", tiebreaker)
+
+	// Check that the order of the chain is respected.
+	winner, tiebreaker = BreakElimTie(redSummary, blueSummary, []string{"takeoffPoints", "foulPoints"})
+	assert.Equal(t, "R", winner)
+	assert.Equal(t, "takeoffPoints", tiebreaker)
+	winner, tiebreaker = BreakElimTie(redSummary, blueSummary, []string{"autoPoints", "replay", "rotorPoints"})
+	assert.Equal(t, "T", winner)
+	assert.Equal(t, "replay", tiebreaker)
+
+	// An exhausted chain should require a replay.
+	winner, tiebreaker = BreakElimTie(redSummary, blueSummary, []string{"autoPoints"})
+	assert.Equal(t, "T", winner)
+	assert.Equal(t, "replay", tiebreaker)
+	winner, tiebreaker = BreakElimTie(redSummary, blueSummary, []string{})
+	assert.Equal(t, "T", winner)
+	assert.Equal(t, "replay", tiebreaker)
+}
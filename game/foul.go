@@ -5,10 +5,15 @@
 
 package game
 
+// A foul called on a team. PenaltySet records whether Points and RankingPoint were fixed when the foul was called;
+// fouls recorded before that was done fall back to the penalties currently in effect.
 type Foul struct {
 	Rule
 	TeamId         int
 	TimeInMatchSec float64
+	Points         int
+	RankingPoint   bool
+	PenaltySet     bool
 }
 
 type Rule struct {
@@ -16,17 +21,47 @@ type Rule struct {
 	IsTechnical bool
 }
 
-// All rules from the 2017 game that carry point penalties.
-var Rules = []Rule{{"S08", false}, {"C08", false}, {"C11", false}, {"G04", false}, {"G05", false},
-	{"G08", false}, {"G09", false}, {"G11", false}, {"G11", true}, {"G12", false}, {"G13", true},
-	{"G15", false}, {"G17", false}, {"G20", false}, {"G22", false}, {"G23", false}, {"G26", true},
-	{"G27", false}, {"G27", true}, {"A01", false}, {"A02", false}, {"A04", false}, {"A04", true},
-	{"A05", true}, {"H06", false}, {"H07", false}, {"H08", false}, {"H11", false}, {"H11", true},
-	{"H12", true}, {"H13", false}}
+// Point values and ranking point penalties for fouls, which are loaded from the event's rule catalogue and can be
+// changed per event.
+var FoulPenalties = struct {
+	FoulPointValue     int
+	TechFoulPointValue int
+	RankingPointRules  map[Rule]bool
+}{5, 25, map[Rule]bool{}}
 
+// Returns a foul carrying the point value and ranking point penalty in effect for its rule at the time it is called,
+// so that later changes to the event's rules don't alter the results of matches that have already been played.
+func NewFoul(rule Rule, teamId int, timeInMatchSec float64) Foul {
+	foul := Foul{Rule: rule, TeamId: teamId, TimeInMatchSec: timeInMatchSec, PenaltySet: true}
+	foul.Points = foul.currentPointValue()
+	foul.RankingPoint = FoulPenalties.RankingPointRules[rule]
+	return foul
+}
+
+// Returns the number of points the foul awards to the opposing alliance, which may be zero.
 func (foul *Foul) PointValue() int {
+	if foul.PenaltySet {
+		return foul.Points
+	}
+	return foul.currentPointValue()
+}
+
+// Returns true if the given foul is the same call as this one, regardless of its point value.
+func (foul *Foul) IsSameCall(other *Foul) bool {
+	return foul.Rule == other.Rule && foul.TeamId == other.TeamId && foul.TimeInMatchSec == other.TimeInMatchSec
+}
+
+func (foul *Foul) currentPointValue() int {
 	if foul.IsTechnical {
-		return 25
+		return FoulPenalties.TechFoulPointValue
+	}
+	return FoulPenalties.FoulPointValue
+}
+
+// Returns true if the foul awards a ranking point to the opposing alliance.
+func (foul *Foul) AwardsRankingPoint() bool {
+	if foul.PenaltySet {
+		return foul.RankingPoint
 	}
-	return 5
+	return FoulPenalties.RankingPointRules[foul.Rule]
 }
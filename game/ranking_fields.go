@@ -55,6 +55,9 @@ func (fields *RankingFields) AddScoreSummary(ownScore *ScoreSummary, opponentSco
 	if ownScore.RotorGoalReached {
 		fields.RankingPoints += 1
 	}
+	if ownScore.FoulRankingPoint {
+		fields.RankingPoints += 1
+	}
 
 	// Assign tiebreaker points.
 	fields.MatchPoints += ownScore.Score
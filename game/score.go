@@ -29,6 +29,7 @@ type ScoreSummary struct {
 	Score               int
 	PressureGoalReached bool
 	RotorGoalReached    bool
+	FoulRankingPoint    bool
 }
 
 // Calculates and returns the summary fields used for ranking and display.
@@ -67,6 +68,9 @@ func (score *Score) Summarize(opponentFouls []Foul, matchType string) *ScoreSumm
 	// Calculate penalty points.
 	for _, foul := range opponentFouls {
 		summary.FoulPoints += foul.PointValue()
+		if foul.AwardsRankingPoint() {
+			summary.FoulRankingPoint = true
+		}
 	}
 
 	summary.Score = summary.AutoMobilityPoints + summary.RotorPoints + summary.TakeoffPoints + summary.PressurePoints +
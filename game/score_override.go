@@ -0,0 +1,117 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model of manual corrections made by the head referee to the sensor-derived parts of the score.
+
+package game
+
+import "fmt"
+
+// Score elements that are counted by the field sensors and can be overridden, keyed by their field name in Score.
+var OverridableScoreElements = map[string]string{
+	"AutoFuelHigh": "Auto high fuel",
+	"AutoFuelLow":  "Auto low fuel",
+	"AutoRotors":   "Auto rotors",
+	"FuelHigh":     "Teleop high fuel",
+	"FuelLow":      "Teleop low fuel",
+	"Rotors":       "Teleop rotors",
+	"Takeoffs":     "Takeoffs",
+}
+
+// A value entered by the head referee in place of what the field sensors counted for one element of the score.
+type ScoreOverride struct {
+	Element        string
+	Value          int
+	OriginalValue  int
+	Reason         string
+	TimeInMatchSec float64
+	PostMatch      bool
+}
+
+// Returns a new override of the given element of the score, recording its current value as the original.
+func NewScoreOverride(score *Score, element string, value int, reason string,
+	timeInMatchSec float64) (*ScoreOverride, error) {
+	field := score.overridableField(element)
+	if field == nil {
+		return nil, fmt.Errorf("Invalid score element '%s'.", element)
+	}
+	if value < 0 {
+		return nil, fmt.Errorf("Override value must be a non-negative integer.")
+	}
+	if reason == "" {
+		return nil, fmt.Errorf("A reason is required to override the score.")
+	}
+	return &ScoreOverride{element, value, *field, reason, timeInMatchSec, false}, nil
+}
+
+// Replaces the value of the overridden element in the given score.
+func (override *ScoreOverride) Apply(score *Score) {
+	if field := score.overridableField(override.Element); field != nil {
+		*field = override.Value
+	}
+}
+
+// Records the value that the sensors have most recently counted for the overridden element in the given score as the
+// original.
+func (override *ScoreOverride) UpdateOriginalValue(score *Score) {
+	if field := score.overridableField(override.Element); field != nil {
+		override.OriginalValue = *field
+	}
+}
+
+// Restores the original sensor value of the overridden element in the given score.
+func (override *ScoreOverride) Revert(score *Score) {
+	if field := score.overridableField(override.Element); field != nil {
+		*field = override.OriginalValue
+	}
+}
+
+// Adds the given amount to a sensor-counted element of the score, such as when the field sensors are unavailable and
+// it is being counted by hand. The value won't go below zero.
+func (score *Score) AdjustSensorElement(element string, delta int) error {
+	field := score.overridableField(element)
+	if field == nil {
+		return fmt.Errorf("Invalid score element '%s'.", element)
+	}
+	*field += delta
+	if *field < 0 {
+		*field = 0
+	}
+	return nil
+}
+
+// Copies the values of all of the sensor-counted elements of the other score into this one.
+func (score *Score) CopySensorElements(other *Score) {
+	for element := range OverridableScoreElements {
+		score.CopySensorElement(element, other)
+	}
+}
+
+// Copies the value of the given sensor-counted element of the other score into this one.
+func (score *Score) CopySensorElement(element string, other *Score) {
+	if field := score.overridableField(element); field != nil {
+		*field = *other.overridableField(element)
+	}
+}
+
+// Returns a pointer to the field of the score that corresponds to the given element, or nil if it can't be
+// overridden.
+func (score *Score) overridableField(element string) *int {
+	switch element {
+	case "AutoFuelHigh":
+		return &score.AutoFuelHigh
+	case "AutoFuelLow":
+		return &score.AutoFuelLow
+	case "AutoRotors":
+		return &score.AutoRotors
+	case "FuelHigh":
+		return &score.FuelHigh
+	case "FuelLow":
+		return &score.FuelLow
+	case "Rotors":
+		return &score.Rotors
+	case "Takeoffs":
+		return &score.Takeoffs
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewScoreOverride(t *testing.T) {
+	score := TestScore1()
+
+	override, err := NewScoreOverride(score, "FuelHigh", 40, "Boiler sensor jammed", 95.5)
+	assert.Nil(t, err)
+	assert.Equal(t, ScoreOverride{"FuelHigh", 40, score.FuelHigh, "Boiler sensor jammed", 95.5, false}, *override)
+
+	_, err = NewScoreOverride(score, "AutoMobility", 3, "Not a sensor", 0)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid score element 'AutoMobility'.", err.Error())
+	}
+	_, err = NewScoreOverride(score, "Rotors", -1, "Negative", 0)
+	assert.NotNil(t, err)
+	_, err = NewScoreOverride(score, "Takeoffs", 2, "", 0)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "reason is required")
+	}
+}
+
+func TestScoreOverrideApplyAndRevert(t *testing.T) {
+	score := &Score{AutoRotors: 1, Rotors: 1, Takeoffs: 2}
+	for element := range OverridableScoreElements {
+		override, err := NewScoreOverride(score, element, 7, "Test", 0)
+		assert.Nil(t, err)
+		override.Apply(score)
+		assert.Equal(t, 7, *score.overridableField(element))
+		override.Revert(score)
+		assert.Equal(t, override.OriginalValue, *score.overridableField(element))
+	}
+	assert.Equal(t, Score{AutoRotors: 1, Rotors: 1, Takeoffs: 2}, *score)
+}
+
+func TestAdjustAndCopySensorElements(t *testing.T) {
+	score := &Score{AutoMobility: 2, FuelHigh: 3}
+	assert.Nil(t, score.AdjustSensorElement("FuelHigh", 5))
+	assert.Nil(t, score.AdjustSensorElement("Rotors", -1))
+	assert.Nil(t, score.AdjustSensorElement("Takeoffs", 1))
+	assert.NotNil(t, score.AdjustSensorElement("AutoMobility", 1))
+	assert.Equal(t, Score{AutoMobility: 2, FuelHigh: 8, Takeoffs: 1}, *score)
+
+	otherScore := &Score{AutoMobility: 1, AutoFuelLow: 4, Rotors: 2, Fouls: []Foul{{}}}
+	score.CopySensorElements(otherScore)
+	assert.Equal(t, Score{AutoMobility: 2, AutoFuelLow: 4, Rotors: 2}, *score)
+
+	score.CopySensorElement("AutoFuelLow", &Score{AutoFuelLow: 7, Rotors: 3})
+	score.CopySensorElement("AutoMobility", &Score{AutoMobility: 3})
+	assert.Equal(t, Score{AutoMobility: 2, AutoFuelLow: 7, Rotors: 2}, *score)
+}
@@ -72,6 +72,49 @@ func TestScoreSummary(t *testing.T) {
 	assert.Equal(t, 0, blueScore.Summarize(redScore.Fouls, "elimination").Score)
 }
 
+func TestScoreSummaryConfiguredFoulPenalties(t *testing.T) {
+	defer func() {
+		FoulPenalties.FoulPointValue = 5
+		FoulPenalties.TechFoulPointValue = 25
+		FoulPenalties.RankingPointRules = map[Rule]bool{}
+	}()
+	redScore := TestScore1()
+	blueScore := TestScore2()
+
+	FoulPenalties.FoulPointValue = 3
+	FoulPenalties.TechFoulPointValue = 15
+	blueSummary := blueScore.Summarize(redScore.Fouls, "qualification")
+	assert.Equal(t, 33, blueSummary.FoulPoints)
+	assert.Equal(t, 411, blueSummary.Score)
+	assert.False(t, blueSummary.FoulRankingPoint)
+
+	// Only a foul for the same rule and severity should award the ranking point.
+	FoulPenalties.RankingPointRules = map[Rule]bool{{"G22", true}: true}
+	assert.False(t, blueScore.Summarize(redScore.Fouls, "qualification").FoulRankingPoint)
+	FoulPenalties.RankingPointRules = map[Rule]bool{{"G22", false}: true}
+	assert.True(t, blueScore.Summarize(redScore.Fouls, "qualification").FoulRankingPoint)
+	assert.False(t, redScore.Summarize(blueScore.Fouls, "qualification").FoulRankingPoint)
+
+	// A foul should keep the penalties it was called with when the event's rules are changed afterwards.
+	blueScore.Fouls = []Foul{NewFoul(Rule{"G22", false}, 254, 12), NewFoul(Rule{"G26", true}, 254, 40)}
+	assert.Equal(t, 3, blueScore.Fouls[0].Points)
+	assert.Equal(t, 15, blueScore.Fouls[1].Points)
+	FoulPenalties.FoulPointValue = 5
+	FoulPenalties.TechFoulPointValue = 25
+	FoulPenalties.RankingPointRules = map[Rule]bool{}
+	redSummary := redScore.Summarize(blueScore.Fouls, "qualification")
+	assert.Equal(t, 18, redSummary.FoulPoints)
+	assert.True(t, redSummary.FoulRankingPoint)
+
+	// A foul called while its point value is zero should stay worth nothing.
+	FoulPenalties.FoulPointValue = 0
+	blueScore.Fouls = []Foul{NewFoul(Rule{"G22", false}, 254, 12)}
+	FoulPenalties.FoulPointValue = 5
+	redSummary = redScore.Summarize(blueScore.Fouls, "qualification")
+	assert.Equal(t, 0, redSummary.FoulPoints)
+	assert.False(t, redSummary.FoulRankingPoint)
+}
+
 func TestScoreEquals(t *testing.T) {
 	score1 := TestScore1()
 	score2 := TestScore1()
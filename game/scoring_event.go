@@ -0,0 +1,122 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model of the timestamped scoring events that make up the timeline of a match.
+
+package game
+
+import "fmt"
+
+// Sources of scoring events.
+const (
+	SensorSource  = "sensor"
+	ScorerSource  = "scorer"
+	RefereeSource = "referee"
+	ManualSource  = "manual"
+)
+
+// A single change to an alliance's score, recorded so that the flow of the match can be reconstructed afterwards.
+type ScoringEvent struct {
+	TimeInMatchSec float64
+	Source         string
+	Element        string
+	Value          int
+	Description    string
+}
+
+// Returns the events describing the differences between the old and new versions of an alliance's score.
+func ScoreChangeEvents(oldScore, newScore *Score, timeInMatchSec float64) []ScoringEvent {
+	var events []ScoringEvent
+	addEvent := func(source, element string, value int, description string) {
+		events = append(events, ScoringEvent{timeInMatchSec, source, element, value, description})
+	}
+
+	if newScore.AutoMobility != oldScore.AutoMobility {
+		addEvent(ScorerSource, "AutoMobility", newScore.AutoMobility,
+			fmt.Sprintf("Auto mobility: %d robots", newScore.AutoMobility))
+	}
+	if newScore.AutoFuelHigh != oldScore.AutoFuelHigh {
+		addEvent(SensorSource, "AutoFuelHigh", newScore.AutoFuelHigh,
+			fmt.Sprintf("Auto high fuel: %d", newScore.AutoFuelHigh))
+	}
+	if newScore.AutoFuelLow != oldScore.AutoFuelLow {
+		addEvent(SensorSource, "AutoFuelLow", newScore.AutoFuelLow,
+			fmt.Sprintf("Auto low fuel: %d", newScore.AutoFuelLow))
+	}
+	if newScore.FuelHigh != oldScore.FuelHigh {
+		addEvent(SensorSource, "FuelHigh", newScore.FuelHigh, fmt.Sprintf("Teleop high fuel: %d", newScore.FuelHigh))
+	}
+	if newScore.FuelLow != oldScore.FuelLow {
+		addEvent(SensorSource, "FuelLow", newScore.FuelLow, fmt.Sprintf("Teleop low fuel: %d", newScore.FuelLow))
+	}
+	oldRotors := oldScore.AutoRotors + oldScore.Rotors
+	newRotors := newScore.AutoRotors + newScore.Rotors
+	if newScore.AutoRotors > oldScore.AutoRotors && newRotors > oldRotors {
+		addEvent(SensorSource, "AutoRotors", newRotors, fmt.Sprintf("Rotor %d turning (auto)", newRotors))
+	} else if newRotors > oldRotors {
+		addEvent(SensorSource, "Rotors", newRotors, fmt.Sprintf("Rotor %d turning", newRotors))
+	} else if newRotors < oldRotors {
+		addEvent(SensorSource, "Rotors", newRotors, fmt.Sprintf("Rotors turning: %d", newRotors))
+	}
+	if newScore.Takeoffs != oldScore.Takeoffs {
+		addEvent(SensorSource, "Takeoffs", newScore.Takeoffs, fmt.Sprintf("Takeoffs: %d", newScore.Takeoffs))
+	}
+	for _, foul := range foulDifference(newScore.Fouls, oldScore.Fouls) {
+		addEvent(RefereeSource, "Foul", foul.TeamId, fmt.Sprintf("%s on %d", foul.description(), foul.TeamId))
+	}
+	for _, foul := range foulDifference(oldScore.Fouls, newScore.Fouls) {
+		addEvent(RefereeSource, "Foul", foul.TeamId, fmt.Sprintf("%s on %d removed", foul.description(),
+			foul.TeamId))
+	}
+	return events
+}
+
+// Returns the event for the given touchpad having changed state, or nil if it didn't change in a way that matters.
+func TouchpadChangeEvent(touchpadIndex, oldState, newState int, timeInMatchSec float64) *ScoringEvent {
+	if oldState == newState {
+		return nil
+	}
+	if newState == Held {
+		return &ScoringEvent{timeInMatchSec, SensorSource, "Touchpad", touchpadIndex + 1,
+			fmt.Sprintf("Touchpad %d held", touchpadIndex+1)}
+	}
+	if oldState == Held {
+		return &ScoringEvent{timeInMatchSec, SensorSource, "Touchpad", touchpadIndex + 1,
+			fmt.Sprintf("Touchpad %d released", touchpadIndex+1)}
+	}
+	return nil
+}
+
+// Returns the time of the event since the start of the match, formatted as minutes and seconds.
+func (event ScoringEvent) MatchTime() string {
+	seconds := int(event.TimeInMatchSec)
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+func (foul *Foul) description() string {
+	if foul.IsTechnical {
+		return fmt.Sprintf("Tech foul %s", foul.RuleNumber)
+	}
+	return fmt.Sprintf("Foul %s", foul.RuleNumber)
+}
+
+// Returns the fouls in the first list that don't have a counterpart in the second.
+func foulDifference(fouls, otherFouls []Foul) []Foul {
+	remaining := make([]Foul, len(otherFouls))
+	copy(remaining, otherFouls)
+	var difference []Foul
+	for _, foul := range fouls {
+		found := false
+		for i, otherFoul := range remaining {
+			if foul == otherFoul {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			difference = append(difference, foul)
+		}
+	}
+	return difference
+}
@@ -0,0 +1,60 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestScoreChangeEvents(t *testing.T) {
+	oldScore := &Score{}
+	assert.Empty(t, ScoreChangeEvents(oldScore, &Score{}, 10))
+
+	newScore := &Score{AutoMobility: 2, AutoFuelHigh: 5, AutoRotors: 1}
+	events := ScoreChangeEvents(oldScore, newScore, 12.5)
+	if assert.Equal(t, 3, len(events)) {
+		assert.Equal(t, ScoringEvent{12.5, ScorerSource, "AutoMobility", 2, "Auto mobility: 2 robots"}, events[0])
+		assert.Equal(t, ScoringEvent{12.5, SensorSource, "AutoFuelHigh", 5, "Auto high fuel: 5"}, events[1])
+		assert.Equal(t, ScoringEvent{12.5, SensorSource, "AutoRotors", 1, "Rotor 1 turning (auto)"}, events[2])
+	}
+
+	oldScore = newScore
+	newScore = &Score{AutoMobility: 2, AutoFuelHigh: 5, AutoRotors: 1, Rotors: 2, FuelLow: 9, Takeoffs: 1}
+	events = ScoreChangeEvents(oldScore, newScore, 102)
+	if assert.Equal(t, 3, len(events)) {
+		assert.Equal(t, "Teleop low fuel: 9", events[0].Description)
+		assert.Equal(t, ScoringEvent{102, SensorSource, "Rotors", 3, "Rotor 3 turning"}, events[1])
+		assert.Equal(t, ScoringEvent{102, SensorSource, "Takeoffs", 1, "Takeoffs: 1"}, events[2])
+	}
+
+	// Check that fouls are matched up individually when added and removed.
+	oldScore = &Score{Fouls: []Foul{{Rule{"G22", false}, 254, 20, 0, false, false}}}
+	newScore = &Score{Fouls: []Foul{{Rule{"G22", false}, 254, 20, 0, false, false},
+		{Rule{"G26", true}, 1114, 30, 0, false, false}}}
+	events = ScoreChangeEvents(oldScore, newScore, 30)
+	if assert.Equal(t, 1, len(events)) {
+		assert.Equal(t, ScoringEvent{30, RefereeSource, "Foul", 1114, "Tech foul G26 on 1114"}, events[0])
+	}
+	events = ScoreChangeEvents(newScore, oldScore, 40)
+	if assert.Equal(t, 1, len(events)) {
+		assert.Equal(t, "Tech foul G26 on 1114 removed", events[0].Description)
+	}
+}
+
+func TestTouchpadChangeEvent(t *testing.T) {
+	assert.Nil(t, TouchpadChangeEvent(0, NotTriggered, NotTriggered, 140))
+	assert.Nil(t, TouchpadChangeEvent(0, NotTriggered, Triggered, 140))
+	assert.Nil(t, TouchpadChangeEvent(0, Triggered, NotTriggered, 140))
+	assert.Equal(t, &ScoringEvent{141, SensorSource, "Touchpad", 2, "Touchpad 2 held"},
+		TouchpadChangeEvent(1, Triggered, Held, 141))
+	assert.Equal(t, &ScoringEvent{145, SensorSource, "Touchpad", 3, "Touchpad 3 released"},
+		TouchpadChangeEvent(2, Held, NotTriggered, 145))
+}
+
+func TestScoringEventMatchTime(t *testing.T) {
+	assert.Equal(t, "0:00", ScoringEvent{TimeInMatchSec: 0.4}.MatchTime())
+	assert.Equal(t, "0:09", ScoringEvent{TimeInMatchSec: 9.99}.MatchTime())
+	assert.Equal(t, "1:42", ScoringEvent{TimeInMatchSec: 102.3}.MatchTime())
+}
@@ -6,8 +6,8 @@
 package game
 
 func TestScore1() *Score {
-	fouls := []Foul{{Rule{"G22", false}, 25, 25.2}, {Rule{"G18", true}, 25, 150},
-		{Rule{"G20", true}, 1868, 0}}
+	fouls := []Foul{{Rule{"G22", false}, 25, 25.2, 0, false, false}, {Rule{"G18", true}, 25, 150, 0, false, false},
+		{Rule{"G20", true}, 1868, 0, 0, false, false}}
 	return &Score{0, 1, 2, 20, 1, 12, 55, 1, fouls, false}
 }
 
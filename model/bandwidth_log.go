@@ -0,0 +1,126 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for the bandwidth usage of a team's robot over the course of a match.
+
+package model
+
+import (
+	"encoding/json"
+)
+
+type BandwidthLog struct {
+	Id              int
+	MatchId         int
+	TeamId          int
+	AllianceStation string
+	Samples         []BandwidthSample
+}
+
+type BandwidthLogDb struct {
+	Id              int
+	MatchId         int
+	TeamId          int
+	AllianceStation string
+	SamplesJson     string
+}
+
+type BandwidthSample struct {
+	MatchTimeSec  float64
+	MBpsToRobot   float64
+	MBpsFromRobot float64
+}
+
+type BandwidthSummary struct {
+	PeakMBpsToRobot      float64
+	AverageMBpsToRobot   float64
+	PeakMBpsFromRobot    float64
+	AverageMBpsFromRobot float64
+}
+
+// Saves the given log, replacing any existing one from an earlier play of the same match in the same station.
+func (database *Database) SaveBandwidthLog(bandwidthLog *BandwidthLog) error {
+	bandwidthLogDb, err := bandwidthLog.serialize()
+	if err != nil {
+		return err
+	}
+	_, err = database.bandwidthLogMap.Exec("DELETE FROM bandwidth_logs WHERE matchid = ? AND alliancestation = ?",
+		bandwidthLog.MatchId, bandwidthLog.AllianceStation)
+	if err != nil {
+		return err
+	}
+	bandwidthLogDb.Id = 0
+	err = database.bandwidthLogMap.Insert(bandwidthLogDb)
+	if err != nil {
+		return err
+	}
+	bandwidthLog.Id = bandwidthLogDb.Id
+	return nil
+}
+
+func (database *Database) GetBandwidthLogsForMatch(matchId int) ([]BandwidthLog, error) {
+	var bandwidthLogDbs []BandwidthLogDb
+	err := database.bandwidthLogMap.Select(&bandwidthLogDbs,
+		"SELECT * FROM bandwidth_logs WHERE matchid = ? ORDER BY id", matchId)
+	if err != nil {
+		return nil, err
+	}
+	return deserializeBandwidthLogs(bandwidthLogDbs)
+}
+
+func (database *Database) GetAllBandwidthLogs() ([]BandwidthLog, error) {
+	var bandwidthLogDbs []BandwidthLogDb
+	err := database.bandwidthLogMap.Select(&bandwidthLogDbs,
+		"SELECT * FROM bandwidth_logs ORDER BY matchid, id")
+	if err != nil {
+		return nil, err
+	}
+	return deserializeBandwidthLogs(bandwidthLogDbs)
+}
+
+func (database *Database) TruncateBandwidthLogs() error {
+	return database.bandwidthLogMap.TruncateTables()
+}
+
+// Calculates the peak and average bandwidth in each direction over the whole log.
+func (bandwidthLog *BandwidthLog) Summarize() BandwidthSummary {
+	var summary BandwidthSummary
+	for _, sample := range bandwidthLog.Samples {
+		if sample.MBpsToRobot > summary.PeakMBpsToRobot {
+			summary.PeakMBpsToRobot = sample.MBpsToRobot
+		}
+		if sample.MBpsFromRobot > summary.PeakMBpsFromRobot {
+			summary.PeakMBpsFromRobot = sample.MBpsFromRobot
+		}
+		summary.AverageMBpsToRobot += sample.MBpsToRobot
+		summary.AverageMBpsFromRobot += sample.MBpsFromRobot
+	}
+	if len(bandwidthLog.Samples) > 0 {
+		summary.AverageMBpsToRobot /= float64(len(bandwidthLog.Samples))
+		summary.AverageMBpsFromRobot /= float64(len(bandwidthLog.Samples))
+	}
+	return summary
+}
+
+// Converts the nested struct BandwidthLog to the DB version that has JSON fields.
+func (bandwidthLog *BandwidthLog) serialize() (*BandwidthLogDb, error) {
+	bandwidthLogDb := BandwidthLogDb{Id: bandwidthLog.Id, MatchId: bandwidthLog.MatchId, TeamId: bandwidthLog.TeamId,
+		AllianceStation: bandwidthLog.AllianceStation}
+	if err := serializeHelper(&bandwidthLogDb.SamplesJson, bandwidthLog.Samples); err != nil {
+		return nil, err
+	}
+	return &bandwidthLogDb, nil
+}
+
+// Converts the DB BandwidthLogs with JSON fields to the nested struct version.
+func deserializeBandwidthLogs(bandwidthLogDbs []BandwidthLogDb) ([]BandwidthLog, error) {
+	bandwidthLogs := make([]BandwidthLog, len(bandwidthLogDbs))
+	for i, bandwidthLogDb := range bandwidthLogDbs {
+		bandwidthLogs[i] = BandwidthLog{Id: bandwidthLogDb.Id, MatchId: bandwidthLogDb.MatchId,
+			TeamId: bandwidthLogDb.TeamId, AllianceStation: bandwidthLogDb.AllianceStation}
+		if err := json.Unmarshal([]byte(bandwidthLogDb.SamplesJson), &bandwidthLogs[i].Samples); err != nil {
+			return nil, err
+		}
+	}
+	return bandwidthLogs, nil
+}
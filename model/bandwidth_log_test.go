@@ -0,0 +1,52 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBandwidthLogCrud(t *testing.T) {
+	db := setupTestDb(t)
+
+	logs, err := db.GetBandwidthLogsForMatch(1)
+	assert.Nil(t, err)
+	assert.Empty(t, logs)
+
+	log1 := BandwidthLog{MatchId: 1, TeamId: 254, AllianceStation: "R1",
+		Samples: []BandwidthSample{{1, 2.5, 0.5}, {2, 3.5, 1.5}}}
+	assert.Nil(t, db.SaveBandwidthLog(&log1))
+	log2 := BandwidthLog{MatchId: 1, TeamId: 1114, AllianceStation: "B2", Samples: []BandwidthSample{{1, 1, 1}}}
+	assert.Nil(t, db.SaveBandwidthLog(&log2))
+	log3 := BandwidthLog{MatchId: 2, TeamId: 254, AllianceStation: "B3", Samples: []BandwidthSample{}}
+	assert.Nil(t, db.SaveBandwidthLog(&log3))
+	logs, err = db.GetBandwidthLogsForMatch(1)
+	assert.Nil(t, err)
+	assert.Equal(t, []BandwidthLog{log1, log2}, logs)
+
+	// Check that saving a log for a replay of the match replaces the old one for the same station.
+	log4 := BandwidthLog{MatchId: 1, TeamId: 148, AllianceStation: "R1", Samples: []BandwidthSample{{1, 5, 5}}}
+	assert.Nil(t, db.SaveBandwidthLog(&log4))
+	logs, err = db.GetBandwidthLogsForMatch(1)
+	assert.Nil(t, err)
+	assert.Equal(t, []BandwidthLog{log2, log4}, logs)
+	logs, err = db.GetAllBandwidthLogs()
+	assert.Nil(t, err)
+	assert.Equal(t, []BandwidthLog{log2, log4, log3}, logs)
+
+	db.TruncateBandwidthLogs()
+	logs, err = db.GetAllBandwidthLogs()
+	assert.Nil(t, err)
+	assert.Empty(t, logs)
+}
+
+func TestBandwidthLogSummarize(t *testing.T) {
+	bandwidthLog := BandwidthLog{}
+	assert.Equal(t, BandwidthSummary{}, bandwidthLog.Summarize())
+
+	bandwidthLog.Samples = []BandwidthSample{{1, 2, 0.5}, {2, 4, 1.5}, {3, 3, 0.25}}
+	assert.Equal(t, BandwidthSummary{PeakMBpsToRobot: 4, AverageMBpsToRobot: 3, PeakMBpsFromRobot: 1.5,
+		AverageMBpsFromRobot: 0.75}, bandwidthLog.Summarize())
+}
@@ -35,6 +35,10 @@ type Database struct {
 	allianceTeamMap  *modl.DbMap
 	lowerThirdMap    *modl.DbMap
 	sponsorSlideMap  *modl.DbMap
+	sensorFilterMap  *modl.DbMap
+	bandwidthLogMap  *modl.DbMap
+	ruleMap          *modl.DbMap
+	scheduleBlockMap *modl.DbMap
 }
 
 // Opens the SQLite database at the given path, creating it if it doesn't exist, and runs any pending
@@ -120,6 +124,18 @@ func (database *Database) mapTables() {
 
 	database.sponsorSlideMap = modl.NewDbMap(database.db, dialect)
 	database.sponsorSlideMap.AddTableWithName(SponsorSlide{}, "sponsor_slides").SetKeys(true, "Id")
+
+	database.sensorFilterMap = modl.NewDbMap(database.db, dialect)
+	database.sensorFilterMap.AddTableWithName(SensorFilter{}, "sensor_filters").SetKeys(true, "Id")
+
+	database.bandwidthLogMap = modl.NewDbMap(database.db, dialect)
+	database.bandwidthLogMap.AddTableWithName(BandwidthLogDb{}, "bandwidth_logs").SetKeys(true, "Id")
+
+	database.ruleMap = modl.NewDbMap(database.db, dialect)
+	database.ruleMap.AddTableWithName(Rule{}, "rules").SetKeys(true, "Id")
+
+	database.scheduleBlockMap = modl.NewDbMap(database.db, dialect)
+	database.scheduleBlockMap.AddTableWithName(ScheduleBlock{}, "schedule_blocks").SetKeys(true, "Id")
 }
 
 func serializeHelper(target *string, source interface{}) error {
@@ -5,33 +5,52 @@
 
 package model
 
+import "github.com/Team254/cheesy-arena/game"
+
 type EventSettings struct {
-	Id                         int
-	Name                       string
-	DisplayBackgroundColor     string
-	NumElimAlliances           int
-	SelectionRound2Order       string
-	SelectionRound3Order       string
-	TBADownloadEnabled         bool
-	TbaPublishingEnabled       bool
-	TbaEventCode               string
-	TbaSecretId                string
-	TbaSecret                  string
-	NetworkSecurityEnabled     bool
-	ApAddress                  string
-	ApUsername                 string
-	ApPassword                 string
-	ApTeamChannel              int
-	ApAdminChannel             int
-	ApAdminWpaKey              string
-	SwitchAddress              string
-	SwitchPassword             string
-	BandwidthMonitoringEnabled bool
-	PlcAddress                 string
-	AdminPassword              string
-	ReaderPassword             string
-	StemTvPublishingEnabled    bool
-	StemTvEventCode            string
+	Id                           int
+	Name                         string
+	DisplayBackgroundColor       string
+	NumElimAlliances             int
+	ElimType                     string
+	RoundRobinMatchesPerAlliance int
+	ElimTiebreakers              string
+	SelectionRound2Order         string
+	SelectionRound3Order         string
+	TBADownloadEnabled           bool
+	TbaPublishingEnabled         bool
+	TbaEventCode                 string
+	TbaSecretId                  string
+	TbaSecret                    string
+	NetworkSecurityEnabled       bool
+	ApType                       string
+	ApAddress                    string
+	ApUsername                   string
+	ApPassword                   string
+	ApTeamChannel                int
+	ApAdminChannel               int
+	ApAdminWpaKey                string
+	SwitchType                   string
+	SwitchTransport              string
+	SwitchAddress                string
+	SwitchUsername               string
+	SwitchPassword               string
+	SwitchDryRun                 bool
+	BandwidthMonitoringEnabled   bool
+	BandwidthAlertMbps           float64
+	Red1SwitchPort               int
+	Red2SwitchPort               int
+	Red3SwitchPort               int
+	Blue1SwitchPort              int
+	Blue2SwitchPort              int
+	Blue3SwitchPort              int
+	PlcAddress                   string
+	FoulPointValue               int
+	TechFoulPointValue           int
+	AdminPassword                string
+	ReaderPassword               string
+	StemTvPublishingEnabled      bool
+	StemTvEventCode              string
 }
 
 const eventSettingsId = 0
@@ -44,12 +63,27 @@ func (database *Database) GetEventSettings() (*EventSettings, error) {
 		eventSettings.Name = "Untitled Event"
 		eventSettings.DisplayBackgroundColor = "#00ff00"
 		eventSettings.NumElimAlliances = 8
+		eventSettings.ElimType = "single"
+		eventSettings.RoundRobinMatchesPerAlliance = 6
+		eventSettings.ElimTiebreakers = game.DefaultElimTiebreakers
 		eventSettings.SelectionRound2Order = "L"
 		eventSettings.SelectionRound3Order = ""
 		eventSettings.TBADownloadEnabled = true
+		eventSettings.ApType = "openwrt"
 		eventSettings.ApTeamChannel = 157
 		eventSettings.ApAdminChannel = 11
 		eventSettings.ApAdminWpaKey = "1234Five"
+		eventSettings.SwitchType = "cisco"
+		eventSettings.SwitchTransport = "telnet"
+		eventSettings.BandwidthAlertMbps = 7
+		eventSettings.Red1SwitchPort = 6
+		eventSettings.Red2SwitchPort = 8
+		eventSettings.Red3SwitchPort = 10
+		eventSettings.Blue1SwitchPort = 12
+		eventSettings.Blue2SwitchPort = 14
+		eventSettings.Blue3SwitchPort = 16
+		eventSettings.FoulPointValue = 5
+		eventSettings.TechFoulPointValue = 25
 
 		err = database.eventSettingsMap.Insert(eventSettings)
 		if err != nil {
@@ -4,6 +4,7 @@
 package model
 
 import (
+	"github.com/Team254/cheesy-arena/game"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -14,12 +15,18 @@ func TestEventSettingsReadWrite(t *testing.T) {
 	eventSettings, err := db.GetEventSettings()
 	assert.Nil(t, err)
 	assert.Equal(t, EventSettings{Id: 0, Name: "Untitled Event", DisplayBackgroundColor: "#00ff00",
-		NumElimAlliances: 8, SelectionRound2Order: "L", SelectionRound3Order: "", TBADownloadEnabled: true,
-		ApTeamChannel: 157, ApAdminChannel: 11, ApAdminWpaKey: "1234Five"}, *eventSettings)
+		NumElimAlliances: 8, ElimType: "single", RoundRobinMatchesPerAlliance: 6,
+		ElimTiebreakers: game.DefaultElimTiebreakers, SelectionRound2Order: "L", SelectionRound3Order: "",
+		TBADownloadEnabled: true, ApType: "openwrt", ApTeamChannel: 157, ApAdminChannel: 11, ApAdminWpaKey: "1234Five",
+		SwitchType: "cisco", SwitchTransport: "telnet", BandwidthAlertMbps: 7, Red1SwitchPort: 6, Red2SwitchPort: 8,
+		Red3SwitchPort: 10, Blue1SwitchPort: 12, Blue2SwitchPort: 14, Blue3SwitchPort: 16, FoulPointValue: 5,
+		TechFoulPointValue: 25}, *eventSettings)
 
 	eventSettings.Name = "Chezy Champs"
 	eventSettings.DisplayBackgroundColor = "#ff00ff"
 	eventSettings.NumElimAlliances = 6
+	eventSettings.ElimType = "roundRobin"
+	eventSettings.RoundRobinMatchesPerAlliance = 3
 	eventSettings.SelectionRound2Order = "F"
 	eventSettings.SelectionRound3Order = "L"
 	err = db.SaveEventSettings(eventSettings)
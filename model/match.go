@@ -12,28 +12,32 @@ import (
 )
 
 type Match struct {
-	Id               int
-	Type             string
-	DisplayName      string
-	Time             time.Time
-	ElimRound        int
-	ElimGroup        int
-	ElimInstance     int
-	Red1             int
-	Red1IsSurrogate  bool
-	Red2             int
-	Red2IsSurrogate  bool
-	Red3             int
-	Red3IsSurrogate  bool
-	Blue1            int
-	Blue1IsSurrogate bool
-	Blue2            int
-	Blue2IsSurrogate bool
-	Blue3            int
-	Blue3IsSurrogate bool
-	Status           string
-	StartedAt        time.Time
-	Winner           string
+	Id                int
+	Type              string
+	DisplayName       string
+	Time              time.Time
+	ElimRound         int
+	ElimGroup         int
+	ElimInstance      int
+	Red1              int
+	Red1IsSurrogate   bool
+	Red2              int
+	Red2IsSurrogate   bool
+	Red3              int
+	Red3IsSurrogate   bool
+	Blue1             int
+	Blue1IsSurrogate  bool
+	Blue2             int
+	Blue2IsSurrogate  bool
+	Blue3             int
+	Blue3IsSurrogate  bool
+	Status            string
+	StartedAt         time.Time
+	Winner            string
+	Tiebreaker        string
+	EndedAt           time.Time
+	ScoresCommittedAt time.Time
+	FieldResetAt      time.Time
 }
 
 var ElimRoundNames = map[int]string{1: "F", 2: "SF", 4: "QF", 8: "EF"}
@@ -57,6 +61,14 @@ func (database *Database) SaveMatch(match *Match) error {
 	return err
 }
 
+// Saves only the times at which the match reached each milestone, so as not to overwrite any other changes to the
+// match that are being saved at the same time.
+func (database *Database) SaveMatchMilestones(match *Match) error {
+	_, err := database.matchMap.Exec("UPDATE matches SET endedat = ?, scorescommittedat = ?, fieldresetat = ? "+
+		"WHERE id = ?", match.EndedAt, match.ScoresCommittedAt, match.FieldResetAt, match.Id)
+	return err
+}
+
 func (database *Database) DeleteMatch(match *Match) error {
 	_, err := database.matchMap.Delete(match)
 	return err
@@ -86,10 +98,23 @@ func (database *Database) GetMatchesByElimRoundGroup(round int, group int) ([]Ma
 	return matches, err
 }
 
+// Returns the matches of the given type in the order they are to be played. Elimination matches are played round by
+// round, with each instance played across all of the round's groups before the next. The bracket matches of a
+// double-elimination playoff all share a round and are numbered by group instead, so they are played group by group,
+// with the replays of a tied match coming before any match that depends on its result.
 func (database *Database) GetMatchesByType(matchType string) ([]Match, error) {
+	orderBy := "elimround desc, eliminstance, elimgroup, id"
+	if matchType == "elimination" {
+		eventSettings, err := database.GetEventSettings()
+		if err != nil {
+			return nil, err
+		}
+		if eventSettings.ElimType == "double" {
+			orderBy = "elimround desc, elimgroup, eliminstance, id"
+		}
+	}
 	var matches []Match
-	err := database.teamMap.Select(&matches,
-		"SELECT * FROM matches WHERE type = ? ORDER BY elimround desc, eliminstance, elimgroup, id", matchType)
+	err := database.teamMap.Select(&matches, "SELECT * FROM matches WHERE type = ? ORDER BY "+orderBy, matchType)
 	return matches, err
 }
 
@@ -8,28 +8,51 @@ package model
 import (
 	"encoding/json"
 	"github.com/Team254/cheesy-arena/game"
+	"sort"
 )
 
 type MatchResult struct {
-	Id         int
-	MatchId    int
-	PlayNumber int
-	MatchType  string
-	RedScore   *game.Score
-	BlueScore  *game.Score
-	RedCards   map[string]string
-	BlueCards  map[string]string
+	Id               int
+	MatchId          int
+	PlayNumber       int
+	MatchType        string
+	RedScore         *game.Score
+	BlueScore        *game.Score
+	RedCards         map[string]string
+	BlueCards        map[string]string
+	RedTimeline      []game.ScoringEvent
+	BlueTimeline     []game.ScoringEvent
+	RedOverrides     []game.ScoreOverride
+	BlueOverrides    []game.ScoreOverride
+	RedScoreSources  map[string]string
+	BlueScoreSources map[string]string
+	RedCardReasons   map[string]string
+	BlueCardReasons  map[string]string
+}
+
+// A scoring event along with the alliance whose score it changed.
+type AllianceScoringEvent struct {
+	Alliance string
+	game.ScoringEvent
 }
 
 type MatchResultDb struct {
-	Id            int
-	MatchId       int
-	PlayNumber    int
-	MatchType     string
-	RedScoreJson  string
-	BlueScoreJson string
-	RedCardsJson  string
-	BlueCardsJson string
+	Id                   int
+	MatchId              int
+	PlayNumber           int
+	MatchType            string
+	RedScoreJson         string
+	BlueScoreJson        string
+	RedCardsJson         string
+	BlueCardsJson        string
+	RedTimelineJson      string
+	BlueTimelineJson     string
+	RedOverridesJson     string
+	BlueOverridesJson    string
+	RedScoreSourcesJson  string
+	BlueScoreSourcesJson string
+	RedCardReasonsJson   string
+	BlueCardReasonsJson  string
 }
 
 // Returns a new match result object with empty slices instead of nil.
@@ -39,6 +62,14 @@ func NewMatchResult() *MatchResult {
 	matchResult.BlueScore = new(game.Score)
 	matchResult.RedCards = make(map[string]string)
 	matchResult.BlueCards = make(map[string]string)
+	matchResult.RedTimeline = []game.ScoringEvent{}
+	matchResult.BlueTimeline = []game.ScoringEvent{}
+	matchResult.RedOverrides = []game.ScoreOverride{}
+	matchResult.BlueOverrides = []game.ScoreOverride{}
+	matchResult.RedScoreSources = make(map[string]string)
+	matchResult.BlueScoreSources = make(map[string]string)
+	matchResult.RedCardReasons = make(map[string]string)
+	matchResult.BlueCardReasons = make(map[string]string)
 	return matchResult
 }
 
@@ -104,6 +135,21 @@ func (matchResult *MatchResult) BlueScoreSummary() *game.ScoreSummary {
 	return matchResult.BlueScore.Summarize(matchResult.RedScore.Fouls, matchResult.MatchType)
 }
 
+// Returns the scoring events of both alliances merged into a single list in the order in which they happened.
+func (matchResult *MatchResult) Timeline() []AllianceScoringEvent {
+	var events []AllianceScoringEvent
+	for _, event := range matchResult.RedTimeline {
+		events = append(events, AllianceScoringEvent{"red", event})
+	}
+	for _, event := range matchResult.BlueTimeline {
+		events = append(events, AllianceScoringEvent{"blue", event})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].TimeInMatchSec < events[j].TimeInMatchSec
+	})
+	return events
+}
+
 // Checks the score for disqualifications or a tie and adjusts it appropriately.
 func (matchResult *MatchResult) CorrectEliminationScore() {
 	matchResult.RedScore.ElimDq = false
@@ -117,8 +163,6 @@ func (matchResult *MatchResult) CorrectEliminationScore() {
 			matchResult.BlueScore.ElimDq = true
 		}
 	}
-
-	// No elimination tiebreakers.
 }
 
 // Converts the nested struct MatchResult to the DB version that has JSON fields.
@@ -137,6 +181,30 @@ func (matchResult *MatchResult) Serialize() (*MatchResultDb, error) {
 	if err := serializeHelper(&matchResultDb.BlueCardsJson, matchResult.BlueCards); err != nil {
 		return nil, err
 	}
+	if err := serializeHelper(&matchResultDb.RedTimelineJson, matchResult.RedTimeline); err != nil {
+		return nil, err
+	}
+	if err := serializeHelper(&matchResultDb.BlueTimelineJson, matchResult.BlueTimeline); err != nil {
+		return nil, err
+	}
+	if err := serializeHelper(&matchResultDb.RedOverridesJson, matchResult.RedOverrides); err != nil {
+		return nil, err
+	}
+	if err := serializeHelper(&matchResultDb.BlueOverridesJson, matchResult.BlueOverrides); err != nil {
+		return nil, err
+	}
+	if err := serializeHelper(&matchResultDb.RedScoreSourcesJson, matchResult.RedScoreSources); err != nil {
+		return nil, err
+	}
+	if err := serializeHelper(&matchResultDb.BlueScoreSourcesJson, matchResult.BlueScoreSources); err != nil {
+		return nil, err
+	}
+	if err := serializeHelper(&matchResultDb.RedCardReasonsJson, matchResult.RedCardReasons); err != nil {
+		return nil, err
+	}
+	if err := serializeHelper(&matchResultDb.BlueCardReasonsJson, matchResult.BlueCardReasons); err != nil {
+		return nil, err
+	}
 	return &matchResultDb, nil
 }
 
@@ -156,5 +224,29 @@ func (matchResultDb *MatchResultDb) Deserialize() (*MatchResult, error) {
 	if err := json.Unmarshal([]byte(matchResultDb.BlueCardsJson), &matchResult.BlueCards); err != nil {
 		return nil, err
 	}
+	if err := json.Unmarshal([]byte(matchResultDb.RedTimelineJson), &matchResult.RedTimeline); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(matchResultDb.BlueTimelineJson), &matchResult.BlueTimeline); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(matchResultDb.RedOverridesJson), &matchResult.RedOverrides); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(matchResultDb.BlueOverridesJson), &matchResult.BlueOverrides); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(matchResultDb.RedScoreSourcesJson), &matchResult.RedScoreSources); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(matchResultDb.BlueScoreSourcesJson), &matchResult.BlueScoreSources); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(matchResultDb.RedCardReasonsJson), &matchResult.RedCardReasons); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(matchResultDb.BlueCardReasonsJson), &matchResult.BlueCardReasons); err != nil {
+		return nil, err
+	}
 	return &matchResult, nil
 }
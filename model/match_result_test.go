@@ -63,3 +63,18 @@ func TestGetMatchResultForMatch(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, matchResult2, matchResult4)
 }
+
+func TestMatchResultTimeline(t *testing.T) {
+	matchResult := BuildTestMatchResult(254, 1)
+	timeline := matchResult.Timeline()
+	if assert.Equal(t, 3, len(timeline)) {
+		assert.Equal(t, "red", timeline[0].Alliance)
+		assert.Equal(t, "Auto mobility: 1 robots", timeline[0].Description)
+		assert.Equal(t, "blue", timeline[1].Alliance)
+		assert.Equal(t, "Foul G22 on 973", timeline[1].Description)
+		assert.Equal(t, "red", timeline[2].Alliance)
+		assert.Equal(t, 48.1, timeline[2].TimeInMatchSec)
+	}
+
+	assert.Empty(t, NewMatchResult().Timeline())
+}
@@ -0,0 +1,146 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Sanity checks run over a match result before it is committed, to catch scores that can't have happened.
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Problems found with a match result. Errors make the result impossible and block it from being committed, while
+// warnings are merely unlikely and can be committed once confirmed.
+type MatchResultValidation struct {
+	Errors   []string
+	Warnings []string
+}
+
+// Checks the match result against the teams in the match. The given set contains the teams that were bypassed during
+// the match, or is nil if that isn't known, in which case the checks that depend on it are skipped.
+func ValidateMatchResult(match *Match, matchResult *MatchResult, bypassedTeams map[int]bool) *MatchResultValidation {
+	validation := &MatchResultValidation{Errors: []string{}, Warnings: []string{}}
+	if match.Type == "test" {
+		// Test matches have no teams and their results aren't saved, so there is nothing to check.
+		return validation
+	}
+	redTeams := allianceTeams(match.Red1, match.Red2, match.Red3)
+	blueTeams := allianceTeams(match.Blue1, match.Blue2, match.Blue3)
+	if matchResult.RedScore != nil {
+		validation.validateAlliance("Red", matchResult, redTeams, blueTeams, bypassedTeams)
+	}
+	if matchResult.BlueScore != nil {
+		validation.validateAlliance("Blue", matchResult, blueTeams, redTeams, bypassedTeams)
+	}
+	return validation
+}
+
+// Returns true if there are no errors or warnings.
+func (validation *MatchResultValidation) IsValid() bool {
+	return len(validation.Errors) == 0 && len(validation.Warnings) == 0
+}
+
+func (validation *MatchResultValidation) validateAlliance(alliance string, matchResult *MatchResult, teams,
+	opposingTeams []int, bypassedTeams map[int]bool) {
+	score := matchResult.RedScore
+	cards := matchResult.RedCards
+	if alliance == "Blue" {
+		score = matchResult.BlueScore
+		cards = matchResult.BlueCards
+	}
+
+	elements := []struct {
+		name  string
+		value int
+	}{{"auto mobility", score.AutoMobility}, {"auto rotors", score.AutoRotors},
+		{"auto low fuel", score.AutoFuelLow}, {"auto high fuel", score.AutoFuelHigh}, {"teleop rotors", score.Rotors},
+		{"teleop low fuel", score.FuelLow}, {"teleop high fuel", score.FuelHigh}, {"takeoffs", score.Takeoffs}}
+	for _, element := range elements {
+		if element.value < 0 {
+			validation.addError("%s %s can't be negative.", alliance, element.name)
+		}
+	}
+	if score.AutoRotors+score.Rotors > 4 {
+		validation.addError("%s has %d rotors turning but there are only 4 on the airship.", alliance,
+			score.AutoRotors+score.Rotors)
+	}
+
+	activeTeams := 0
+	for _, team := range teams {
+		if !bypassedTeams[team] {
+			activeTeams++
+		}
+	}
+	for _, element := range []struct {
+		name  string
+		value int
+	}{{"auto mobility", score.AutoMobility}, {"takeoffs", score.Takeoffs}} {
+		if element.value > len(teams) {
+			validation.addError("%s %s of %d is more than the %d teams on the alliance.", alliance, element.name,
+				element.value, len(teams))
+		} else if bypassedTeams != nil && element.value > activeTeams {
+			validation.addWarning("%s %s of %d is more than the %d robots that weren't bypassed.", alliance,
+				element.name, element.value, activeTeams)
+		}
+	}
+
+	for _, foul := range score.Fouls {
+		if !containsTeam(teams, foul.TeamId) {
+			validation.addError("%s foul %s at %.1f seconds is against team %d, %s.", alliance, foul.RuleNumber,
+				foul.TimeInMatchSec, foul.TeamId, describeOtherTeam(foul.TeamId, opposingTeams))
+		}
+	}
+
+	var cardTeams []string
+	for teamString := range cards {
+		cardTeams = append(cardTeams, teamString)
+	}
+	sort.Strings(cardTeams)
+	for _, teamString := range cardTeams {
+		card := cards[teamString]
+		if card == "" {
+			continue
+		}
+		team, _ := strconv.Atoi(teamString)
+		if !containsTeam(teams, team) {
+			validation.addError("%s %s card is for team %s, %s.", alliance, card, teamString,
+				describeOtherTeam(team, opposingTeams))
+		}
+	}
+}
+
+func (validation *MatchResultValidation) addError(format string, args ...interface{}) {
+	validation.Errors = append(validation.Errors, fmt.Sprintf(format, args...))
+}
+
+func (validation *MatchResultValidation) addWarning(format string, args ...interface{}) {
+	validation.Warnings = append(validation.Warnings, fmt.Sprintf(format, args...))
+}
+
+func allianceTeams(teams ...int) []int {
+	var presentTeams []int
+	for _, team := range teams {
+		if team != 0 {
+			presentTeams = append(presentTeams, team)
+		}
+	}
+	return presentTeams
+}
+
+func containsTeam(teams []int, team int) bool {
+	for _, allianceTeam := range teams {
+		if allianceTeam == team {
+			return true
+		}
+	}
+	return false
+}
+
+func describeOtherTeam(team int, opposingTeams []int) string {
+	if containsTeam(opposingTeams, team) {
+		return "who is on the other alliance"
+	}
+	return "who isn't in the match"
+}
@@ -0,0 +1,52 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidateMatchResult(t *testing.T) {
+	match := &Match{Red1: 254, Red2: 1114, Red3: 0, Blue1: 1868, Blue2: 2056, Blue3: 973}
+	matchResult := NewMatchResult()
+	matchResult.RedScore = &game.Score{AutoMobility: 2, Takeoffs: 2,
+		Fouls: []game.Foul{{game.Rule{"G22", false}, 254, 12, 0, false, false}}}
+	matchResult.BlueScore = &game.Score{AutoMobility: 3, AutoRotors: 1, Rotors: 3}
+	matchResult.RedCards = map[string]string{"254": "yellow", "1114": ""}
+	matchResult.BlueCards = map[string]string{"1868": "red"}
+	validation := ValidateMatchResult(match, matchResult, nil)
+	assert.True(t, validation.IsValid())
+	assert.Empty(t, validation.Errors)
+	assert.Empty(t, validation.Warnings)
+
+	// Bypassed robots shouldn't be able to score, but the scorers might know better.
+	validation = ValidateMatchResult(match, matchResult, map[int]bool{1114: true, 973: true})
+	assert.Empty(t, validation.Errors)
+	assert.Equal(t, []string{"Red auto mobility of 2 is more than the 1 robots that weren't bypassed.",
+		"Red takeoffs of 2 is more than the 1 robots that weren't bypassed.",
+		"Blue auto mobility of 3 is more than the 2 robots that weren't bypassed."}, validation.Warnings)
+
+	// Check the impossible results.
+	matchResult.RedScore.Takeoffs = 3
+	matchResult.RedScore.FuelLow = -1
+	matchResult.RedScore.Fouls = append(matchResult.RedScore.Fouls,
+		game.Foul{game.Rule{"G18", true}, 1868, 30, 0, false, false},
+		game.Foul{game.Rule{"G20", false}, 9999, 45, 0, false, false})
+	matchResult.BlueScore.Rotors = 4
+	matchResult.BlueCards["1114"] = "yellow"
+	validation = ValidateMatchResult(match, matchResult, nil)
+	assert.False(t, validation.IsValid())
+	assert.Equal(t, []string{"Red teleop low fuel can't be negative.",
+		"Red takeoffs of 3 is more than the 2 teams on the alliance.",
+		"Red foul G18 at 30.0 seconds is against team 1868, who is on the other alliance.",
+		"Red foul G20 at 45.0 seconds is against team 9999, who isn't in the match.",
+		"Blue has 5 rotors turning but there are only 4 on the airship.",
+		"Blue yellow card is for team 1114, who is on the other alliance."}, validation.Errors)
+	assert.Empty(t, validation.Warnings)
+
+	match.Type = "test"
+	assert.True(t, ValidateMatchResult(match, matchResult, nil).IsValid())
+}
@@ -21,7 +21,8 @@ func TestMatchCrud(t *testing.T) {
 	db := setupTestDb(t)
 
 	match := Match{0, "qualification", "254", time.Now().UTC(), 0, 0, 0, 1, false, 2, false, 3, false, 4, false,
-		5, false, 6, false, "", time.Now().UTC(), ""}
+		5, false, 6, false, "", time.Now().UTC(), "", "",
+		time.Now().UTC(), time.Now().UTC(), time.Now().UTC()}
 	db.CreateMatch(&match)
 	match2, err := db.GetMatchById(1)
 	assert.Nil(t, err)
@@ -36,6 +37,17 @@ func TestMatchCrud(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, match.Status, match2.Status)
 
+	// Saving the milestones shouldn't touch any of the other fields.
+	milestoneMatch := *match2
+	milestoneMatch.Status = "complete"
+	milestoneMatch.FieldResetAt = time.Unix(1500000000, 0).UTC()
+	assert.Nil(t, db.SaveMatchMilestones(&milestoneMatch))
+	match2, err = db.GetMatchById(1)
+	assert.Nil(t, err)
+	assert.Equal(t, "started", match2.Status)
+	assert.Equal(t, milestoneMatch.FieldResetAt, match2.FieldResetAt)
+	assert.Equal(t, match.EndedAt, match2.EndedAt)
+
 	db.DeleteMatch(&match)
 	match2, err = db.GetMatchById(1)
 	assert.Nil(t, err)
@@ -46,7 +58,8 @@ func TestTruncateMatches(t *testing.T) {
 	db := setupTestDb(t)
 
 	match := Match{0, "qualification", "254", time.Now().UTC(), 0, 0, 0, 1, false, 2, false, 3, false, 4, false,
-		5, false, 6, false, "", time.Now().UTC(), ""}
+		5, false, 6, false, "", time.Now().UTC(), "", "",
+		time.Now().UTC(), time.Now().UTC(), time.Now().UTC()}
 	db.CreateMatch(&match)
 	db.TruncateMatches()
 	match2, err := db.GetMatchById(1)
@@ -83,13 +96,16 @@ func TestGetMatchesByType(t *testing.T) {
 	db := setupTestDb(t)
 
 	match := Match{0, "qualification", "1", time.Now().UTC(), 0, 0, 0, 1, false, 2, false, 3, false, 4, false,
-		5, false, 6, false, "", time.Now().UTC(), ""}
+		5, false, 6, false, "", time.Now().UTC(), "", "",
+		time.Now().UTC(), time.Now().UTC(), time.Now().UTC()}
 	db.CreateMatch(&match)
 	match2 := Match{0, "practice", "1", time.Now().UTC(), 0, 0, 0, 1, false, 2, false, 3, false, 4, false, 5,
-		false, 6, false, "", time.Now().UTC(), ""}
+		false, 6, false, "", time.Now().UTC(), "", "",
+		time.Now().UTC(), time.Now().UTC(), time.Now().UTC()}
 	db.CreateMatch(&match2)
 	match3 := Match{0, "practice", "2", time.Now().UTC(), 0, 0, 0, 1, false, 2, false, 3, false, 4, false, 5,
-		false, 6, false, "", time.Now().UTC(), ""}
+		false, 6, false, "", time.Now().UTC(), "", "",
+		time.Now().UTC(), time.Now().UTC(), time.Now().UTC()}
 	db.CreateMatch(&match3)
 
 	matches, err := db.GetMatchesByType("test")
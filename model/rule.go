@@ -0,0 +1,67 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for the event's catalogue of penalized game rules.
+
+package model
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+)
+
+type Rule struct {
+	Id                 int
+	RuleNumber         string
+	IsTechnical        bool
+	Description        string
+	TriggersCard       bool
+	AwardsRankingPoint bool
+}
+
+func (database *Database) CreateRule(rule *Rule) error {
+	return database.ruleMap.Insert(rule)
+}
+
+func (database *Database) GetRuleById(id int) (*Rule, error) {
+	rule := new(Rule)
+	err := database.ruleMap.Get(rule, id)
+	if err != nil && err.Error() == "sql: no rows in result set" {
+		rule = nil
+		err = nil
+	}
+	return rule, err
+}
+
+func (database *Database) SaveRule(rule *Rule) error {
+	_, err := database.ruleMap.Update(rule)
+	return err
+}
+
+func (database *Database) DeleteRule(rule *Rule) error {
+	_, err := database.ruleMap.Delete(rule)
+	return err
+}
+
+func (database *Database) TruncateRules() error {
+	return database.ruleMap.TruncateTables()
+}
+
+func (database *Database) GetAllRules() ([]Rule, error) {
+	var rules []Rule
+	err := database.ruleMap.Select(&rules, "SELECT * FROM rules ORDER BY rulenumber, istechnical")
+	return rules, err
+}
+
+// Returns an error if the rule can't be saved to the catalogue as-is.
+func ValidateRule(rule *Rule) error {
+	if rule.RuleNumber == "" {
+		return fmt.Errorf("Rule number must not be blank.")
+	}
+	return nil
+}
+
+// Returns the rule key under which fouls for this rule are recorded.
+func (rule *Rule) GameRule() game.Rule {
+	return game.Rule{RuleNumber: rule.RuleNumber, IsTechnical: rule.IsTechnical}
+}
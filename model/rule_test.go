@@ -0,0 +1,76 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetNonexistentRule(t *testing.T) {
+	db := setupTestDb(t)
+
+	rule, err := db.GetRuleById(1114)
+	assert.Nil(t, err)
+	assert.Nil(t, rule)
+}
+
+func TestDefaultRules(t *testing.T) {
+	db := setupTestDb(t)
+
+	rules, err := db.GetAllRules()
+	assert.Nil(t, err)
+	if assert.Equal(t, 31, len(rules)) {
+		assert.Equal(t, "A01", rules[0].RuleNumber)
+		assert.Equal(t, game.Rule{"G11", false}, rules[11].GameRule())
+		assert.Equal(t, game.Rule{"G11", true}, rules[12].GameRule())
+	}
+}
+
+func TestRuleCrud(t *testing.T) {
+	db := setupTestDb(t)
+	db.TruncateRules()
+
+	rule := Rule{0, "G22", false, "Fuel control limits", false, false}
+	db.CreateRule(&rule)
+	rule2, err := db.GetRuleById(rule.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, rule, *rule2)
+
+	rule.Description = "Fuel control limits exceeded"
+	rule.TriggersCard = true
+	db.SaveRule(&rule)
+	rule2, err = db.GetRuleById(rule.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, rule, *rule2)
+
+	db.CreateRule(&Rule{0, "G22", true, "", false, true})
+	db.CreateRule(&Rule{0, "C08", false, "", false, false})
+	rules, err := db.GetAllRules()
+	assert.Nil(t, err)
+	if assert.Equal(t, 3, len(rules)) {
+		assert.Equal(t, "C08", rules[0].RuleNumber)
+		assert.Equal(t, rule, rules[1])
+		assert.True(t, rules[2].AwardsRankingPoint)
+	}
+
+	// The same rule number and severity can't be entered twice.
+	assert.NotNil(t, db.CreateRule(&Rule{0, "C08", false, "", false, false}))
+
+	db.DeleteRule(&rule)
+	rule2, err = db.GetRuleById(rule.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, rule2)
+
+	db.TruncateRules()
+	rules, err = db.GetAllRules()
+	assert.Nil(t, err)
+	assert.Empty(t, rules)
+}
+
+func TestValidateRule(t *testing.T) {
+	assert.Nil(t, ValidateRule(&Rule{RuleNumber: "G22"}))
+	assert.NotNil(t, ValidateRule(&Rule{Description: "No rule number"}))
+}
@@ -0,0 +1,53 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for the blocks of time into which practice and qualification matches are scheduled.
+
+package model
+
+import "time"
+
+type ScheduleBlock struct {
+	Id              int
+	MatchType       string
+	Name            string
+	StartTime       time.Time
+	NumMatches      int
+	MatchSpacingSec int
+}
+
+func (database *Database) CreateScheduleBlock(block *ScheduleBlock) error {
+	return database.scheduleBlockMap.Insert(block)
+}
+
+func (database *Database) GetScheduleBlocksByMatchType(matchType string) ([]ScheduleBlock, error) {
+	var blocks []ScheduleBlock
+	err := database.scheduleBlockMap.Select(&blocks,
+		"SELECT * FROM schedule_blocks WHERE matchtype = ? ORDER BY id", matchType)
+	return blocks, err
+}
+
+// Replaces the saved schedule blocks for the given match type with the given ones.
+func (database *Database) ReplaceScheduleBlocks(matchType string, blocks []ScheduleBlock) error {
+	_, err := database.scheduleBlockMap.Exec("DELETE FROM schedule_blocks WHERE matchtype = ?", matchType)
+	if err != nil {
+		return err
+	}
+	for i := range blocks {
+		blocks[i].Id = 0
+		blocks[i].MatchType = matchType
+		if err = database.CreateScheduleBlock(&blocks[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (database *Database) TruncateScheduleBlocks() error {
+	return database.scheduleBlockMap.TruncateTables()
+}
+
+// Returns the time at which the given block's last match is scheduled to end.
+func (block *ScheduleBlock) EndTime() time.Time {
+	return block.StartTime.Add(time.Duration(block.NumMatches*block.MatchSpacingSec) * time.Second)
+}
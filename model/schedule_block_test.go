@@ -0,0 +1,48 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestScheduleBlockCrud(t *testing.T) {
+	db := setupTestDb(t)
+
+	blocks, err := db.GetScheduleBlocksByMatchType("qualification")
+	assert.Nil(t, err)
+	assert.Empty(t, blocks)
+
+	startTime := time.Unix(1500000000, 0).UTC()
+	qualificationBlocks := []ScheduleBlock{{0, "", "Day 1 PM", startTime, 20, 420},
+		{0, "", "Day 2 AM", startTime.Add(24 * time.Hour), 30, 420}}
+	assert.Nil(t, db.ReplaceScheduleBlocks("qualification", qualificationBlocks))
+	assert.Nil(t, db.ReplaceScheduleBlocks("practice", []ScheduleBlock{{0, "", "", startTime, 10, 360}}))
+	blocks, err = db.GetScheduleBlocksByMatchType("qualification")
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(blocks)) {
+		assert.Equal(t, "qualification", blocks[0].MatchType)
+		assert.Equal(t, "Day 1 PM", blocks[0].Name)
+		assert.Equal(t, 20, blocks[0].NumMatches)
+		assert.Equal(t, "Day 2 AM", blocks[1].Name)
+		assert.Equal(t, 420, blocks[1].MatchSpacingSec)
+		assert.True(t, startTime.Add(24*time.Hour).Equal(blocks[1].StartTime))
+		assert.True(t, startTime.Add(24*time.Hour+210*time.Minute).Equal(blocks[1].EndTime()))
+	}
+
+	// Check that replacing the blocks for one match type leaves the others alone.
+	assert.Nil(t, db.ReplaceScheduleBlocks("qualification", qualificationBlocks[1:]))
+	blocks, _ = db.GetScheduleBlocksByMatchType("qualification")
+	if assert.Equal(t, 1, len(blocks)) {
+		assert.Equal(t, "Day 2 AM", blocks[0].Name)
+	}
+	blocks, _ = db.GetScheduleBlocksByMatchType("practice")
+	assert.Equal(t, 1, len(blocks))
+
+	db.TruncateScheduleBlocks()
+	blocks, _ = db.GetScheduleBlocksByMatchType("practice")
+	assert.Empty(t, blocks)
+}
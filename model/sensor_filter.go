@@ -0,0 +1,45 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for the debounce/filter thresholds of a field PLC sensor.
+
+package model
+
+type SensorFilter struct {
+	Id             int
+	Sensor         string
+	MinOnMs        int
+	GlitchRejectMs int
+	MaxCountRate   int
+}
+
+func (database *Database) CreateSensorFilter(sensorFilter *SensorFilter) error {
+	return database.sensorFilterMap.Insert(sensorFilter)
+}
+
+func (database *Database) GetSensorFilterBySensor(sensor string) (*SensorFilter, error) {
+	var sensorFilters []SensorFilter
+	err := database.sensorFilterMap.Select(&sensorFilters, "SELECT * FROM sensor_filters WHERE sensor = ?", sensor)
+	if err != nil {
+		return nil, err
+	}
+	if len(sensorFilters) == 0 {
+		return nil, nil
+	}
+	return &sensorFilters[0], err
+}
+
+func (database *Database) SaveSensorFilter(sensorFilter *SensorFilter) error {
+	_, err := database.sensorFilterMap.Update(sensorFilter)
+	return err
+}
+
+func (database *Database) TruncateSensorFilters() error {
+	return database.sensorFilterMap.TruncateTables()
+}
+
+func (database *Database) GetAllSensorFilters() ([]SensorFilter, error) {
+	var sensorFilters []SensorFilter
+	err := database.sensorFilterMap.Select(&sensorFilters, "SELECT * FROM sensor_filters ORDER BY id")
+	return sensorFilters, err
+}
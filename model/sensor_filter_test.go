@@ -0,0 +1,46 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetNonexistentSensorFilter(t *testing.T) {
+	db := setupTestDb(t)
+
+	sensorFilter, err := db.GetSensorFilterBySensor("redRotor1")
+	assert.Nil(t, err)
+	assert.Nil(t, sensorFilter)
+}
+
+func TestSensorFilterCrud(t *testing.T) {
+	db := setupTestDb(t)
+
+	sensorFilter := SensorFilter{0, "redRotor1", 200, 0, 0}
+	db.CreateSensorFilter(&sensorFilter)
+	sensorFilter2, err := db.GetSensorFilterBySensor("redRotor1")
+	assert.Nil(t, err)
+	assert.Equal(t, sensorFilter, *sensorFilter2)
+
+	sensorFilter.GlitchRejectMs = 50
+	db.SaveSensorFilter(&sensorFilter)
+	sensorFilter2, err = db.GetSensorFilterBySensor("redRotor1")
+	assert.Nil(t, err)
+	assert.Equal(t, 50, sensorFilter2.GlitchRejectMs)
+
+	db.CreateSensorFilter(&SensorFilter{0, "blueLowBoilerCount", 0, 0, 30})
+	sensorFilters, err := db.GetAllSensorFilters()
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(sensorFilters)) {
+		assert.Equal(t, "redRotor1", sensorFilters[0].Sensor)
+		assert.Equal(t, 30, sensorFilters[1].MaxCountRate)
+	}
+
+	db.TruncateSensorFilters()
+	sensorFilters, err = db.GetAllSensorFilters()
+	assert.Nil(t, err)
+	assert.Empty(t, sensorFilters)
+}
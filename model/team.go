@@ -5,18 +5,21 @@
 
 package model
 
+import "fmt"
+
 type Team struct {
-	Id              int
-	Name            string
-	Nickname        string
-	City            string
-	StateProv       string
-	Country         string
-	RookieYear      int
-	RobotName       string
-	Accomplishments string
-	WpaKey          string
-	YellowCard      bool
+	Id                 int
+	Name               string
+	Nickname           string
+	City               string
+	StateProv          string
+	Country            string
+	RookieYear         int
+	RobotName          string
+	Accomplishments    string
+	WpaKey             string
+	YellowCard         bool
+	BandwidthLimitMbps float64
 }
 
 func (database *Database) CreateTeam(team *Team) error {
@@ -52,3 +55,17 @@ func (database *Database) GetAllTeams() ([]Team, error) {
 	err := database.teamMap.Select(&teams, "SELECT * FROM teams ORDER BY id")
 	return teams, err
 }
+
+// Returns an error describing why the given key can't be used as the WPA passphrase of a team network, or nil if it
+// is valid. Single quotes are disallowed since the key is quoted within the access point configuration.
+func ValidateWpaKey(wpaKey string) error {
+	if len(wpaKey) < 8 || len(wpaKey) > 63 {
+		return fmt.Errorf("WPA key must be between 8 and 63 characters.")
+	}
+	for _, char := range wpaKey {
+		if char < ' ' || char > '~' || char == '\'' {
+			return fmt.Errorf("WPA key must contain only printable ASCII characters other than single quotes.")
+		}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ package model
 
 import (
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -68,3 +69,22 @@ func TestGetAllTeams(t *testing.T) {
 		assert.Equal(t, i+1, teams[i].Id)
 	}
 }
+
+func TestValidateWpaKey(t *testing.T) {
+	assert.Nil(t, ValidateWpaKey("12345678"))
+	assert.Nil(t, ValidateWpaKey("Cheesy Poofs #254 ~!@$%^&*()\"<>"))
+	assert.Nil(t, ValidateWpaKey(strings.Repeat("a", 63)))
+
+	err := ValidateWpaKey("1234567")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "WPA key must be between 8 and 63 characters.", err.Error())
+	}
+	assert.NotNil(t, ValidateWpaKey(strings.Repeat("a", 64)))
+	assert.NotNil(t, ValidateWpaKey(""))
+	err = ValidateWpaKey("team's key")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "printable ASCII characters")
+	}
+	assert.NotNil(t, ValidateWpaKey("tab\tbetween"))
+	assert.NotNil(t, ValidateWpaKey("clé secrète"))
+}
@@ -29,6 +29,15 @@ func BuildTestMatchResult(matchId int, playNumber int) *MatchResult {
 	matchResult.BlueScore = game.TestScore2()
 	matchResult.RedCards = map[string]string{"1868": "yellow"}
 	matchResult.BlueCards = map[string]string{}
+	matchResult.RedTimeline = []game.ScoringEvent{{2.5, game.ScorerSource, "AutoMobility", 1, "Auto mobility: 1 robots"},
+		{48.1, game.SensorSource, "Rotors", 2, "Rotor 2 turning"}}
+	matchResult.BlueTimeline = []game.ScoringEvent{{31.7, game.RefereeSource, "Foul", 973, "Foul G22 on 973"}}
+	matchResult.RedOverrides = []game.ScoreOverride{}
+	matchResult.BlueOverrides = []game.ScoreOverride{{"Takeoffs", 3, 2, "Touchpad 3 light out", 148.2, false}}
+	matchResult.RedScoreSources = map[string]string{"AutoFuelHigh": game.ManualSource, "Rotors": game.ManualSource}
+	matchResult.BlueScoreSources = map[string]string{"AutoFuelHigh": game.SensorSource, "Takeoffs": game.RefereeSource}
+	matchResult.RedCardReasons = map[string]string{"1868": "G20 pinning"}
+	matchResult.BlueCardReasons = map[string]string{}
 	return matchResult
 }
 
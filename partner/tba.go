@@ -267,6 +267,8 @@ func (client *TbaClient) PublishMatches(database *model.Database) error {
 			"blue": blueAlliance}, scoreBreakdown, match.Time.Local().Format("3:04 PM"),
 			match.Time.UTC().Format("2006-01-02T15:04:05")}
 		if match.Type == "elimination" {
+			// Double-elimination bracket matches are stored as semifinal sets numbered by match, as TBA expects, and
+			// round-robin matches as the matches of a single semifinal set.
 			tbaMatches[i].CompLevel = map[int]string{1: "f", 2: "sf", 4: "qf", 8: "ef"}[match.ElimRound]
 			tbaMatches[i].SetNumber = match.ElimGroup
 			tbaMatches[i].MatchNumber = match.ElimInstance
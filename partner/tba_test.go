@@ -128,3 +128,22 @@ func TestPublishingErrors(t *testing.T) {
 func setupTestDb(t *testing.T) *model.Database {
 	return model.SetupTestDb(t, "partner")
 }
+
+func TestTbaScoringBreakdownFoulPoints(t *testing.T) {
+	defer func() {
+		game.FoulPenalties.FoulPointValue = 5
+		game.FoulPenalties.TechFoulPointValue = 25
+	}()
+	match := model.Match{Type: "qualification", DisplayName: "1"}
+	matchResult := model.BuildTestMatchResult(1, 1)
+
+	breakdown := createTbaScoringBreakdown(&match, matchResult, "blue")
+	assert.Equal(t, 55, breakdown.FoulPoints)
+
+	// Check that the foul point values configured for the event are used.
+	game.FoulPenalties.FoulPointValue = 3
+	game.FoulPenalties.TechFoulPointValue = 15
+	breakdown = createTbaScoringBreakdown(&match, matchResult, "blue")
+	assert.Equal(t, 33, breakdown.FoulPoints)
+	assert.Equal(t, matchResult.BlueScoreSummary().Score, breakdown.TotalPoints)
+}
@@ -14,6 +14,9 @@ Handlebars.registerHelper("eachMapEntry", function(context, options) {
   });
   return ret;
 });
+Handlebars.registerHelper("elementName", function(element) {
+  return scoreElements[element];
+});
 
 // Handles a websocket message to hide the score dialog once the next match is being introduced.
 var handleSetAudienceDisplay = function(targetScreen) {
@@ -46,15 +49,28 @@ var handleMatchTime = function(data) {
 var handleRealtimeScore = function(data) {
   $("#redScore").text(data.RedScore);
   $("#blueScore").text(data.BlueScore);
+  $("#redOverrides").text(describeOverrides(data.RedOverrides));
+  $("#blueOverrides").text(describeOverrides(data.BlueOverrides));
+};
+
+// Returns a summary of the head referee's corrections to an alliance's sensor-derived score.
+var describeOverrides = function(overrides) {
+  if (!overrides || overrides.length == 0) {
+    return "";
+  }
+  var descriptions = $.map(overrides, function(override) {
+    return scoreElements[override.Element] + " overridden to " + override.Value + " (" + override.Reason + ")";
+  });
+  return descriptions.join(", ");
 };
 
 // Handles a websocket message to populate the final score data.
 var handleSetFinalScore = function(data) {
   $("#scoreMatchName").text(data.MatchType + " Match " + data.MatchDisplayName);
   $("#redScoreDetails").html(matchResultTemplate({score: data.RedScoreSummary, fouls: data.RedFouls,
-      cards: data.RedCards}));
+      cards: data.RedCards, overrides: data.RedOverrides}));
   $("#blueScoreDetails").html(matchResultTemplate({score: data.BlueScoreSummary, fouls: data.BlueFouls,
-      cards: data.BlueCards}));
+      cards: data.BlueCards, overrides: data.BlueOverrides}));
   $("#matchResult").modal("show");
 };
 
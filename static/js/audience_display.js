@@ -8,6 +8,7 @@ var websocket;
 var transitionMap;
 var currentScreen = "blank";
 var allianceSelectionTemplate = Handlebars.compile($("#allianceSelectionTemplate").html());
+var roundRobinStandingsTemplate = Handlebars.compile($("#roundRobinStandingsTemplate").html());
 
 // Handles a websocket message to change which screen is displayed.
 var handleSetAudienceDisplay = function(targetScreen) {
@@ -91,6 +92,13 @@ var handleSetFinalScore = function(data) {
   $("#blueFinalRotorGoalReached").html(data.BlueScore.RotorGoalReached ? "&#x2714;" : "&#x2718;");
   $("#blueFinalRotorGoalReached").attr("data-checked", data.BlueScore.RotorGoalReached);
   $("#finalMatchName").text(data.MatchName + " " + data.Match.DisplayName);
+  if (data.Match.Tiebreaker == "replay") {
+    $("#finalTiebreaker").text("Tie - Replay");
+  } else if (data.Match.Tiebreaker) {
+    $("#finalTiebreaker").text("Tiebreaker: " + data.Tiebreaker);
+  } else {
+    $("#finalTiebreaker").text("");
+  }
 };
 
 // Handles a websocket message to play a sound to signal match start/stop/etc.
@@ -261,6 +269,35 @@ var transitionAllianceSelectionToBlank = function(callback) {
   $('#allianceSelectionCentering').transition({queue: false, right: "-60em"}, 500, "ease", callback);
 };
 
+var transitionBlankToRoundRobinStandings = function(callback) {
+  // Fetch the standings each time they are shown, so that they reflect the latest committed results.
+  $.getJSON("/api/round_robin_standings", function(standings) {
+    if (currentScreen != "roundRobinStandings") {
+      // The display has already been switched to another screen while the standings were loading.
+      if (callback) {
+        callback();
+      }
+      return;
+    }
+    $.each(standings, function(k, v) {
+      v.Rank = k + 1;
+      v.Teams = v.TeamIds.join(" ");
+      v.Average = v.AverageScore.toFixed(1);
+    });
+    $("#roundRobinStandings").html(roundRobinStandingsTemplate({standings: standings}));
+    $('#roundRobinStandingsCentering').css("right","-60em").show();
+    $('#roundRobinStandingsCentering').transition({queue: false, right: "3em"}, 500, "ease", callback);
+  }).fail(function() {
+    if (callback) {
+      callback();
+    }
+  });
+};
+
+var transitionRoundRobinStandingsToBlank = function(callback) {
+  $('#roundRobinStandingsCentering').transition({queue: false, right: "-60em"}, 500, "ease", callback);
+};
+
 var transitionBlankToLowerThird = function(callback) {
   $("#lowerThird").show();
   $("#lowerThird").transition({queue: false, left: "150px"}, 750, "ease", callback);
@@ -396,6 +433,7 @@ $(function() {
       logo: transitionBlankToLogo,
       sponsor: transitionBlankToSponsor,
       allianceSelection: transitionBlankToAllianceSelection,
+      roundRobinStandings: transitionBlankToRoundRobinStandings,
       lowerThird: transitionBlankToLowerThird
     },
     intro: {
@@ -424,6 +462,9 @@ $(function() {
     allianceSelection: {
       blank: transitionAllianceSelectionToBlank
     },
+    roundRobinStandings: {
+      blank: transitionRoundRobinStandingsToBlank
+    },
     lowerThird: {
       blank: transitionLowerThirdToBlank
     }
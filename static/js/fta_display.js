@@ -20,6 +20,7 @@ var handleStatus = function(data) {
       var dsConn = stationStatus.DsConn;
       $("#status" + station + " .ds-status").attr("data-status-ok", dsConn.DsLinked);
       $("#status" + station + " .ds-status").text(dsConn.MBpsToRobot.toFixed(1) + "/" + dsConn.MBpsFromRobot.toFixed(1));
+      $("#status" + station + " .ds-status").toggleClass("bandwidth-alert", dsConn.BandwidthAlert);
       $("#status" + station + " .radio-status").attr("data-status-ok", dsConn.RadioLinked);
       $("#status" + station + " .robot-status").attr("data-status-ok", dsConn.RobotLinked);
       if (stationStatus.DsConn.SecondsSinceLastRobotLink > 1 && stationStatus.DsConn.SecondsSinceLastRobotLink < 1000) {
@@ -41,6 +42,7 @@ var handleStatus = function(data) {
     } else {
       $("#status" + station + " .ds-status").attr("data-status-ok", "");
       $("#status" + station + " .ds-status").text("");
+      $("#status" + station + " .ds-status").removeClass("bandwidth-alert");
       $("#status" + station + " .radio-status").attr("data-status-ok", "");
       $("#status" + station + " .radio-status").text("");
       $("#status" + station + " .robot-status").attr("data-status-ok", "");
@@ -53,6 +55,9 @@ var handleStatus = function(data) {
       $("#status" + station + " .packet-loss").text("");
     }
 
+    updateNetworkStatus($("#status" + station + " .network-status"), stationStatus);
+    updateWirelessStatus($("#status" + station + " .wifi-status"), stationStatus);
+
     if (stationStatus.Estop) {
       $("#status" + station + " .bypass-status-fta").attr("data-status-ok", false);
       $("#status" + station + " .bypass-status-fta").text("ES");
@@ -66,6 +71,25 @@ var handleStatus = function(data) {
   });
 };
 
+// Shows whether the team's robot radio has associated with the access point, along with its signal strength and rates.
+var updateWirelessStatus = function(element, stationStatus) {
+  var wireless = stationStatus.Wireless;
+  if (!stationStatus.Team || !wireless.SsidActive) {
+    element.attr("data-status-ok", "");
+    element.text("");
+    element.attr("title", "");
+  } else if (!wireless.Associated) {
+    element.attr("data-status-ok", false);
+    element.text("");
+    element.attr("title", "SSID is active but no radio has joined it");
+  } else {
+    element.attr("data-status-ok", true);
+    element.text(wireless.SignalDbm);
+    element.attr("title", "Tx " + wireless.TxRateMbps.toFixed(1) + " / Rx " + wireless.RxRateMbps.toFixed(1) +
+        " Mbit/s, channel " + wireless.ChannelUtilization.toFixed() + "% busy");
+  }
+};
+
 $(function() {
   // Activate tooltips above the status headers.
   $("[data-toggle=tooltip]").tooltip({"placement": "top"});
@@ -21,14 +21,19 @@ var startMatch = function() {
   websocket.send("startMatch", { muteMatchSounds: $("#muteMatchSounds").prop("checked") });
 };
 
+// Sends a websocket message to reconfigure the team networks for the current match.
+var retryNetworkSetup = function() {
+  websocket.send("retryNetworkSetup");
+};
+
 // Sends a websocket message to abort the match.
 var abortMatch = function() {
   websocket.send("abortMatch");
 };
 
 // Sends a websocket message to commit the match score and load the next match.
-var commitResults = function() {
-  websocket.send("commitResults");
+var commitResults = function(confirmWarnings) {
+  websocket.send("commitResults", { confirmWarnings: confirmWarnings === true });
 };
 
 // Sends a websocket message to discard the match score and load the next match.
@@ -57,6 +62,21 @@ var confirmCommit = function(isReplay) {
   }
 };
 
+// Handles a websocket message listing the problems that prevented the score from being committed.
+var handleScoreValidation = function(data) {
+  $("#confirmCommitResults").modal("hide");
+  $("#scoreValidationErrors").html(data.Errors.map(function(error) {
+    return $("<li>").text(error);
+  }));
+  $("#scoreValidationWarnings").html(data.Warnings.map(function(warning) {
+    return $("<li>").text(warning);
+  }));
+  $("#scoreValidationErrorsPanel").toggle(data.Errors.length > 0);
+  $("#scoreValidationWarningsPanel").toggle(data.Warnings.length > 0);
+  $("#commitWithWarnings").toggle(data.Errors.length == 0);
+  $("#scoreValidation").modal("show");
+};
+
 // Handles a websocket message to update the team connection status.
 var handleStatus = function(data) {
   // Update the team status view.
@@ -87,6 +107,8 @@ var handleStatus = function(data) {
       $("#status" + station + " .battery-status").text("");
     }
 
+    updateNetworkStatus($("#status" + station + " .network-status"), stationStatus);
+
     if (stationStatus.Estop) {
       $("#status" + station + " .bypass-status").attr("data-status-ok", false);
       $("#status" + station + " .bypass-status").text("ES");
@@ -107,6 +129,7 @@ var handleStatus = function(data) {
       $("#commitResults").prop("disabled", true);
       $("#discardResults").prop("disabled", true);
       $("#editResults").prop("disabled", true);
+      $("#retryNetworkSetup").prop("disabled", false);
       break;
     case "START_MATCH":
     case "AUTO_PERIOD":
@@ -118,6 +141,7 @@ var handleStatus = function(data) {
       $("#commitResults").prop("disabled", true);
       $("#discardResults").prop("disabled", true);
       $("#editResults").prop("disabled", true);
+      $("#retryNetworkSetup").prop("disabled", true);
       break;
     case "POST_MATCH":
       $("#startMatch").prop("disabled", true);
@@ -125,6 +149,7 @@ var handleStatus = function(data) {
       $("#commitResults").prop("disabled", false);
       $("#discardResults").prop("disabled", false);
       $("#editResults").prop("disabled", false);
+      $("#retryNetworkSetup").prop("disabled", true);
       break;
   }
 
@@ -184,6 +209,7 @@ $(function() {
     realtimeScore: function(event) { handleRealtimeScore(event.data); },
     setAudienceDisplay: function(event) { handleSetAudienceDisplay(event.data); },
     scoringStatus: function(event) { handleScoringStatus(event.data); },
-    setAllianceStationDisplay: function(event) { handleSetAllianceStationDisplay(event.data); }
+    setAllianceStationDisplay: function(event) { handleSetAllianceStationDisplay(event.data); },
+    scoreValidation: function(event) { handleScoreValidation(event.data); }
   });
 });
@@ -75,12 +75,22 @@ var updateResults = function(alliance) {
   result.score.FuelHigh = parseInt(formData[alliance + "FuelHigh"]);
   result.score.Takeoffs = parseInt(formData[alliance + "Takeoffs"]);
 
+  var previousFouls = result.score.Fouls || [];
   result.score.Fouls = [];
   for (var i = 0; formData[alliance + "Foul" + i + "Time"]; i++) {
     var prefix = alliance + "Foul" + i;
     var foul = {TeamId: parseInt(formData[prefix + "Team"]), RuleNumber: formData[prefix + "RuleNumber"],
                 IsTechnical: formData[prefix + "IsTechnical"] == "on",
                 TimeInMatchSec: parseFloat(formData[prefix + "Time"])};
+
+    // Keep the penalty the foul was called with, unless its rule or severity has been changed.
+    var previousFoul = previousFouls[i];
+    if (previousFoul && previousFoul.PenaltySet && previousFoul.RuleNumber == foul.RuleNumber &&
+        previousFoul.IsTechnical == foul.IsTechnical) {
+      foul.Points = previousFoul.Points;
+      foul.RankingPoint = previousFoul.RankingPoint;
+      foul.PenaltySet = true;
+    }
     result.score.Fouls.push(foul);
   }
 
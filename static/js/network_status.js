@@ -0,0 +1,37 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Shared client-side logic for showing the team network configuration status of an alliance station.
+
+// Updates the given status element to reflect the WiFi and Ethernet configuration state of the given station.
+var updateNetworkStatus = function(element, stationStatus) {
+  var wifi = stationStatus.WifiStatus;
+  var ethernet = stationStatus.EthernetStatus;
+
+  var messages = [];
+  if (wifi.Error) {
+    messages.push("WiFi: " + wifi.Error);
+  }
+  if (ethernet.Error) {
+    messages.push("Ethernet: " + ethernet.Error);
+  }
+  element.attr("title", messages.join("\n"));
+
+  if (wifi.State == "failed" || ethernet.State == "failed") {
+    element.attr("data-status-ok", false);
+    var failed = [];
+    if (wifi.State == "failed") {
+      failed.push("W");
+    }
+    if (ethernet.State == "failed") {
+      failed.push("E");
+    }
+    element.text(failed.join("/"));
+  } else if (wifi.State == "configured" && ethernet.State == "configured") {
+    element.attr("data-status-ok", true);
+    element.text("");
+  } else {
+    element.attr("data-status-ok", "");
+    element.text(wifi.State == "" && ethernet.State == "" ? "" : "...");
+  }
+};
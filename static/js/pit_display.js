@@ -80,9 +80,24 @@ var setHighestPlayedMatch = function(highestPlayedMatch) {
   }
 };
 
+// Updates the message showing how far the qualification matches are running ahead of or behind schedule.
+var updateScheduleStatus = function() {
+  $.getJSON("/api/schedule/qualification", function(data) {
+    if (data.Matches.length == 0) {
+      $("#scheduleStatus").text("");
+    } else {
+      var nextMatch = data.Matches[0];
+      $("#scheduleStatus").text("Qualifications: " + data.SlipDescription + " - Match " +
+          nextMatch.DisplayName + " expected at " + moment(nextMatch.PredictedTime).format("h:mm A"));
+    }
+  });
+};
+
 $(function() {
   // Set up the websocket back to the server. Used only for remote forcing of reloads.
   websocket = new CheesyWebsocket("/displays/pit/websocket", {});
 
   updateStaticRankings();
+  updateScheduleStatus();
+  setInterval(updateScheduleStatus, static_update_interval_ms);
 });
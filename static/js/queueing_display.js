@@ -0,0 +1,30 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Client-side methods for the queueing display.
+
+var websocket;
+var update_interval_ms = 10000;
+var num_matches_shown = 6;
+var matchesTemplate = Handlebars.compile($("#matchesTemplate").html());
+
+// Loads the predicted schedule from the event server and shows the next few matches.
+var updateQueue = function() {
+  $.getJSON("/api/schedule/" + matchType, function(data) {
+    $("#scheduleStatus").text(data.SlipDescription);
+    var matches = $.map(data.Matches.slice(0, num_matches_shown), function(match) {
+      match.ScheduledTime = moment(match.Time).format("h:mm A");
+      match.PredictedTime = moment(match.PredictedTime).format("h:mm A");
+      return match;
+    });
+    $("#matches").html(matchesTemplate(matches));
+  });
+};
+
+$(function() {
+  // Set up the websocket back to the server. Used only for remote forcing of reloads.
+  websocket = new CheesyWebsocket("/displays/queueing/websocket", {});
+
+  updateQueue();
+  setInterval(updateQueue, update_interval_ms);
+});
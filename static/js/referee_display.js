@@ -25,10 +25,27 @@ var setFoulRule = function(ruleButton) {
   }
   foulRuleButton = $(ruleButton);
   foulRuleButton.attr("data-selected", true);
+  showRuleDescription();
 
   $("#commit").prop("disabled", !(foulTeamButton && foulRuleButton));
 };
 
+// Shows the description and additional penalties of the selected rule to help the referee confirm the call.
+var showRuleDescription = function() {
+  if (!foulRuleButton) {
+    $("#ruleDescription").text("");
+    return;
+  }
+  var text = foulRuleButton.attr("data-rule") + ": " + foulRuleButton.attr("data-description");
+  if (foulRuleButton.attr("data-triggers-card") == "true") {
+    text += " (also calls for a card)";
+  }
+  if (foulRuleButton.attr("data-awards-ranking-point") == "true") {
+    text += " (awards a ranking point to the opponent)";
+  }
+  $("#ruleDescription").text(text);
+};
+
 // Sets button styles to match the selection cached in the global variables.
 var setSelections = function() {
   $("[data-team]").each(function(i, teamButton) {
@@ -50,6 +67,7 @@ var clearFoul = function() {
     foulRuleButton.attr("data-selected", false);
     foulRuleButton = null;
   }
+  showRuleDescription();
   $("#commit").prop("disabled", true);
 };
 
@@ -66,7 +84,7 @@ var deleteFoul = function(alliance, team, rule, isTechnical, timeSec) {
       IsTechnical: isTechnical, TimeInMatchSec: timeSec});
 };
 
-// Cycles through no card, yellow card, and red card.
+// Cycles through no card, yellow card, and red card. The server upgrades a second yellow card to a red card.
 var cycleCard = function(cardButton) {
   var newCard = "";
   if ($(cardButton).attr("data-card") == "") {
@@ -74,11 +92,40 @@ var cycleCard = function(cardButton) {
   } else if ($(cardButton).attr("data-card") == "yellow") {
     newCard = "red";
   }
+  var reason = "";
+  if (newCard != "") {
+    reason = prompt("Reason for the " + newCard + " card to team " + $(cardButton).attr("data-card-team") + ":");
+    if (reason == null) {
+      return;
+    }
+  }
   websocket.send("card", {Alliance: $(cardButton).attr("data-alliance"),
-      TeamId: parseInt($(cardButton).attr("data-card-team")), Card: newCard});
+      TeamId: parseInt($(cardButton).attr("data-card-team")), Card: newCard, Reason: reason});
   $(cardButton).attr("data-card", newCard);
 };
 
+// Sends the head referee's correction of a sensor-derived score element to the server.
+var overrideScore = function() {
+  var value = parseInt($("#overrideValue").val());
+  var reason = $("#overrideReason").val();
+  if (isNaN(value) || reason == "") {
+    alert("Enter both the corrected value and the reason for the override.");
+    return;
+  }
+  websocket.send("overrideScore", {Alliance: $("#overrideAlliance").val(), Element: $("#overrideElement").val(),
+      Value: value, Reason: reason});
+};
+
+// Removes the override of the given score element, restoring the value counted by the sensors.
+var clearOverride = function(alliance, element) {
+  websocket.send("clearOverride", {Alliance: alliance, Element: element});
+};
+
+// Settles a disagreement between an alliance's scorers by choosing the value to use for all of them.
+var resolveDisagreement = function(alliance, element, value) {
+  websocket.send("resolveDisagreement", {Alliance: alliance, Element: element, Value: value});
+};
+
 // Signals to the teams that they may enter the field.
 var signalReset = function() {
   websocket.send("signalReset");
@@ -92,6 +139,7 @@ var commitMatch = function() {
 $(function() {
   // Set up the websocket back to the server.
   websocket = new CheesyWebsocket("/displays/referee/websocket", {
+    error: function(event) { alert(event.data); }
   });
 
   clearFoul();
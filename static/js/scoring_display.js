@@ -9,16 +9,35 @@ var scoreCommitted = false;
 // Handles a websocket message to update the realtime scoring fields.
 var handleScore = function(data) {
   // Update autonomous period values.
-  var score = data.Score.CurrentScore;
+  var score = data.Scorer ? data.Scorer : data.Score.CurrentScore;
   $("#autoMobility").text(score.AutoMobility);
+  handleOverrides(data.Score.Overrides);
+
+  // Show whether this scorer's inputs are waiting on the head referee to settle a disagreement with another scorer.
+  if (data.Disagreements && data.Disagreements.length > 0) {
+    $("#disagreementMessage").text("Scorers disagree on " + data.Disagreements.join(", ") +
+        "; waiting for the head referee.");
+    $("#disagreementMessage").show();
+  } else {
+    $("#disagreementMessage").hide();
+  }
+
+  // Show the counters for the elements normally counted by the field sensors if they are being scored by hand.
+  $.each(scoreElements, function(element, name) {
+    $("#manual" + element).text(data.Score.CurrentScore[element]);
+  });
+  $("#manualScoringOffer").toggle(data.ManualScoringAvailable && !data.Score.ManualScoring &&
+      !data.Score.TeleopCommitted);
+  $("#manualScoring").toggle(data.Score.ManualScoring && !data.Score.TeleopCommitted);
 
   // Update component visibility.
+  var teleopCommitted = data.Scorer ? data.Scorer.TeleopCommitted : data.Score.TeleopCommitted;
   if (!data.AutoCommitted) {
     $("#autoScoring").fadeTo(0, 1);
     $("#teleopScoring").hide();
     $("#waitingMessage").hide();
     scoreCommitted = false;
-  } else if (!data.Score.TeleopCommitted) {
+  } else if (!teleopCommitted) {
     $("#autoScoring").fadeTo(0, 0.25);
     $("#teleopScoring").show();
     $("#waitingMessage").hide();
@@ -32,6 +51,28 @@ var handleScore = function(data) {
   }
 };
 
+// Handles a websocket message to list the sensor-derived score elements that the head referee has corrected.
+var handleOverrides = function(overrides) {
+  $("#scoreOverrideList").empty();
+  $.each(overrides || [], function(i, override) {
+    $("#scoreOverrideList").append($("<div class='row scoring-comment'>").append(
+        $("<div class='col-lg-4 col-lg-offset-1'>").text(scoreElements[override.Element]),
+        $("<div class='col-lg-2'>").text(override.OriginalValue + " \u2192 " + override.Value),
+        $("<div class='col-lg-5'>").text(override.Reason)));
+  });
+  $("#scoreOverrides").toggle(overrides != null && overrides.length > 0);
+};
+
+// Switches between counting the sensor-derived score elements by hand and using the field sensors.
+var setManualScoring = function(enabled) {
+  websocket.send("manualScoring", enabled);
+};
+
+// Adjusts the hand-counted value of the given sensor-derived score element.
+var manualScore = function(element, delta) {
+  websocket.send("manualScore", {Element: element, Delta: delta});
+};
+
 // Handles a keyboard event and sends the appropriate websocket message.
 var handleKeyPress = function(event) {
   var key = String.fromCharCode(event.keyCode);
@@ -67,9 +108,10 @@ var commitMatchScore = function() {
 
 $(function() {
   // Set up the websocket back to the server.
-  websocket = new CheesyWebsocket("/displays/scoring/" + alliance + "/websocket", {
+  websocket = new CheesyWebsocket("/displays/scoring/" + alliance + "/websocket?scorer=" + scorerId, {
     score: function(event) { handleScore(event.data); },
-    matchTime: function(event) { handleMatchTime(event.data); }
+    matchTime: function(event) { handleMatchTime(event.data); },
+    overrides: function(event) { handleOverrides(event.data); }
   });
 
   $(document).keypress(handleKeyPress);
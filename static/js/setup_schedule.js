@@ -7,7 +7,7 @@ var blockTemplate = Handlebars.compile($("#blockTemplate").html());
 var blockMatches = {};
 
 // Adds a new scheduling block to the page.
-var addBlock = function(startTime, numMatches, matchSpacingSec) {
+var addBlock = function(startTime, numMatches, matchSpacingSec, name) {
   var lastBlockNumber = getLastBlockNumber();
   if (!startTime) {
     if ($.isEmptyObject(blockMatches)) {
@@ -27,6 +27,7 @@ var addBlock = function(startTime, numMatches, matchSpacingSec) {
   var matchSpacingMinSec = moment(matchSpacingSec * 1000).format("m:ss");
   var block = blockTemplate({blockNumber: lastBlockNumber, matchSpacingMinSec: matchSpacingMinSec});
   $("#blockContainer").append(block);
+  $("#name" + lastBlockNumber).val(name || "");
   $("#startTimePicker" + lastBlockNumber).datetimepicker({useSeconds: true}).
       data("DateTimePicker").setDate(startTime);
   $("#endTimePicker" + lastBlockNumber).datetimepicker({useSeconds: true}).
@@ -66,6 +67,23 @@ var updateStats = function() {
   $("#matchesPerTeam").text(matchesPerTeam);
   $("#numExcessMatches").text(numExcessMatches);
   $("#nextLevelMatches").text(nextLevelMatches);
+
+  // Show the break between each block and the one before it, such as for lunch or overnight.
+  var lastEndTime;
+  $.each(getSortedBlockNumbers(), function(i, blockNumber) {
+    var startTime = moment($("#startTime" + blockNumber).val(), "YYYY-MM-DD hh:mm:ss A");
+    var breakText = "";
+    if (lastEndTime) {
+      var breakMinutes = Math.round((startTime - lastEndTime) / 60000);
+      if (breakMinutes < 0) {
+        breakText = "(overlaps the previous block)";
+      } else if (breakMinutes > 0) {
+        breakText = "(" + Math.floor(breakMinutes / 60) + "h " + breakMinutes % 60 + "m break before)";
+      }
+    }
+    $("#breakBefore" + blockNumber).text(breakText);
+    lastEndTime = moment(startTime + blockMatches[blockNumber] * getMatchSpacingSec(blockNumber) * 1000);
+  });
 };
 
 var deleteBlock = function(blockNumber) {
@@ -76,9 +94,23 @@ var deleteBlock = function(blockNumber) {
 
 // Dynamically generates and posts a form containing the schedule blocks to the server for population.
 var generateSchedule = function() {
+  postBlocks("/setup/schedule/generate");
+};
+
+// Posts the schedule blocks to the server to move the existing matches to the new times.
+var retimeSchedule = function() {
+  postBlocks("/setup/schedule/retime");
+};
+
+// Reloads the page with the schedule blocks last used for the given match type.
+var switchMatchType = function(matchType) {
+  window.location = "/setup/schedule?matchType=" + matchType;
+};
+
+var postBlocks = function(action) {
   var form = $("#scheduleForm");
   form.attr("method", "POST");
-  form.attr("action", "/setup/schedule/generate");
+  form.attr("action", action);
   var addField = function(name, value) {
   var field = $(document.createElement("input"));
     field.attr("type", "hidden");
@@ -87,7 +119,8 @@ var generateSchedule = function() {
     form.append(field);
   }
   var i = 0;
-  $.each(blockMatches, function(k, v) {
+  $.each(getSortedBlockNumbers(), function(j, k) {
+    addField("name" + i, $("#name" + k).val());
     addField("startTime" + i, $("#startTime" + k).val());
     addField("numMatches" + i, $("#numMatches" + k).text());
     addField("matchSpacingSec" + i, getMatchSpacingSec(k));
@@ -103,6 +136,16 @@ var getMatchSpacingSec = function(blockNumber) {
   return parseInt(matchSpacingMinSec[0]) * 60 + parseInt(matchSpacingMinSec[1]);
 };
 
+// Returns the numbers of the blocks on the page in order of their start times.
+var getSortedBlockNumbers = function() {
+  var blockNumbers = Object.keys(blockMatches);
+  blockNumbers.sort(function(a, b) {
+    return moment($("#startTime" + a).val(), "YYYY-MM-DD hh:mm:ss A") -
+        moment($("#startTime" + b).val(), "YYYY-MM-DD hh:mm:ss A");
+  });
+  return blockNumbers;
+};
+
 var getLastBlockNumber = function() {
   var max = 0;
   $.each(blockMatches, function(k, v) {
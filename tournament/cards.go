@@ -0,0 +1,139 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Functions for tracking the yellow and red cards issued to teams over the course of the event.
+
+package tournament
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"strconv"
+)
+
+// A card issued to a team in a completed match.
+type TeamCard struct {
+	MatchId          int
+	MatchType        string
+	MatchDisplayName string
+	Alliance         string
+	Card             string
+	Reason           string
+}
+
+// Checks all the match results for yellow and red cards, and updates the team model accordingly.
+func CalculateTeamCards(database *model.Database, matchType string) error {
+	teams, err := database.GetAllTeams()
+	if err != nil {
+		return err
+	}
+	carriedYellowCards, err := getCarriedYellowCards(database, matchType, 0)
+	if err != nil {
+		return err
+	}
+
+	// Save the teams to the database.
+	for _, team := range teams {
+		team.YellowCard = carriedYellowCards[team.Id]
+		err = database.SaveTeam(&team)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns the set of teams that are carrying a yellow card into the given match from the completed matches of the
+// same type played before it. Cards from the qualifications don't carry over into the playoffs, where a card issued to
+// any team on an alliance is carried by all of its teams.
+func GetCarriedYellowCards(database *model.Database, match *model.Match) (map[int]bool, error) {
+	return getCarriedYellowCards(database, match.Type, match.Id)
+}
+
+// Returns the cards issued to each team in completed qualification and playoff matches, in the order they were played.
+func GetCardHistory(database *model.Database) (map[int][]TeamCard, error) {
+	cardHistory := make(map[int][]TeamCard)
+	for _, matchType := range []string{"qualification", "elimination"} {
+		err := forEachCard(database, matchType, 0, func(teamId int, card TeamCard) {
+			cardHistory[teamId] = append(cardHistory[teamId], card)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cardHistory, nil
+}
+
+func getCarriedYellowCards(database *model.Database, matchType string, beforeMatchId int) (map[int]bool, error) {
+	carriedYellowCards := make(map[int]bool)
+	err := forEachCard(database, matchType, beforeMatchId, func(teamId int, card TeamCard) {
+		// A team has a yellow card from then on if they got either a yellow or red card.
+		carriedYellowCards[teamId] = true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if matchType == "elimination" {
+		alliances, err := database.GetAllAlliances()
+		if err != nil {
+			return nil, err
+		}
+		for _, alliance := range alliances {
+			allianceHasYellowCard := false
+			for _, allianceTeam := range alliance {
+				allianceHasYellowCard = allianceHasYellowCard || carriedYellowCards[allianceTeam.TeamId]
+			}
+			if allianceHasYellowCard {
+				for _, allianceTeam := range alliance {
+					carriedYellowCards[allianceTeam.TeamId] = true
+				}
+			}
+		}
+	}
+
+	return carriedYellowCards, nil
+}
+
+// Calls the given function for each card issued in the completed matches of the given type, stopping when the match
+// with the given ID is reached.
+func forEachCard(database *model.Database, matchType string, beforeMatchId int,
+	handleCard func(teamId int, card TeamCard)) error {
+	matches, err := database.GetMatchesByType(matchType)
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		if match.Id == beforeMatchId {
+			break
+		}
+		if match.Status != "complete" {
+			continue
+		}
+		matchResult, err := database.GetMatchResultForMatch(match.Id)
+		if err != nil {
+			return err
+		}
+		if matchResult == nil {
+			continue
+		}
+
+		for _, alliance := range []struct {
+			name    string
+			cards   map[string]string
+			reasons map[string]string
+		}{{"red", matchResult.RedCards, matchResult.RedCardReasons},
+			{"blue", matchResult.BlueCards, matchResult.BlueCardReasons}} {
+			for _, teamId := range []int{match.Red1, match.Red2, match.Red3, match.Blue1, match.Blue2, match.Blue3} {
+				card := alliance.cards[strconv.Itoa(teamId)]
+				if card != game.YellowCard && card != game.RedCard {
+					continue
+				}
+				handleCard(teamId, TeamCard{match.Id, match.Type, match.DisplayName, alliance.name, card,
+					alliance.reasons[strconv.Itoa(teamId)]})
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package tournament
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCarriedYellowCardsAndHistory(t *testing.T) {
+	database := setupTestDb(t)
+	CreateTestAlliances(database, 2)
+	database.CreateTeam(&model.Team{Id: 1})
+	database.CreateTeam(&model.Team{Id: 10})
+	database.CreateTeam(&model.Team{Id: 2})
+
+	qual1 := createCardTestMatch(database, "qualification", "1", map[string]string{"1": "yellow"},
+		map[string]string{"1": "G20 pinning"})
+	qual2 := createCardTestMatch(database, "qualification", "2", map[string]string{}, map[string]string{})
+	elim1 := createCardTestMatch(database, "elimination", "SF1-1", map[string]string{"10": "yellow"},
+		map[string]string{})
+	elim2 := createCardTestMatch(database, "elimination", "SF1-2", map[string]string{}, map[string]string{})
+
+	// Only cards from matches before the given one should be carried.
+	carriedYellowCards, err := GetCarriedYellowCards(database, qual1)
+	assert.Nil(t, err)
+	assert.Empty(t, carriedYellowCards)
+	carriedYellowCards, err = GetCarriedYellowCards(database, qual2)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]bool{1: true}, carriedYellowCards)
+
+	// Qualification cards don't carry into the playoffs, where a card applies to the whole alliance.
+	carriedYellowCards, err = GetCarriedYellowCards(database, elim1)
+	assert.Nil(t, err)
+	assert.Empty(t, carriedYellowCards)
+	carriedYellowCards, err = GetCarriedYellowCards(database, elim2)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]bool{1: true, 10: true, 100: true}, carriedYellowCards)
+
+	assert.Nil(t, CalculateTeamCards(database, "elimination"))
+	team, _ := database.GetTeamById(1)
+	assert.True(t, team.YellowCard)
+	team, _ = database.GetTeamById(2)
+	assert.False(t, team.YellowCard)
+
+	cardHistory, err := GetCardHistory(database)
+	assert.Nil(t, err)
+	assert.Equal(t, []TeamCard{{qual1.Id, "qualification", "1", "red", "yellow", "G20 pinning"}}, cardHistory[1])
+	assert.Equal(t, []TeamCard{{elim1.Id, "elimination", "SF1-1", "red", "yellow", ""}}, cardHistory[10])
+	assert.Empty(t, cardHistory[100])
+}
+
+func createCardTestMatch(database *model.Database, matchType, displayName string, redCards,
+	redCardReasons map[string]string) *model.Match {
+	match := &model.Match{Type: matchType, DisplayName: displayName, Status: "complete", Red1: 1, Red2: 10,
+		Red3: 100, Blue1: 2, Blue2: 20, Blue3: 200}
+	database.CreateMatch(match)
+	matchResult := model.NewMatchResult()
+	matchResult.MatchId = match.Id
+	matchResult.RedCards = redCards
+	matchResult.RedCardReasons = redCardReasons
+	database.CreateMatchResult(matchResult)
+	return match
+}
@@ -0,0 +1,188 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Functions for measuring how long it actually took to turn the field over between matches.
+
+package tournament
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"time"
+)
+
+// The measured timings of a set of played matches, along with their summaries over the whole set, each schedule
+// block and each hour of play.
+type CycleTimeReport struct {
+	Matches []MatchCycleTime
+	Overall CycleTimeSummary
+	ByBlock []CycleTimeSummary
+	ByHour  []CycleTimeSummary
+}
+
+// The timings of a single match. A duration is zero if the milestones needed to measure it weren't recorded, or if the
+// match is the first to be played in its block.
+type MatchCycleTime struct {
+	model.Match
+	Block                 string
+	CycleTimeSec          int
+	CommitTimeSec         int
+	ResetToStartSec       int
+	ScheduledCycleTimeSec int
+}
+
+// Aggregate timings for a group of matches.
+type CycleTimeSummary struct {
+	Name               string
+	NumMatches         int
+	NumCycles          int
+	AvgCycleTimeSec    int
+	MinCycleTimeSec    int
+	MaxCycleTimeSec    int
+	AvgCommitTimeSec   int
+	AvgResetToStartSec int
+	totalCycleTimeSec  int
+	totalCommitTimeSec int
+	numCommits         int
+	totalResetTimeSec  int
+	numResets          int
+}
+
+// Measures the time between the starts of consecutive matches in the same block, the time the scorers took to commit
+// after each match ended and the time from each field reset until the next match started. The matches are expected to
+// be in schedule order; unplayed ones are left out of the report. Matches are grouped into the given schedule blocks
+// by their scheduled time, or into blocks inferred from the breaks in the schedule if there are none that fit.
+func CalculateCycleTimes(matches []model.Match, scheduleBlocks []model.ScheduleBlock) *CycleTimeReport {
+	report := &CycleTimeReport{Matches: []MatchCycleTime{}, Overall: CycleTimeSummary{Name: "All matches"},
+		ByBlock: []CycleTimeSummary{}, ByHour: []CycleTimeSummary{}}
+	blockNames := assignBlockNames(matches, scheduleBlocks)
+
+	blockIndices := make(map[string]int)
+	hourIndices := make(map[time.Time]int)
+	for i, match := range matches {
+		if match.StartedAt.IsZero() {
+			continue
+		}
+		cycleTime := MatchCycleTime{Match: match, Block: blockNames[i]}
+		if i > 0 && blockNames[i-1] == blockNames[i] {
+			previousMatch := matches[i-1]
+			cycleTime.ScheduledCycleTimeSec = int(match.Time.Sub(previousMatch.Time).Seconds())
+			if !previousMatch.StartedAt.IsZero() {
+				cycleTime.CycleTimeSec = positiveSeconds(previousMatch.StartedAt, match.StartedAt)
+			}
+			if !previousMatch.FieldResetAt.IsZero() {
+				cycleTime.ResetToStartSec = positiveSeconds(previousMatch.FieldResetAt, match.StartedAt)
+			}
+		}
+		if !match.EndedAt.IsZero() && !match.ScoresCommittedAt.IsZero() {
+			cycleTime.CommitTimeSec = positiveSeconds(match.EndedAt, match.ScoresCommittedAt)
+		}
+		report.Matches = append(report.Matches, cycleTime)
+
+		report.Overall.add(&cycleTime)
+		blockIndex, ok := blockIndices[cycleTime.Block]
+		if !ok {
+			blockIndex = len(report.ByBlock)
+			blockIndices[cycleTime.Block] = blockIndex
+			report.ByBlock = append(report.ByBlock, CycleTimeSummary{Name: cycleTime.Block})
+		}
+		report.ByBlock[blockIndex].add(&cycleTime)
+		localStart := match.StartedAt.Local()
+		hour := time.Date(localStart.Year(), localStart.Month(), localStart.Day(), localStart.Hour(), 0, 0, 0,
+			time.Local)
+		hourIndex, ok := hourIndices[hour]
+		if !ok {
+			hourIndex = len(report.ByHour)
+			hourIndices[hour] = hourIndex
+			report.ByHour = append(report.ByHour, CycleTimeSummary{Name: hour.Format("Mon 15:04")})
+		}
+		report.ByHour[hourIndex].add(&cycleTime)
+	}
+
+	report.Overall.average()
+	for i := range report.ByBlock {
+		report.ByBlock[i].average()
+	}
+	for i := range report.ByHour {
+		report.ByHour[i].average()
+	}
+	return report
+}
+
+// Returns the name of the block that each of the given matches belongs to.
+func assignBlockNames(matches []model.Match, scheduleBlocks []model.ScheduleBlock) []string {
+	blockNames := make([]string, len(matches))
+	for i, match := range matches {
+		for j, block := range scheduleBlocks {
+			if !match.Time.Before(block.StartTime) && match.Time.Before(block.EndTime()) {
+				blockNames[i] = block.Name
+				if blockNames[i] == "" {
+					blockNames[i] = fmt.Sprintf("Block %d", j+1)
+				}
+				break
+			}
+		}
+		if blockNames[i] == "" {
+			// Fall back to inferring the blocks from the schedule if the saved ones don't cover every match.
+			blockNumber := 1
+			for j := range matches {
+				if isBlockStart(matches, j) {
+					blockNumber++
+				}
+				blockNames[j] = fmt.Sprintf("Block %d", blockNumber)
+			}
+			break
+		}
+	}
+	return blockNames
+}
+
+func (summary *CycleTimeSummary) add(cycleTime *MatchCycleTime) {
+	summary.NumMatches++
+	if cycleTime.CycleTimeSec > 0 {
+		if summary.NumCycles == 0 || cycleTime.CycleTimeSec < summary.MinCycleTimeSec {
+			summary.MinCycleTimeSec = cycleTime.CycleTimeSec
+		}
+		if cycleTime.CycleTimeSec > summary.MaxCycleTimeSec {
+			summary.MaxCycleTimeSec = cycleTime.CycleTimeSec
+		}
+		summary.NumCycles++
+		summary.totalCycleTimeSec += cycleTime.CycleTimeSec
+	}
+	if cycleTime.CommitTimeSec > 0 {
+		summary.numCommits++
+		summary.totalCommitTimeSec += cycleTime.CommitTimeSec
+	}
+	if cycleTime.ResetToStartSec > 0 {
+		summary.numResets++
+		summary.totalResetTimeSec += cycleTime.ResetToStartSec
+	}
+}
+
+func (summary *CycleTimeSummary) average() {
+	if summary.NumCycles > 0 {
+		summary.AvgCycleTimeSec = summary.totalCycleTimeSec / summary.NumCycles
+	}
+	if summary.numCommits > 0 {
+		summary.AvgCommitTimeSec = summary.totalCommitTimeSec / summary.numCommits
+	}
+	if summary.numResets > 0 {
+		summary.AvgResetToStartSec = summary.totalResetTimeSec / summary.numResets
+	}
+}
+
+// Returns the whole number of seconds from the first time to the second, or zero if they are out of order.
+func positiveSeconds(from, to time.Time) int {
+	if seconds := int(to.Sub(from).Seconds()); seconds > 0 {
+		return seconds
+	}
+	return 0
+}
+
+// Formats the given number of seconds as minutes and seconds, or a dash if it is zero.
+func FormatCycleTime(seconds int) string {
+	if seconds <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
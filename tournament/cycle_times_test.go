@@ -0,0 +1,97 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package tournament
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCalculateCycleTimesEmpty(t *testing.T) {
+	report := CalculateCycleTimes([]model.Match{{Time: time.Now()}}, []model.ScheduleBlock{})
+	assert.Empty(t, report.Matches)
+	assert.Equal(t, 0, report.Overall.NumMatches)
+	assert.Empty(t, report.ByBlock)
+	assert.Empty(t, report.ByHour)
+}
+
+func TestCalculateCycleTimes(t *testing.T) {
+	startTime := time.Date(2017, 8, 5, 9, 0, 0, 0, time.Local)
+	matches := buildCycleTimeMatches(startTime)
+	scheduleBlocks := []model.ScheduleBlock{{0, "qualification", "Morning", startTime, 3, 360},
+		{0, "qualification", "", startTime.Add(78 * time.Minute), 2, 360}}
+
+	report := CalculateCycleTimes(matches, scheduleBlocks)
+	if assert.Equal(t, 4, len(report.Matches)) {
+		assert.Equal(t, MatchCycleTime{matches[0], "Morning", 0, 30, 0, 0}, report.Matches[0])
+		assert.Equal(t, MatchCycleTime{matches[1], "Morning", 420, 60, 180, 360}, report.Matches[1])
+		assert.Equal(t, MatchCycleTime{matches[2], "Morning", 450, 0, 150, 360}, report.Matches[2])
+		assert.Equal(t, MatchCycleTime{matches[3], "Block 2", 0, 0, 0, 0}, report.Matches[3])
+	}
+
+	assert.Equal(t, "All matches", report.Overall.Name)
+	assert.Equal(t, 4, report.Overall.NumMatches)
+	assert.Equal(t, 2, report.Overall.NumCycles)
+	assert.Equal(t, 435, report.Overall.AvgCycleTimeSec)
+	assert.Equal(t, 420, report.Overall.MinCycleTimeSec)
+	assert.Equal(t, 450, report.Overall.MaxCycleTimeSec)
+	assert.Equal(t, 45, report.Overall.AvgCommitTimeSec)
+	assert.Equal(t, 165, report.Overall.AvgResetToStartSec)
+
+	if assert.Equal(t, 2, len(report.ByBlock)) {
+		assert.Equal(t, "Morning", report.ByBlock[0].Name)
+		assert.Equal(t, 3, report.ByBlock[0].NumMatches)
+		assert.Equal(t, 435, report.ByBlock[0].AvgCycleTimeSec)
+		assert.Equal(t, "Block 2", report.ByBlock[1].Name)
+		assert.Equal(t, 1, report.ByBlock[1].NumMatches)
+		assert.Equal(t, 0, report.ByBlock[1].NumCycles)
+	}
+	if assert.Equal(t, 2, len(report.ByHour)) {
+		assert.Equal(t, "Sat 09:00", report.ByHour[0].Name)
+		assert.Equal(t, 3, report.ByHour[0].NumMatches)
+		assert.Equal(t, "Sat 10:00", report.ByHour[1].Name)
+		assert.Equal(t, 1, report.ByHour[1].NumMatches)
+	}
+
+	// Check that the blocks are inferred from the schedule if none have been saved.
+	report = CalculateCycleTimes(matches, []model.ScheduleBlock{})
+	assert.Equal(t, "Block 1", report.Matches[2].Block)
+	assert.Equal(t, "Block 2", report.Matches[3].Block)
+	assert.Equal(t, 450, report.Matches[2].CycleTimeSec)
+	assert.Equal(t, 0, report.Matches[3].CycleTimeSec)
+}
+
+func TestFormatCycleTime(t *testing.T) {
+	assert.Equal(t, "-", FormatCycleTime(0))
+	assert.Equal(t, "0:45", FormatCycleTime(45))
+	assert.Equal(t, "7:15", FormatCycleTime(435))
+	assert.Equal(t, "61:00", FormatCycleTime(3660))
+}
+
+// Returns two blocks of matches spaced six minutes apart, of which all but the last have been played.
+func buildCycleTimeMatches(startTime time.Time) []model.Match {
+	matches := make([]model.Match, 5)
+	for i := range matches {
+		matches[i].Id = i + 1
+		matches[i].DisplayName = strconv.Itoa(i + 1)
+		matches[i].Time = startTime.Add(time.Duration(i) * 6 * time.Minute)
+		if i >= 3 {
+			matches[i].Time = matches[i].Time.Add(time.Hour)
+		}
+	}
+	matches[0].StartedAt = startTime.Add(time.Minute)
+	matches[0].EndedAt = matches[0].StartedAt.Add(150 * time.Second)
+	matches[0].ScoresCommittedAt = matches[0].EndedAt.Add(30 * time.Second)
+	matches[0].FieldResetAt = matches[0].EndedAt.Add(90 * time.Second)
+	matches[1].StartedAt = matches[0].StartedAt.Add(7 * time.Minute)
+	matches[1].EndedAt = matches[1].StartedAt.Add(150 * time.Second)
+	matches[1].ScoresCommittedAt = matches[1].EndedAt.Add(time.Minute)
+	matches[1].FieldResetAt = matches[1].StartedAt.Add(5 * time.Minute)
+	matches[2].StartedAt = matches[1].StartedAt.Add(450 * time.Second)
+	matches[3].StartedAt = matches[3].Time.Add(20 * time.Second)
+	return matches
+}
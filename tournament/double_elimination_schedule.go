@@ -0,0 +1,98 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Functions for creating and updating the match schedule of an eight-alliance double-elimination playoff bracket.
+
+package tournament
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+)
+
+// The bracket matches are all stored as the same round with the match number as the group, which is how The Blue
+// Alliance expects double-elimination matches to be keyed (e.g. "sf13m1"). The finals are stored as in a
+// single-elimination bracket.
+const doubleElimBracketRound = 2
+
+// Where one of the alliances in a double-elimination match comes from: either an alliance selection seed, or the
+// winner or loser of an earlier match.
+type doubleElimSource struct {
+	seed        int
+	matchNumber int
+	isWinner    bool
+}
+
+// The bracket matches in the order they are played, each with the sources of its red and blue alliances. The winners
+// of matches 11 and 13 go on to the finals.
+var doubleElimBracket = []struct {
+	red  doubleElimSource
+	blue doubleElimSource
+}{
+	{seedOf(1), seedOf(8)},
+	{seedOf(4), seedOf(5)},
+	{seedOf(2), seedOf(7)},
+	{seedOf(3), seedOf(6)},
+	{loserOf(1), loserOf(2)},
+	{loserOf(3), loserOf(4)},
+	{winnerOf(1), winnerOf(2)},
+	{winnerOf(3), winnerOf(4)},
+	{loserOf(7), winnerOf(6)},
+	{loserOf(8), winnerOf(5)},
+	{winnerOf(7), winnerOf(8)},
+	{winnerOf(10), winnerOf(9)},
+	{loserOf(11), winnerOf(12)},
+}
+
+// Creates any double-elimination matches that can be created, based on the results of alliance selection or prior
+// matches. Returns the winner of the finals if known.
+func buildDoubleEliminationMatches(database *model.Database, numAlliances int) ([]int, error) {
+	if numAlliances != 8 {
+		return []int{}, fmt.Errorf("Double-elimination playoffs require 8 alliances")
+	}
+
+	winners := make(map[int][]int)
+	losers := make(map[int][]int)
+	getAlliance := func(source doubleElimSource, isRed bool) ([]int, error) {
+		if source.seed > 0 {
+			return getSelectedAlliance(database, source.seed, isRed)
+		}
+		if source.isWinner {
+			return winners[source.matchNumber], nil
+		}
+		return losers[source.matchNumber], nil
+	}
+
+	for i, bracketMatch := range doubleElimBracket {
+		matchNumber := i + 1
+		redAlliance, err := getAlliance(bracketMatch.red, true)
+		if err != nil {
+			return []int{}, err
+		}
+		blueAlliance, err := getAlliance(bracketMatch.blue, false)
+		if err != nil {
+			return []int{}, err
+		}
+		winners[matchNumber], losers[matchNumber], err = updateMatchSet(database, fmt.Sprintf("M%d", matchNumber),
+			doubleElimBracketRound, matchNumber, 1, redAlliance, blueAlliance)
+		if err != nil {
+			return []int{}, err
+		}
+	}
+
+	// The finals are a best-of-three between the winners of the upper and lower brackets.
+	winner, _, err := updateMatchSet(database, model.ElimRoundNames[1], 1, 1, 2, winners[11], winners[13])
+	return winner, err
+}
+
+func seedOf(allianceNumber int) doubleElimSource {
+	return doubleElimSource{seed: allianceNumber}
+}
+
+func winnerOf(matchNumber int) doubleElimSource {
+	return doubleElimSource{matchNumber: matchNumber, isWinner: true}
+}
+
+func loserOf(matchNumber int) doubleElimSource {
+	return doubleElimSource{matchNumber: matchNumber}
+}
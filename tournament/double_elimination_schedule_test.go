@@ -0,0 +1,138 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package tournament
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDoubleEliminationScheduleInitial(t *testing.T) {
+	database := setupDoubleEliminationTestDb(t)
+
+	CreateTestAlliances(database, 8)
+	won, err := UpdateEliminationSchedule(database, time.Unix(0, 0))
+	assert.Nil(t, err)
+	assert.False(t, won)
+	matches, err := database.GetMatchesByType("elimination")
+	assert.Nil(t, err)
+	if assert.Equal(t, 4, len(matches)) {
+		assertMatch(t, matches[0], "M1-1", 1, 8)
+		assertMatch(t, matches[1], "M2-1", 4, 5)
+		assertMatch(t, matches[2], "M3-1", 2, 7)
+		assertMatch(t, matches[3], "M4-1", 3, 6)
+		assert.Equal(t, "sf1m1", matches[0].TbaCode())
+		assert.Equal(t, "sf4m1", matches[3].TbaCode())
+	}
+}
+
+func TestDoubleEliminationScheduleErrors(t *testing.T) {
+	database := setupDoubleEliminationTestDb(t)
+
+	CreateTestAlliances(database, 4)
+	_, err := UpdateEliminationSchedule(database, time.Unix(0, 0))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Double-elimination playoffs require 8 alliances", err.Error())
+	}
+}
+
+func TestDoubleEliminationScheduleProgression(t *testing.T) {
+	database := setupDoubleEliminationTestDb(t)
+
+	CreateTestAlliances(database, 8)
+	UpdateEliminationSchedule(database, time.Unix(0, 0))
+	scoreMatch(database, "M1-1", "R")
+	scoreMatch(database, "M2-1", "B")
+	scoreMatch(database, "M3-1", "R")
+	scoreMatch(database, "M4-1", "B")
+	_, err := UpdateEliminationSchedule(database, time.Unix(0, 0))
+	assert.Nil(t, err)
+	matches, _ := database.GetMatchesByType("elimination")
+	if assert.Equal(t, 8, len(matches)) {
+		assertMatch(t, matches[4], "M5-1", 8, 4)
+		assertMatch(t, matches[5], "M6-1", 7, 3)
+		assertMatch(t, matches[6], "M7-1", 1, 5)
+		assertMatch(t, matches[7], "M8-1", 2, 6)
+	}
+
+	scoreMatch(database, "M5-1", "R")
+	scoreMatch(database, "M6-1", "B")
+	scoreMatch(database, "M7-1", "R")
+	scoreMatch(database, "M8-1", "B")
+	UpdateEliminationSchedule(database, time.Unix(0, 0))
+	matches, _ = database.GetMatchesByType("elimination")
+	if assert.Equal(t, 11, len(matches)) {
+		assertMatch(t, matches[8], "M9-1", 5, 3)
+		assertMatch(t, matches[9], "M10-1", 2, 8)
+		assertMatch(t, matches[10], "M11-1", 1, 6)
+	}
+
+	// Check that the lower bracket final is created as soon as one of its alliances is known.
+	scoreMatch(database, "M9-1", "B")
+	scoreMatch(database, "M10-1", "R")
+	scoreMatch(database, "M11-1", "R")
+	UpdateEliminationSchedule(database, time.Unix(0, 0))
+	matches, _ = database.GetMatchesByType("elimination")
+	if assert.Equal(t, 16, len(matches)) {
+		assertMatch(t, matches[11], "M12-1", 2, 3)
+		assertMatch(t, matches[12], "M13-1", 6, 0)
+		assertMatch(t, matches[13], "F-1", 1, 0)
+		assertMatch(t, matches[14], "F-2", 1, 0)
+		assertMatch(t, matches[15], "F-3", 1, 0)
+	}
+
+	scoreMatch(database, "M12-1", "B")
+	UpdateEliminationSchedule(database, time.Unix(0, 0))
+	matches, _ = database.GetMatchesByType("elimination")
+	assertMatch(t, matches[12], "M13-1", 6, 3)
+	scoreMatch(database, "M13-1", "B")
+	won, err := UpdateEliminationSchedule(database, time.Unix(0, 0))
+	assert.Nil(t, err)
+	assert.False(t, won)
+	matches, _ = database.GetMatchesByType("elimination")
+	if assert.Equal(t, 16, len(matches)) {
+		assertMatch(t, matches[13], "F-1", 1, 3)
+		assert.Equal(t, "f1m1", matches[13].TbaCode())
+	}
+
+	scoreMatch(database, "F-1", "B")
+	scoreMatch(database, "F-2", "B")
+	won, err = UpdateEliminationSchedule(database, time.Unix(0, 0))
+	assert.Nil(t, err)
+	assert.True(t, won)
+	matches, _ = database.GetMatchesByType("elimination")
+	assert.Equal(t, 15, len(matches))
+}
+
+func TestDoubleEliminationScheduleTie(t *testing.T) {
+	database := setupDoubleEliminationTestDb(t)
+
+	CreateTestAlliances(database, 8)
+	UpdateEliminationSchedule(database, time.Unix(0, 0))
+	scoreMatch(database, "M1-1", "T")
+	_, err := UpdateEliminationSchedule(database, time.Unix(0, 0))
+	assert.Nil(t, err)
+	matches, _ := database.GetMatchesByType("elimination")
+	if assert.Equal(t, 5, len(matches)) {
+		assertMatch(t, matches[1], "M1-2", 1, 8)
+	}
+
+	scoreMatch(database, "M1-2", "B")
+	UpdateEliminationSchedule(database, time.Unix(0, 0))
+	matches, _ = database.GetMatchesByType("elimination")
+	if assert.Equal(t, 7, len(matches)) {
+		assertMatch(t, matches[5], "M5-1", 1, 0)
+		assertMatch(t, matches[6], "M7-1", 8, 0)
+	}
+}
+
+func setupDoubleEliminationTestDb(t *testing.T) *model.Database {
+	database := setupTestDb(t)
+	eventSettings, _ := database.GetEventSettings()
+	eventSettings.ElimType = "double"
+	database.SaveEventSettings(eventSettings)
+	return database
+}
@@ -17,11 +17,19 @@ const ElimMatchSpacingSec = 600
 // Incrementally creates any elimination matches that can be created, based on the results of alliance
 // selection or prior elimination rounds. Returns true if the tournament is won.
 func UpdateEliminationSchedule(database *model.Database, startTime time.Time) (bool, error) {
+	eventSettings, err := database.GetEventSettings()
+	if err != nil {
+		return false, err
+	}
 	alliances, err := database.GetAllAlliances()
 	if err != nil {
 		return false, err
 	}
-	winner, err := buildEliminationMatchSet(database, 1, 1, len(alliances))
+	playoffFormat, err := GetPlayoffFormat(eventSettings)
+	if err != nil {
+		return false, err
+	}
+	winner, err := playoffFormat.UpdateMatches(database, eventSettings, len(alliances))
 	if err != nil {
 		return false, err
 	}
@@ -71,33 +79,17 @@ func buildEliminationMatchSet(database *model.Database, round int, group int, nu
 		numDirectAlliances := 4*round - numAlliances
 		if redAllianceNumber <= numDirectAlliances {
 			// The red alliance has a bye or the number of alliances is a power of 2; get from alliance selection.
-			redAllianceTeams, err := database.GetTeamsByAlliance(redAllianceNumber)
+			redAlliance, err = getSelectedAlliance(database, redAllianceNumber, true)
 			if err != nil {
 				return []int{}, err
 			}
-			for _, allianceTeam := range redAllianceTeams {
-				redAlliance = append(redAlliance, allianceTeam.TeamId)
-			}
-
-			if len(redAlliance) >= 3 {
-				// Swap the teams around to match the positions dictated by the 2017 rules.
-				redAlliance[0], redAlliance[1], redAlliance[2] = redAlliance[2], redAlliance[0], redAlliance[1]
-			}
 		}
 		if blueAllianceNumber <= numDirectAlliances {
 			// The blue alliance has a bye or the number of alliances is a power of 2; get from alliance selection.
-			blueAllianceTeams, err := database.GetTeamsByAlliance(blueAllianceNumber)
+			blueAlliance, err = getSelectedAlliance(database, blueAllianceNumber, false)
 			if err != nil {
 				return []int{}, err
 			}
-			for _, allianceTeam := range blueAllianceTeams {
-				blueAlliance = append(blueAlliance, allianceTeam.TeamId)
-			}
-
-			if len(blueAlliance) >= 3 {
-				// Swap the teams around to match the positions dictated by the 2017 rules.
-				blueAlliance[0], blueAlliance[1], blueAlliance[2] = blueAlliance[1], blueAlliance[0], blueAlliance[2]
-			}
 		}
 	}
 
@@ -115,9 +107,40 @@ func buildEliminationMatchSet(database *model.Database, round int, group int, nu
 		}
 	}
 
+	winner, _, err := updateMatchSet(database, roundName, round, group, 2, redAlliance, blueAlliance)
+	return winner, err
+}
+
+// Returns the teams of the given alliance from the alliance selection results, in the positions they take when
+// starting out on the given side of the bracket.
+func getSelectedAlliance(database *model.Database, allianceNumber int, isRed bool) ([]int, error) {
+	allianceTeams, err := database.GetTeamsByAlliance(allianceNumber)
+	if err != nil {
+		return []int{}, err
+	}
+	var alliance []int
+	for _, allianceTeam := range allianceTeams {
+		alliance = append(alliance, allianceTeam.TeamId)
+	}
+
+	if len(alliance) >= 3 {
+		// Swap the teams around to match the positions dictated by the 2017 rules.
+		if isRed {
+			alliance[0], alliance[1], alliance[2] = alliance[2], alliance[0], alliance[1]
+		} else {
+			alliance[0], alliance[1] = alliance[1], alliance[0]
+		}
+	}
+	return alliance, nil
+}
+
+// Creates, updates or prunes the matches between the given alliances at the given point in the bracket, of which an
+// alliance needs to win the given number to advance. Returns the winning and losing alliances if the set is decided.
+func updateMatchSet(database *model.Database, roundName string, round int, group int, numWins int, redAlliance,
+	blueAlliance []int) ([]int, []int, error) {
 	// Bail if the rounds below are not yet complete and we don't know either alliance competing this round.
 	if len(redAlliance) == 0 && len(blueAlliance) == 0 {
-		return []int{}, nil
+		return []int{}, []int{}, nil
 	}
 
 	// Check if the match set exists already and if it has been won.
@@ -125,7 +148,7 @@ func buildEliminationMatchSet(database *model.Database, round int, group int, nu
 	var ties []*model.Match
 	matches, err := database.GetMatchesByElimRoundGroup(round, group)
 	if err != nil {
-		return []int{}, err
+		return []int{}, []int{}, err
 	}
 	var unplayedMatches []*model.Match
 	for _, match := range matches {
@@ -150,11 +173,11 @@ func buildEliminationMatchSet(database *model.Database, round int, group int, nu
 		// Reorder the teams based on the last complete match, so that new and unplayed matches use the same positions.
 		err = reorderTeams(match.Red1, match.Red2, match.Red3, redAlliance)
 		if err != nil {
-			return []int{}, err
+			return []int{}, []int{}, err
 		}
 		err = reorderTeams(match.Blue1, match.Blue2, match.Blue3, blueAlliance)
 		if err != nil {
-			return []int{}, err
+			return []int{}, []int{}, err
 		}
 
 		// Check who won.
@@ -166,24 +189,24 @@ func buildEliminationMatchSet(database *model.Database, round int, group int, nu
 		case "T":
 			ties = append(ties, &match)
 		default:
-			return []int{}, fmt.Errorf("Completed match %d has invalid winner '%s'", match.Id, match.Winner)
+			return []int{}, []int{}, fmt.Errorf("Completed match %d has invalid winner '%s'", match.Id, match.Winner)
 		}
 	}
 
 	// Delete any superfluous matches if the round is won.
-	if redWins == 2 || blueWins == 2 {
+	if redWins == numWins || blueWins == numWins {
 		for _, match := range unplayedMatches {
 			err = database.DeleteMatch(match)
 			if err != nil {
-				return []int{}, err
+				return []int{}, []int{}, err
 			}
 		}
 
 		// Bail out and announce the winner of this round.
-		if redWins == 2 {
-			return redAlliance, nil
+		if redWins == numWins {
+			return redAlliance, blueAlliance, nil
 		} else {
-			return blueAlliance, nil
+			return blueAlliance, redAlliance, nil
 		}
 	}
 
@@ -198,24 +221,12 @@ func buildEliminationMatchSet(database *model.Database, round int, group int, nu
 		}
 		if len(redAlliance) < 3 || len(blueAlliance) < 3 {
 			// Raise an error if the alliance selection process gave us less than 3 teams per alliance.
-			return []int{}, fmt.Errorf("Alliances must consist of at least 3 teams")
+			return []int{}, []int{}, fmt.Errorf("Alliances must consist of at least 3 teams")
 		}
-		if len(matches) < 1 {
-			err = database.CreateMatch(createMatch(roundName, round, group, 1, redAlliance, blueAlliance))
+		for instance := len(matches) + 1; instance <= 2*numWins-1; instance++ {
+			err = database.CreateMatch(createMatch(roundName, round, group, instance, redAlliance, blueAlliance))
 			if err != nil {
-				return []int{}, err
-			}
-		}
-		if len(matches) < 2 {
-			err = database.CreateMatch(createMatch(roundName, round, group, 2, redAlliance, blueAlliance))
-			if err != nil {
-				return []int{}, err
-			}
-		}
-		if len(matches) < 3 {
-			err = database.CreateMatch(createMatch(roundName, round, group, 3, redAlliance, blueAlliance))
-			if err != nil {
-				return []int{}, err
+				return []int{}, []int{}, err
 			}
 		}
 	}
@@ -226,12 +237,12 @@ func buildEliminationMatchSet(database *model.Database, round int, group int, nu
 			err = database.CreateMatch(createMatch(roundName, round, group, len(matches)+index+1, redAlliance,
 				blueAlliance))
 			if err != nil {
-				return []int{}, err
+				return []int{}, []int{}, err
 			}
 		}
 	}
 
-	return []int{}, nil
+	return []int{}, []int{}, nil
 }
 
 // Creates a match at the given point in the elimination bracket and populates the teams.
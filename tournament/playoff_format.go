@@ -0,0 +1,88 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Abstraction over the different ways in which the playoff matches can be run.
+
+package tournament
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+)
+
+// A way of running the playoffs between the alliances chosen in alliance selection.
+type PlayoffFormat interface {
+	// Returns the name of the format as shown on the settings page.
+	Description() string
+
+	// Returns an error if the format can't be run with the given event settings.
+	CheckSettings(eventSettings *model.EventSettings) error
+
+	// Creates any playoff matches that can be created, based on the results of alliance selection or prior matches.
+	// Returns the winning alliance if known.
+	UpdateMatches(database *model.Database, eventSettings *model.EventSettings, numAlliances int) ([]int, error)
+}
+
+// The playoff formats that can be chosen in the event settings, keyed by the stored value.
+var PlayoffFormats = map[string]PlayoffFormat{
+	"single":     singleEliminationFormat{},
+	"double":     doubleEliminationFormat{},
+	"roundRobin": roundRobinFormat{},
+}
+
+// Returns the playoff format selected in the given event settings.
+func GetPlayoffFormat(eventSettings *model.EventSettings) (PlayoffFormat, error) {
+	playoffFormat, ok := PlayoffFormats[eventSettings.ElimType]
+	if !ok {
+		return nil, fmt.Errorf("Invalid playoff format '%s'", eventSettings.ElimType)
+	}
+	return playoffFormat, nil
+}
+
+type singleEliminationFormat struct{}
+
+func (singleEliminationFormat) Description() string {
+	return "Single-elimination (best of 3)"
+}
+
+func (singleEliminationFormat) CheckSettings(eventSettings *model.EventSettings) error {
+	return nil
+}
+
+func (singleEliminationFormat) UpdateMatches(database *model.Database, eventSettings *model.EventSettings,
+	numAlliances int) ([]int, error) {
+	return buildEliminationMatchSet(database, 1, 1, numAlliances)
+}
+
+type doubleEliminationFormat struct{}
+
+func (doubleEliminationFormat) Description() string {
+	return "Double-elimination (8 alliances)"
+}
+
+func (doubleEliminationFormat) CheckSettings(eventSettings *model.EventSettings) error {
+	if eventSettings.NumElimAlliances != 8 {
+		return fmt.Errorf("Double-elimination playoffs require 8 alliances")
+	}
+	return nil
+}
+
+func (doubleEliminationFormat) UpdateMatches(database *model.Database, eventSettings *model.EventSettings,
+	numAlliances int) ([]int, error) {
+	return buildDoubleEliminationMatches(database, numAlliances)
+}
+
+type roundRobinFormat struct{}
+
+func (roundRobinFormat) Description() string {
+	return "Round-robin (top 2 to finals)"
+}
+
+func (roundRobinFormat) CheckSettings(eventSettings *model.EventSettings) error {
+	return checkRoundRobinSettings(eventSettings.NumElimAlliances, eventSettings.RoundRobinMatchesPerAlliance)
+}
+
+func (roundRobinFormat) UpdateMatches(database *model.Database, eventSettings *model.EventSettings,
+	numAlliances int) ([]int, error) {
+	return buildRoundRobinMatches(database, numAlliances, eventSettings.RoundRobinMatchesPerAlliance)
+}
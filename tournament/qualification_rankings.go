@@ -59,57 +59,6 @@ func CalculateRankings(database *model.Database) error {
 	return nil
 }
 
-// Checks all the match results for yellow and red cards, and updates the team model accordingly.
-func CalculateTeamCards(database *model.Database, matchType string) error {
-	teams, err := database.GetAllTeams()
-	if err != nil {
-		return err
-	}
-	teamsMap := make(map[string]model.Team)
-	for _, team := range teams {
-		team.YellowCard = false
-		teamsMap[strconv.Itoa(team.Id)] = team
-	}
-
-	matches, err := database.GetMatchesByType(matchType)
-	if err != nil {
-		return err
-	}
-	for _, match := range matches {
-		if match.Status != "complete" {
-			continue
-		}
-		matchResult, err := database.GetMatchResultForMatch(match.Id)
-		if err != nil {
-			return err
-		}
-
-		// Mark the team as having a yellow card if they got either a yellow or red in a previous match.
-		for teamId, card := range matchResult.RedCards {
-			if team, ok := teamsMap[teamId]; ok && card != "" {
-				team.YellowCard = true
-				teamsMap[teamId] = team
-			}
-		}
-		for teamId, card := range matchResult.BlueCards {
-			if team, ok := teamsMap[teamId]; ok && card != "" {
-				team.YellowCard = true
-				teamsMap[teamId] = team
-			}
-		}
-	}
-
-	// Save the teams to the database.
-	for _, team := range teamsMap {
-		err = database.SaveTeam(&team)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 // Incrementally accounts for the given match result in the set of rankings that are being built.
 func addMatchResultToRankings(rankings map[int]*game.Ranking, teamId int, matchResult *model.MatchResult, isRed bool) {
 	ranking := rankings[teamId]
@@ -0,0 +1,216 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Functions for creating the match schedule of a round-robin playoff and calculating its standings.
+
+package tournament
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"sort"
+)
+
+// The round-robin matches are all stored as a single group of the semifinal round, numbered in the order they are
+// played. The finals are stored as in a single-elimination bracket.
+const (
+	roundRobinRound     = 2
+	roundRobinGroup     = 1
+	roundRobinRoundName = "RR"
+)
+
+// An alliance's record over the round-robin matches played so far.
+type RoundRobinStanding struct {
+	AllianceId    int
+	TeamIds       []int
+	MatchesPlayed int
+	Wins          int
+	Losses        int
+	Ties          int
+	TotalScore    int
+	AverageScore  float64
+}
+
+// Returns an error if a round-robin can't be run with the given number of alliances and matches per alliance.
+func checkRoundRobinSettings(numAlliances int, matchesPerAlliance int) error {
+	if numAlliances < 4 || numAlliances%2 != 0 {
+		return fmt.Errorf("Round-robin playoffs require an even number of alliances, and at least 4")
+	}
+	if matchesPerAlliance < 1 {
+		return fmt.Errorf("Round-robin playoffs require at least 1 match per alliance")
+	}
+	return nil
+}
+
+// Creates all of the round-robin matches up front, then the best-of-three finals between the top two alliances in the
+// standings once every round-robin match has been played. Returns the winner of the finals if known.
+func buildRoundRobinMatches(database *model.Database, numAlliances int, matchesPerAlliance int) ([]int, error) {
+	if err := checkRoundRobinSettings(numAlliances, matchesPerAlliance); err != nil {
+		return []int{}, err
+	}
+
+	matches, err := database.GetMatchesByElimRoundGroup(roundRobinRound, roundRobinGroup)
+	if err != nil {
+		return []int{}, err
+	}
+	existingMatches := make(map[int]bool)
+	allComplete := true
+	for _, match := range matches {
+		existingMatches[match.ElimInstance] = true
+		if match.Status != "complete" {
+			allComplete = false
+		}
+	}
+	for i, pairing := range roundRobinPairings(numAlliances, matchesPerAlliance) {
+		instance := i + 1
+		if existingMatches[instance] {
+			continue
+		}
+		redAlliance, err := getSelectedAlliance(database, pairing[0], true)
+		if err != nil {
+			return []int{}, err
+		}
+		blueAlliance, err := getSelectedAlliance(database, pairing[1], false)
+		if err != nil {
+			return []int{}, err
+		}
+		if len(redAlliance) < 3 || len(blueAlliance) < 3 {
+			return []int{}, fmt.Errorf("Alliances must consist of at least 3 teams")
+		}
+		err = database.CreateMatch(createMatch(roundRobinRoundName, roundRobinRound, roundRobinGroup, instance,
+			redAlliance, blueAlliance))
+		if err != nil {
+			return []int{}, err
+		}
+		allComplete = false
+	}
+	if !allComplete {
+		return []int{}, nil
+	}
+
+	standings, err := CalculateRoundRobinStandings(database)
+	if err != nil {
+		return []int{}, err
+	}
+	if len(standings) != numAlliances || len(standings) < 2 {
+		return []int{}, fmt.Errorf("Round-robin standings have %d alliances but %d were expected", len(standings),
+			numAlliances)
+	}
+	redAlliance, err := getSelectedAlliance(database, standings[0].AllianceId, true)
+	if err != nil {
+		return []int{}, err
+	}
+	blueAlliance, err := getSelectedAlliance(database, standings[1].AllianceId, false)
+	if err != nil {
+		return []int{}, err
+	}
+	winner, _, err := updateMatchSet(database, model.ElimRoundNames[1], 1, 1, 2, redAlliance, blueAlliance)
+	return winner, err
+}
+
+// Returns the red and blue alliance numbers of each round-robin match in the order they are played. The pairings are
+// generated using the circle method, so that every alliance plays once per round and meets every other alliance
+// before meeting any of them again; the colors are swapped each time the cycle repeats.
+func roundRobinPairings(numAlliances int, matchesPerAlliance int) [][2]int {
+	var pairings [][2]int
+	numRoundsPerCycle := numAlliances - 1
+	rotation := make([]int, numRoundsPerCycle)
+	for i := range rotation {
+		rotation[i] = i + 2
+	}
+	for round := 0; round < matchesPerAlliance; round++ {
+		// Alliance 1 stays in place while the others rotate around it.
+		positions := append([]int{1}, rotation...)
+		for i := 0; i < numAlliances/2; i++ {
+			red, blue := positions[i], positions[numAlliances-1-i]
+			swapColors := (round%numRoundsPerCycle+i)%2 == 1
+			if (round/numRoundsPerCycle)%2 == 1 {
+				swapColors = !swapColors
+			}
+			if swapColors {
+				red, blue = blue, red
+			}
+			pairings = append(pairings, [2]int{red, blue})
+		}
+		rotation = append([]int{rotation[numRoundsPerCycle-1]}, rotation[:numRoundsPerCycle-1]...)
+	}
+	return pairings
+}
+
+// Tallies the results of the completed round-robin matches for each alliance. The standings are ordered by record,
+// counting two points for a win and one for a tie, then by average score, with any remaining ties going to the
+// higher-seeded alliance.
+func CalculateRoundRobinStandings(database *model.Database) ([]RoundRobinStanding, error) {
+	alliances, err := database.GetAllAlliances()
+	if err != nil {
+		return []RoundRobinStanding{}, err
+	}
+	standings := make([]RoundRobinStanding, len(alliances))
+	allianceIndices := make(map[int]int)
+	for i, alliance := range alliances {
+		standings[i].AllianceId = alliance[0].AllianceId
+		for _, allianceTeam := range alliance {
+			standings[i].TeamIds = append(standings[i].TeamIds, allianceTeam.TeamId)
+			allianceIndices[allianceTeam.TeamId] = i
+		}
+	}
+
+	matches, err := database.GetMatchesByElimRoundGroup(roundRobinRound, roundRobinGroup)
+	if err != nil {
+		return []RoundRobinStanding{}, err
+	}
+	for _, match := range matches {
+		if match.Status != "complete" {
+			continue
+		}
+		matchResult, err := database.GetMatchResultForMatch(match.Id)
+		if err != nil {
+			return []RoundRobinStanding{}, err
+		}
+		if matchResult == nil {
+			continue
+		}
+		redIndex, ok := allianceIndices[match.Red1]
+		if !ok {
+			return []RoundRobinStanding{}, fmt.Errorf("Team %d is not part of an alliance", match.Red1)
+		}
+		blueIndex, ok := allianceIndices[match.Blue1]
+		if !ok {
+			return []RoundRobinStanding{}, fmt.Errorf("Team %d is not part of an alliance", match.Blue1)
+		}
+		standings[redIndex].addMatch(matchResult.RedScoreSummary().Score, match.Winner, "R")
+		standings[blueIndex].addMatch(matchResult.BlueScoreSummary().Score, match.Winner, "B")
+	}
+
+	for i := range standings {
+		if standings[i].MatchesPlayed > 0 {
+			standings[i].AverageScore = float64(standings[i].TotalScore) / float64(standings[i].MatchesPlayed)
+		}
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		if standings[i].recordPoints() != standings[j].recordPoints() {
+			return standings[i].recordPoints() > standings[j].recordPoints()
+		}
+		if standings[i].AverageScore != standings[j].AverageScore {
+			return standings[i].AverageScore > standings[j].AverageScore
+		}
+		return standings[i].AllianceId < standings[j].AllianceId
+	})
+	return standings, nil
+}
+
+func (standing *RoundRobinStanding) addMatch(score int, winner string, color string) {
+	standing.MatchesPlayed++
+	standing.TotalScore += score
+	if winner == color {
+		standing.Wins++
+	} else if winner == "T" {
+		standing.Ties++
+	} else {
+		standing.Losses++
+	}
+}
+
+func (standing *RoundRobinStanding) recordPoints() int {
+	return 2*standing.Wins + standing.Ties
+}
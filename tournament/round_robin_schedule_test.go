@@ -0,0 +1,196 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package tournament
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRoundRobinPairings(t *testing.T) {
+	pairings := roundRobinPairings(4, 6)
+	assert.Equal(t, [][2]int{{1, 4}, {3, 2}, {3, 1}, {4, 2}, {1, 2}, {4, 3}, {4, 1}, {2, 3}, {1, 3}, {2, 4}, {2, 1},
+		{3, 4}}, pairings)
+
+	// Check that each alliance plays the right number of matches and meets every other alliance before a rematch.
+	pairings = roundRobinPairings(6, 5)
+	numMatches := make(map[int]int)
+	opponents := make(map[[2]int]bool)
+	for _, pairing := range pairings {
+		numMatches[pairing[0]]++
+		numMatches[pairing[1]]++
+		assert.False(t, opponents[pairing])
+		opponents[pairing] = true
+		opponents[[2]int{pairing[1], pairing[0]}] = true
+	}
+	for alliance := 1; alliance <= 6; alliance++ {
+		assert.Equal(t, 5, numMatches[alliance])
+	}
+}
+
+func TestRoundRobinScheduleInitial(t *testing.T) {
+	database := setupRoundRobinTestDb(t, 2)
+
+	CreateTestAlliances(database, 4)
+	won, err := UpdateEliminationSchedule(database, time.Unix(0, 0))
+	assert.Nil(t, err)
+	assert.False(t, won)
+	matches, err := database.GetMatchesByType("elimination")
+	assert.Nil(t, err)
+	if assert.Equal(t, 4, len(matches)) {
+		assertMatch(t, matches[0], "RR-1", 1, 4)
+		assertMatch(t, matches[1], "RR-2", 3, 2)
+		assertMatch(t, matches[2], "RR-3", 3, 1)
+		assertMatch(t, matches[3], "RR-4", 4, 2)
+		assert.Equal(t, "sf1m1", matches[0].TbaCode())
+		assert.Equal(t, "sf1m4", matches[3].TbaCode())
+		assert.Equal(t, int64(600), matches[1].Time.Unix())
+	}
+
+	// Check that updating again doesn't create any more matches.
+	UpdateEliminationSchedule(database, time.Unix(0, 0))
+	matches, _ = database.GetMatchesByType("elimination")
+	assert.Equal(t, 4, len(matches))
+}
+
+func TestRoundRobinScheduleErrors(t *testing.T) {
+	database := setupRoundRobinTestDb(t, 2)
+
+	CreateTestAlliances(database, 5)
+	_, err := UpdateEliminationSchedule(database, time.Unix(0, 0))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Round-robin playoffs require an even number of alliances, and at least 4", err.Error())
+	}
+
+	database = setupRoundRobinTestDb(t, 0)
+	CreateTestAlliances(database, 4)
+	_, err = UpdateEliminationSchedule(database, time.Unix(0, 0))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Round-robin playoffs require at least 1 match per alliance", err.Error())
+	}
+}
+
+func TestRoundRobinScheduleProgression(t *testing.T) {
+	database := setupRoundRobinTestDb(t, 2)
+
+	CreateTestAlliances(database, 4)
+	UpdateEliminationSchedule(database, time.Unix(0, 0))
+	scoreRoundRobinMatch(database, "RR-1", 2, 1)
+	scoreRoundRobinMatch(database, "RR-2", 3, 3)
+	scoreRoundRobinMatch(database, "RR-3", 0, 4)
+	won, err := UpdateEliminationSchedule(database, time.Unix(0, 0))
+	assert.Nil(t, err)
+	assert.False(t, won)
+	matches, _ := database.GetMatchesByType("elimination")
+	assert.Equal(t, 4, len(matches))
+
+	standings, err := CalculateRoundRobinStandings(database)
+	assert.Nil(t, err)
+	if assert.Equal(t, 4, len(standings)) {
+		assertStanding(t, standings[0], 1, 2, 2, 0, 300)
+		assert.Equal(t, []int{1, 10, 100}, standings[0].TeamIds)
+		assertStanding(t, standings[1], 2, 1, 0, 1, 150)
+		assertStanding(t, standings[2], 3, 2, 0, 1, 150)
+		assertStanding(t, standings[3], 4, 1, 0, 0, 50)
+	}
+
+	// The finals are created between the top two once all the round-robin matches have been played, with the better
+	// record outranking the higher average score.
+	scoreRoundRobinMatch(database, "RR-4", 1, 5)
+	standings, _ = CalculateRoundRobinStandings(database)
+	if assert.Equal(t, 4, len(standings)) {
+		assertStanding(t, standings[0], 1, 2, 2, 0, 300)
+		assertStanding(t, standings[1], 2, 2, 1, 1, 400)
+	}
+	won, err = UpdateEliminationSchedule(database, time.Unix(0, 0))
+	assert.Nil(t, err)
+	assert.False(t, won)
+	matches, _ = database.GetMatchesByType("elimination")
+	if assert.Equal(t, 7, len(matches)) {
+		assertMatch(t, matches[4], "F-1", 1, 2)
+		assertMatch(t, matches[5], "F-2", 1, 2)
+		assertMatch(t, matches[6], "F-3", 1, 2)
+		assert.Equal(t, "f1m1", matches[4].TbaCode())
+	}
+
+	scoreMatch(database, "F-1", "B")
+	scoreMatch(database, "F-2", "B")
+	won, err = UpdateEliminationSchedule(database, time.Unix(0, 0))
+	assert.Nil(t, err)
+	assert.True(t, won)
+	matches, _ = database.GetMatchesByType("elimination")
+	assert.Equal(t, 6, len(matches))
+}
+
+func TestRoundRobinStandingsTiebreak(t *testing.T) {
+	database := setupRoundRobinTestDb(t, 1)
+
+	CreateTestAlliances(database, 4)
+	UpdateEliminationSchedule(database, time.Unix(0, 0))
+	scoreRoundRobinMatch(database, "RR-1", 2, 2)
+	scoreRoundRobinMatch(database, "RR-2", 2, 2)
+	standings, err := CalculateRoundRobinStandings(database)
+	assert.Nil(t, err)
+	if assert.Equal(t, 4, len(standings)) {
+		assert.Equal(t, 1, standings[0].AllianceId)
+		assert.Equal(t, 2, standings[1].AllianceId)
+		assert.Equal(t, 3, standings[2].AllianceId)
+		assert.Equal(t, 4, standings[3].AllianceId)
+		assert.Equal(t, 1, standings[0].Ties)
+	}
+
+	// Alliances with the same record should be separated by average score before seed.
+	database = setupRoundRobinTestDb(t, 1)
+	CreateTestAlliances(database, 4)
+	UpdateEliminationSchedule(database, time.Unix(0, 0))
+	scoreRoundRobinMatch(database, "RR-1", 2, 2)
+	scoreRoundRobinMatch(database, "RR-2", 3, 3)
+	standings, err = CalculateRoundRobinStandings(database)
+	assert.Nil(t, err)
+	if assert.Equal(t, 4, len(standings)) {
+		assert.Equal(t, 2, standings[0].AllianceId)
+		assert.Equal(t, 3, standings[1].AllianceId)
+		assert.Equal(t, 1, standings[2].AllianceId)
+		assert.Equal(t, 4, standings[3].AllianceId)
+	}
+}
+
+func assertStanding(t *testing.T, standing RoundRobinStanding, allianceId int, matchesPlayed int, wins int,
+	ties int, totalScore int) {
+	assert.Equal(t, allianceId, standing.AllianceId)
+	assert.Equal(t, matchesPlayed, standing.MatchesPlayed)
+	assert.Equal(t, wins, standing.Wins)
+	assert.Equal(t, ties, standing.Ties)
+	assert.Equal(t, matchesPlayed-wins-ties, standing.Losses)
+	assert.Equal(t, totalScore, standing.TotalScore)
+	assert.Equal(t, float64(totalScore)/float64(matchesPlayed), standing.AverageScore)
+}
+
+// Commits a result for the given match in which each alliance's score is made up only of takeoffs.
+func scoreRoundRobinMatch(database *model.Database, displayName string, redTakeoffs int, blueTakeoffs int) {
+	match, _ := database.GetMatchByName("elimination", displayName)
+	database.CreateMatchResult(&model.MatchResult{MatchId: match.Id, PlayNumber: 1, MatchType: match.Type,
+		RedScore: &game.Score{Takeoffs: redTakeoffs}, BlueScore: &game.Score{Takeoffs: blueTakeoffs}})
+	match.Status = "complete"
+	if redTakeoffs > blueTakeoffs {
+		match.Winner = "R"
+	} else if redTakeoffs < blueTakeoffs {
+		match.Winner = "B"
+	} else {
+		match.Winner = "T"
+	}
+	database.SaveMatch(match)
+}
+
+func setupRoundRobinTestDb(t *testing.T, matchesPerAlliance int) *model.Database {
+	database := setupTestDb(t)
+	eventSettings, _ := database.GetEventSettings()
+	eventSettings.ElimType = "roundRobin"
+	eventSettings.RoundRobinMatchesPerAlliance = matchesPerAlliance
+	database.SaveEventSettings(eventSettings)
+	return database
+}
@@ -22,14 +22,10 @@ const (
 	TeamsPerMatch = 6
 )
 
-type ScheduleBlock struct {
-	StartTime       time.Time
-	NumMatches      int
-	MatchSpacingSec int
-}
-
-// Creates a random schedule for the given parameters and returns it as a list of matches.
-func BuildRandomSchedule(teams []model.Team, scheduleBlocks []ScheduleBlock, matchType string) ([]model.Match, error) {
+// Creates a random schedule for the given parameters from the pre-computed schedule template in the schedules directory
+// and returns it as a list of matches.
+func BuildRandomSchedule(teams []model.Team, scheduleBlocks []model.ScheduleBlock,
+	matchType string) ([]model.Match, error) {
 	// Load the anonymized, pre-randomized match schedule for the given number of teams and matches per team.
 	numTeams := len(teams)
 	numMatches := countMatches(scheduleBlocks)
@@ -64,8 +60,17 @@ func BuildRandomSchedule(teams []model.Team, scheduleBlocks []ScheduleBlock, mat
 		}
 	}
 
+	return buildMatches(teams, anonSchedule, scheduleBlocks, matchType), nil
+}
+
+// Fills the given teams in a random order into the anonymized schedule, in which teams are numbered starting at 1 and
+// each team is followed by its surrogate flag, and assigns the match times from the schedule blocks.
+func buildMatches(teams []model.Team, anonSchedule [][12]int, scheduleBlocks []model.ScheduleBlock,
+	matchType string) []model.Match {
+	numMatches := len(anonSchedule)
+
 	// Generate a random permutation of the team ordering to fill into the pre-randomized schedule.
-	teamShuffle := rand.Perm(numTeams)
+	teamShuffle := rand.Perm(len(teams))
 	matches := make([]model.Match, numMatches)
 	for i, anonMatch := range anonSchedule {
 		matches[i].Type = matchType
@@ -84,20 +89,28 @@ func BuildRandomSchedule(teams []model.Team, scheduleBlocks []ScheduleBlock, mat
 		matches[i].Blue3IsSurrogate = (anonMatch[11] == 1)
 	}
 
-	// Fill in the match times.
+	UpdateMatchTimes(matches, scheduleBlocks)
+	return matches
+}
+
+// Assigns times from the schedule blocks to the given matches in order, leaving the teams in them untouched. Returns
+// an error if the blocks don't have room for all of the matches, in which case the extra matches aren't updated.
+func UpdateMatchTimes(matches []model.Match, scheduleBlocks []model.ScheduleBlock) error {
 	matchIndex := 0
 	for _, block := range scheduleBlocks {
-		for i := 0; i < block.NumMatches && matchIndex < numMatches; i++ {
+		for i := 0; i < block.NumMatches && matchIndex < len(matches); i++ {
 			matches[matchIndex].Time = block.StartTime.Add(time.Duration(i*block.MatchSpacingSec) * time.Second)
 			matchIndex++
 		}
 	}
-
-	return matches, nil
+	if matchIndex < len(matches) {
+		return fmt.Errorf("The schedule blocks only have room for %d of the %d matches", matchIndex, len(matches))
+	}
+	return nil
 }
 
 // Returns the total number of matches that can be run within the given schedule blocks.
-func countMatches(scheduleBlocks []ScheduleBlock) int {
+func countMatches(scheduleBlocks []model.ScheduleBlock) int {
 	numMatches := 0
 	for _, block := range scheduleBlocks {
 		numMatches += block.NumMatches
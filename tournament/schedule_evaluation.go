@@ -0,0 +1,211 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Metrics for judging the quality of a practice or qualification match schedule.
+
+package tournament
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"time"
+)
+
+// The index of the match, counting from zero, in which a team is expected to play as a surrogate.
+const surrogateMatchIndex = 2
+
+// Measures of how fair a schedule is to the teams in it.
+type ScheduleEvaluation struct {
+	NumTeams             int
+	NumMatches           int
+	MinMatchesPerTeam    int
+	MaxMatchesPerTeam    int
+	MinTurnaround        int
+	MinTurnaroundTeam    int
+	MinTurnaroundTime    time.Duration
+	BackToBackMatches    int
+	MaxPartnerCount      int
+	DuplicatePartners    int
+	MaxOpponentCount     int
+	DuplicateOpponents   int
+	MaxColorImbalance    int
+	MaxStationCount      int
+	NumSurrogates        int
+	MisplacedSurrogates  int
+	RepeatSurrogateTeams int
+	Warnings             []string
+}
+
+// Tallies of how a single team is treated by a schedule.
+type teamScheduleStats struct {
+	matchIndices     []int
+	matchTimes       []time.Time
+	redCount         int
+	stationCounts    [TeamsPerMatch]int
+	surrogateCount   int
+	surrogateIndices []int
+}
+
+// Evaluates the given schedule, which is assumed to be in the order in which the matches will be played. Surrogate
+// appearances count towards a team's turnaround, pairings and balance but not towards its number of matches.
+func EvaluateSchedule(matches []model.Match) *ScheduleEvaluation {
+	evaluation := &ScheduleEvaluation{NumMatches: len(matches), Warnings: []string{}}
+	if len(matches) == 0 {
+		return evaluation
+	}
+
+	teamStats := make(map[int]*teamScheduleStats)
+	var teamIds []int
+	partnerCounts := make(map[[2]int]int)
+	opponentCounts := make(map[[2]int]int)
+	for i, match := range matches {
+		teams := [TeamsPerMatch]int{match.Red1, match.Red2, match.Red3, match.Blue1, match.Blue2, match.Blue3}
+		surrogates := [TeamsPerMatch]bool{match.Red1IsSurrogate, match.Red2IsSurrogate, match.Red3IsSurrogate,
+			match.Blue1IsSurrogate, match.Blue2IsSurrogate, match.Blue3IsSurrogate}
+		for j, team := range teams {
+			if team == 0 {
+				continue
+			}
+			stats, ok := teamStats[team]
+			if !ok {
+				stats = new(teamScheduleStats)
+				teamStats[team] = stats
+				teamIds = append(teamIds, team)
+			}
+			if surrogates[j] {
+				stats.surrogateCount++
+				stats.surrogateIndices = append(stats.surrogateIndices, len(stats.matchIndices))
+			}
+			stats.matchIndices = append(stats.matchIndices, i)
+			stats.matchTimes = append(stats.matchTimes, match.Time)
+			stats.stationCounts[j]++
+			if isRed(j) {
+				stats.redCount++
+			}
+
+			for k := j + 1; k < TeamsPerMatch; k++ {
+				if teams[k] == 0 {
+					continue
+				}
+				pair := orderedPair(team, teams[k])
+				if isRed(j) == isRed(k) {
+					partnerCounts[pair]++
+				} else {
+					opponentCounts[pair]++
+				}
+			}
+		}
+	}
+
+	evaluation.NumTeams = len(teamIds)
+	evaluation.MinMatchesPerTeam = -1
+	haveTurnaround := false
+	for _, team := range teamIds {
+		stats := teamStats[team]
+		numMatches := len(stats.matchIndices) - stats.surrogateCount
+		if evaluation.MinMatchesPerTeam == -1 || numMatches < evaluation.MinMatchesPerTeam {
+			evaluation.MinMatchesPerTeam = numMatches
+		}
+		if numMatches > evaluation.MaxMatchesPerTeam {
+			evaluation.MaxMatchesPerTeam = numMatches
+		}
+
+		for i := 1; i < len(stats.matchIndices); i++ {
+			// The turnaround is the number of matches a team gets to sit out between two of its own.
+			turnaround := stats.matchIndices[i] - stats.matchIndices[i-1] - 1
+			if turnaround == 0 {
+				evaluation.BackToBackMatches++
+			}
+			if !haveTurnaround || turnaround < evaluation.MinTurnaround {
+				evaluation.MinTurnaround = turnaround
+				evaluation.MinTurnaroundTeam = team
+			}
+			turnaroundTime := stats.matchTimes[i].Sub(stats.matchTimes[i-1])
+			if !haveTurnaround || turnaroundTime < evaluation.MinTurnaroundTime {
+				evaluation.MinTurnaroundTime = turnaroundTime
+			}
+			haveTurnaround = true
+		}
+
+		colorImbalance := len(stats.matchIndices) - 2*stats.redCount
+		if colorImbalance < 0 {
+			colorImbalance = -colorImbalance
+		}
+		if colorImbalance > evaluation.MaxColorImbalance {
+			evaluation.MaxColorImbalance = colorImbalance
+		}
+		for _, count := range stats.stationCounts {
+			if count > evaluation.MaxStationCount {
+				evaluation.MaxStationCount = count
+			}
+		}
+
+		evaluation.NumSurrogates += stats.surrogateCount
+		if stats.surrogateCount > 1 {
+			evaluation.RepeatSurrogateTeams++
+		}
+		for _, index := range stats.surrogateIndices {
+			// A surrogate match should be the third, so that it isn't the team's first or last impression.
+			if index != minInt(surrogateMatchIndex, len(stats.matchIndices)-1) {
+				evaluation.MisplacedSurrogates++
+			}
+		}
+	}
+	evaluation.MaxPartnerCount, evaluation.DuplicatePartners = countDuplicatePairs(partnerCounts)
+	evaluation.MaxOpponentCount, evaluation.DuplicateOpponents = countDuplicatePairs(opponentCounts)
+
+	evaluation.addWarnings()
+	return evaluation
+}
+
+// Flags the metrics that suggest that the schedule should be regenerated.
+func (evaluation *ScheduleEvaluation) addWarnings() {
+	if evaluation.MinMatchesPerTeam != evaluation.MaxMatchesPerTeam {
+		evaluation.addWarning("Teams play between %d and %d matches.", evaluation.MinMatchesPerTeam,
+			evaluation.MaxMatchesPerTeam)
+	}
+	if evaluation.BackToBackMatches > 0 {
+		evaluation.addWarning("There are %d instances of a team playing back-to-back matches.",
+			evaluation.BackToBackMatches)
+	}
+	if evaluation.MaxPartnerCount > 2 {
+		evaluation.addWarning("At least one pair of teams is on the same alliance %d times.",
+			evaluation.MaxPartnerCount)
+	}
+	if evaluation.MaxColorImbalance > 2 {
+		evaluation.addWarning("At least one team plays %d more matches on one alliance color than the other.",
+			evaluation.MaxColorImbalance)
+	}
+	if evaluation.MisplacedSurrogates > 0 {
+		evaluation.addWarning("%d surrogate appearances aren't in the team's third match.",
+			evaluation.MisplacedSurrogates)
+	}
+	if evaluation.RepeatSurrogateTeams > 0 {
+		evaluation.addWarning("%d teams play as a surrogate more than once.", evaluation.RepeatSurrogateTeams)
+	}
+}
+
+func (evaluation *ScheduleEvaluation) addWarning(format string, args ...interface{}) {
+	evaluation.Warnings = append(evaluation.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Returns the most times any pair of teams was matched up and the number of pairs that were matched up more than once.
+func countDuplicatePairs(pairCounts map[[2]int]int) (int, int) {
+	maxCount, duplicates := 0, 0
+	for _, count := range pairCounts {
+		if count > maxCount {
+			maxCount = count
+		}
+		if count > 1 {
+			duplicates++
+		}
+	}
+	return maxCount, duplicates
+}
+
+func orderedPair(team1, team2 int) [2]int {
+	if team1 > team2 {
+		return [2]int{team2, team1}
+	}
+	return [2]int{team1, team2}
+}
@@ -0,0 +1,73 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package tournament
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestEvaluateScheduleEmpty(t *testing.T) {
+	evaluation := EvaluateSchedule([]model.Match{})
+	assert.Equal(t, 0, evaluation.NumTeams)
+	assert.Empty(t, evaluation.Warnings)
+}
+
+func TestEvaluateSchedule(t *testing.T) {
+	start := time.Unix(0, 0).UTC()
+	matches := []model.Match{
+		{Time: start, Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5, Blue3: 6},
+		{Time: start.Add(6 * time.Minute), Red1: 7, Red2: 8, Red3: 9, Blue1: 10, Blue2: 11, Blue3: 12},
+		{Time: start.Add(12 * time.Minute), Red1: 1, Red2: 2, Red3: 7, Blue1: 4, Blue2: 10, Blue3: 12,
+			Blue3IsSurrogate: true},
+		{Time: start.Add(18 * time.Minute), Red1: 1, Red2: 2, Red3: 8, Blue1: 3, Blue2: 5, Blue3: 6},
+		{Time: start.Add(30 * time.Minute), Red1: 9, Red2: 11, Red3: 3, Blue1: 12, Blue2: 10, Blue3: 7,
+			Blue3IsSurrogate: true},
+	}
+	evaluation := EvaluateSchedule(matches)
+	assert.Equal(t, 12, evaluation.NumTeams)
+	assert.Equal(t, 5, evaluation.NumMatches)
+	assert.Equal(t, 2, evaluation.MinMatchesPerTeam)
+	assert.Equal(t, 3, evaluation.MaxMatchesPerTeam)
+	assert.Equal(t, 0, evaluation.MinTurnaround)
+	assert.Equal(t, 1, evaluation.MinTurnaroundTeam)
+	assert.Equal(t, 6*time.Minute, evaluation.MinTurnaroundTime)
+	assert.Equal(t, 6, evaluation.BackToBackMatches)
+	assert.Equal(t, 3, evaluation.MaxPartnerCount)
+	assert.Equal(t, 3, evaluation.DuplicatePartners)
+	assert.Equal(t, 2, evaluation.MaxOpponentCount)
+	assert.Equal(t, 3, evaluation.MaxColorImbalance)
+	assert.Equal(t, 3, evaluation.MaxStationCount)
+	assert.Equal(t, 2, evaluation.NumSurrogates)
+	assert.Equal(t, 1, evaluation.MisplacedSurrogates)
+	assert.Equal(t, 0, evaluation.RepeatSurrogateTeams)
+	assert.Equal(t, []string{"Teams play between 2 and 3 matches.",
+		"There are 6 instances of a team playing back-to-back matches.",
+		"At least one pair of teams is on the same alliance 3 times.",
+		"At least one team plays 3 more matches on one alliance color than the other.",
+		"1 surrogate appearances aren't in the team's third match."}, evaluation.Warnings)
+}
+
+func TestEvaluateGeneratedSchedule(t *testing.T) {
+	rand.Seed(0)
+
+	teams := make([]model.Team, 24)
+	for i := range teams {
+		teams[i].Id = i + 101
+	}
+	scheduleBlocks := []model.ScheduleBlock{{0, "", "", time.Unix(0, 0).UTC(), 40, 360}}
+	matches, err := BuildGeneratedSchedule(teams, scheduleBlocks, "qualification")
+	assert.Nil(t, err)
+	evaluation := EvaluateSchedule(matches)
+	assert.Equal(t, 24, evaluation.NumTeams)
+	assert.Equal(t, 10, evaluation.MinMatchesPerTeam)
+	assert.Equal(t, 10, evaluation.MaxMatchesPerTeam)
+	assert.True(t, evaluation.MinTurnaround >= 1)
+	assert.True(t, evaluation.MinTurnaroundTime >= 12*time.Minute)
+	assert.Equal(t, 0, evaluation.NumSurrogates)
+	assert.Empty(t, evaluation.Warnings)
+}
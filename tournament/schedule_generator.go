@@ -0,0 +1,297 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Generation of match schedules for any number of teams using simulated annealing.
+
+package tournament
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Relative weights of the qualities of a schedule that the generator trades off against each other.
+const (
+	partnerWeight    = 3
+	opponentWeight   = 1
+	separationWeight = 15
+	balanceWeight    = 2
+)
+
+// Bounds on the number of attempted swaps while generating a schedule, which is otherwise scaled to its size.
+const (
+	minScheduleIterations = 50000
+	maxScheduleIterations = 1000000
+)
+
+// Creates a random schedule for the given parameters by generating it from scratch, so that it works for any number
+// of teams and matches per team, and returns it as a list of matches.
+func BuildGeneratedSchedule(teams []model.Team, scheduleBlocks []model.ScheduleBlock,
+	matchType string) ([]model.Match, error) {
+	numTeams := len(teams)
+	if numTeams < TeamsPerMatch {
+		return nil, fmt.Errorf("At least %d teams are needed to generate a schedule", TeamsPerMatch)
+	}
+	matchesPerTeam := int(float32(countMatches(scheduleBlocks)*TeamsPerMatch) / float32(numTeams))
+	if matchesPerTeam < 1 {
+		return nil, fmt.Errorf("The schedule blocks don't have room for any matches")
+	}
+
+	anonSchedule, err := generateAnonSchedule(numTeams, matchesPerTeam)
+	if err != nil {
+		return nil, err
+	}
+	return buildMatches(teams, anonSchedule, scheduleBlocks, matchType), nil
+}
+
+// The state of a schedule being optimized, with running tallies of everything that goes into its cost.
+type scheduleGenerator struct {
+	numTeams       int
+	matches        [][TeamsPerMatch]int
+	minSeparation  int
+	partnerCounts  [][]int
+	opponentCounts [][]int
+	teamMatches    [][]int
+	redCounts      []int
+	cost           int
+}
+
+// Generates an anonymized schedule in the same format as the schedule templates, where every team plays the given
+// number of matches. If the matches can't be filled evenly, some teams play an extra match as a surrogate.
+func generateAnonSchedule(numTeams int, matchesPerTeam int) ([][12]int, error) {
+	numMatches := int(math.Ceil(float64(numTeams) * float64(matchesPerTeam) / TeamsPerMatch))
+	numSurrogates := numMatches*TeamsPerMatch - numTeams*matchesPerTeam
+
+	var generator *scheduleGenerator
+	for attempt := 0; generator == nil; attempt++ {
+		if attempt == 10 {
+			return nil, fmt.Errorf("Failed to generate a schedule for %d teams and %d matches", numTeams,
+				matchesPerTeam)
+		}
+		generator = newScheduleGenerator(numTeams, matchesPerTeam, numMatches, numSurrogates)
+	}
+	generator.anneal()
+
+	// Mark each surrogate team's third match (or last, if it plays fewer) as the one it plays as a surrogate.
+	anonSchedule := make([][12]int, numMatches)
+	for i, match := range generator.matches {
+		for j, team := range match {
+			anonSchedule[i][2*j] = team + 1
+		}
+	}
+	for team := 0; team < numSurrogates; team++ {
+		surrogateMatch := generator.teamMatches[team][minInt(surrogateMatchIndex, matchesPerTeam)]
+		for j, matchTeam := range generator.matches[surrogateMatch] {
+			if matchTeam == team {
+				anonSchedule[surrogateMatch][2*j+1] = 1
+			}
+		}
+	}
+	return anonSchedule, nil
+}
+
+// Returns a generator filled with a random initial schedule in which teams are spread out round by round, or nil if
+// a team couldn't be placed without playing twice in the same match.
+func newScheduleGenerator(numTeams, matchesPerTeam, numMatches, numSurrogates int) *scheduleGenerator {
+	generator := &scheduleGenerator{numTeams: numTeams, matches: make([][TeamsPerMatch]int, numMatches)}
+	generator.minSeparation = int(0.6*float64(numMatches)/float64(matchesPerTeam) + 0.5)
+	if generator.minSeparation < 1 {
+		generator.minSeparation = 1
+	}
+	for i := range generator.matches {
+		for j := range generator.matches[i] {
+			generator.matches[i][j] = -1
+		}
+	}
+
+	// Build a queue of the teams to place, shuffled within each round. The surrogate teams play an extra round.
+	var queue []int
+	for round := 0; round <= matchesPerTeam; round++ {
+		for _, team := range rand.Perm(numTeams) {
+			if round < matchesPerTeam || team < numSurrogates {
+				queue = append(queue, team)
+			}
+		}
+	}
+
+	for i := range generator.matches {
+		for j := range generator.matches[i] {
+			// Take the first team in the queue that isn't already in this match.
+			placed := false
+			for k, team := range queue {
+				if !generator.inMatch(i, j, team) {
+					generator.matches[i][j] = team
+					queue = append(queue[:k], queue[k+1:]...)
+					placed = true
+					break
+				}
+			}
+			if !placed {
+				return nil
+			}
+		}
+	}
+
+	generator.partnerCounts = makeCountMatrix(numTeams)
+	generator.opponentCounts = makeCountMatrix(numTeams)
+	generator.teamMatches = make([][]int, numTeams)
+	generator.redCounts = make([]int, numTeams)
+	for i, match := range generator.matches {
+		for _, team := range match {
+			generator.teamMatches[team] = append(generator.teamMatches[team], i)
+		}
+		generator.addMatch(i, 1)
+	}
+	for team := 0; team < numTeams; team++ {
+		generator.cost += generator.teamCost(team)
+	}
+	return generator
+}
+
+// Improves the schedule by repeatedly swapping pairs of teams, accepting swaps that make it worse with a probability
+// that decreases as the schedule cools.
+func (generator *scheduleGenerator) anneal() {
+	numSlots := len(generator.matches) * TeamsPerMatch
+	iterations := numSlots * 500
+	if iterations < minScheduleIterations {
+		iterations = minScheduleIterations
+	} else if iterations > maxScheduleIterations {
+		iterations = maxScheduleIterations
+	}
+	startTemperature, endTemperature := 10.0, 0.05
+	cooling := math.Pow(endTemperature/startTemperature, 1/float64(iterations))
+	temperature := startTemperature
+	for iteration := 0; iteration < iterations; iteration++ {
+		slot1, slot2 := rand.Intn(numSlots), rand.Intn(numSlots)
+		match1, position1 := slot1/TeamsPerMatch, slot1%TeamsPerMatch
+		match2, position2 := slot2/TeamsPerMatch, slot2%TeamsPerMatch
+		if !generator.canSwap(match1, position1, match2, position2) {
+			continue
+		}
+		oldCost := generator.cost
+		generator.swap(match1, position1, match2, position2)
+		delta := generator.cost - oldCost
+		if delta > 0 && rand.Float64() >= math.Exp(-float64(delta)/temperature) {
+			generator.swap(match1, position1, match2, position2)
+		}
+		temperature *= cooling
+	}
+}
+
+// Returns true if swapping the teams in the given positions changes the schedule without putting a team in the same
+// match twice.
+func (generator *scheduleGenerator) canSwap(match1, position1, match2, position2 int) bool {
+	if generator.matches[match1][position1] == generator.matches[match2][position2] {
+		return false
+	}
+	if match1 == match2 {
+		return isRed(position1) != isRed(position2)
+	}
+	return !generator.inMatch(match2, position2, generator.matches[match1][position1]) &&
+		!generator.inMatch(match1, position1, generator.matches[match2][position2])
+}
+
+// Swaps the teams in the given positions, keeping the running cost up to date.
+func (generator *scheduleGenerator) swap(match1, position1, match2, position2 int) {
+	team1, team2 := generator.matches[match1][position1], generator.matches[match2][position2]
+	generator.cost -= generator.teamCost(team1) + generator.teamCost(team2)
+	generator.addMatch(match1, -1)
+	if match2 != match1 {
+		generator.addMatch(match2, -1)
+	}
+
+	generator.matches[match1][position1], generator.matches[match2][position2] = team2, team1
+	if match1 != match2 {
+		generator.moveTeam(team1, match1, match2)
+		generator.moveTeam(team2, match2, match1)
+	}
+
+	generator.addMatch(match1, 1)
+	if match2 != match1 {
+		generator.addMatch(match2, 1)
+	}
+	generator.cost += generator.teamCost(team1) + generator.teamCost(team2)
+}
+
+// Adds (or removes, if the sign is negative) the pairings and alliance colors of the given match to the tallies.
+func (generator *scheduleGenerator) addMatch(matchIndex int, sign int) {
+	match := generator.matches[matchIndex]
+	for i := 0; i < TeamsPerMatch; i++ {
+		if isRed(i) {
+			generator.redCounts[match[i]] += sign
+		}
+		for j := i + 1; j < TeamsPerMatch; j++ {
+			counts, weight := generator.opponentCounts, opponentWeight
+			if isRed(i) == isRed(j) {
+				counts, weight = generator.partnerCounts, partnerWeight
+			}
+			team1, team2 := match[i], match[j]
+			oldCount := counts[team1][team2]
+			counts[team1][team2] += sign
+			counts[team2][team1] += sign
+			generator.cost += weight * (square(counts[team1][team2]) - square(oldCount))
+		}
+	}
+}
+
+// Updates the sorted list of matches that the team plays in after it is moved from one match to another.
+func (generator *scheduleGenerator) moveTeam(team, fromMatch, toMatch int) {
+	matches := generator.teamMatches[team]
+	for i, match := range matches {
+		if match == fromMatch {
+			matches[i] = toMatch
+			break
+		}
+	}
+	sort.Ints(matches)
+}
+
+// Returns the parts of the cost that depend on a single team: how close together its matches are and how unevenly it
+// is split between the red and blue alliances.
+func (generator *scheduleGenerator) teamCost(team int) int {
+	cost := 0
+	matches := generator.teamMatches[team]
+	for i := 1; i < len(matches); i++ {
+		if gap := matches[i] - matches[i-1]; gap < generator.minSeparation {
+			cost += separationWeight * square(generator.minSeparation-gap)
+		}
+	}
+	cost += balanceWeight * square(2*generator.redCounts[team]-len(matches))
+	return cost
+}
+
+// Returns true if the given team is in the match in any position other than the given one.
+func (generator *scheduleGenerator) inMatch(matchIndex, excludedPosition, team int) bool {
+	for i, matchTeam := range generator.matches[matchIndex] {
+		if i != excludedPosition && matchTeam == team {
+			return true
+		}
+	}
+	return false
+}
+
+func isRed(position int) bool {
+	return position < TeamsPerMatch/2
+}
+
+func makeCountMatrix(size int) [][]int {
+	matrix := make([][]int, size)
+	for i := range matrix {
+		matrix[i] = make([]int, size)
+	}
+	return matrix
+}
+
+func square(value int) int {
+	return value * value
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
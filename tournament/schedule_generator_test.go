@@ -0,0 +1,85 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package tournament
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestGeneratedScheduleErrors(t *testing.T) {
+	teams := make([]model.Team, 5)
+	scheduleBlocks := []model.ScheduleBlock{{0, "", "", time.Unix(0, 0).UTC(), 10, 60}}
+	_, err := BuildGeneratedSchedule(teams, scheduleBlocks, "test")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "At least 6 teams are needed to generate a schedule", err.Error())
+	}
+
+	teams = make([]model.Team, 18)
+	scheduleBlocks = []model.ScheduleBlock{{0, "", "", time.Unix(0, 0).UTC(), 2, 60}}
+	_, err = BuildGeneratedSchedule(teams, scheduleBlocks, "test")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "The schedule blocks don't have room for any matches", err.Error())
+	}
+}
+
+func TestGeneratedScheduleQuality(t *testing.T) {
+	rand.Seed(0)
+
+	for _, numTeams := range []int{17, 18, 40, 101} {
+		teams := make([]model.Team, numTeams)
+		for i := 0; i < numTeams; i++ {
+			teams[i].Id = i + 101
+		}
+		scheduleBlocks := []model.ScheduleBlock{{0, "", "", time.Unix(0, 0).UTC(), numTeams * 2, 360}}
+		matches, err := BuildGeneratedSchedule(teams, scheduleBlocks, "qualification")
+		assert.Nil(t, err)
+		assert.Equal(t, time.Unix(0, 0).UTC(), matches[0].Time)
+		assert.Equal(t, time.Unix(360, 0).UTC(), matches[1].Time)
+
+		// Check that every team plays the same number of matches, not counting surrogate appearances.
+		matchCounts := make(map[int]int)
+		surrogateCounts := make(map[int]int)
+		lastMatch := make(map[int]int)
+		partnerCounts := make(map[[2]int]int)
+		for i, match := range matches {
+			matchTeams := []int{match.Red1, match.Red2, match.Red3, match.Blue1, match.Blue2, match.Blue3}
+			surrogates := []bool{match.Red1IsSurrogate, match.Red2IsSurrogate, match.Red3IsSurrogate,
+				match.Blue1IsSurrogate, match.Blue2IsSurrogate, match.Blue3IsSurrogate}
+			for j, team := range matchTeams {
+				if surrogates[j] {
+					surrogateCounts[team]++
+				} else {
+					matchCounts[team]++
+				}
+				if last, ok := lastMatch[team]; ok {
+					assert.True(t, i-last > 1, "Team %d plays back-to-back in match %d", team, i+1)
+				}
+				lastMatch[team] = i
+				for k := j + 1; k < len(matchTeams); k++ {
+					assert.NotEqual(t, team, matchTeams[k])
+					if j/3 == k/3 {
+						partnerCounts[[2]int{team, matchTeams[k]}]++
+					}
+				}
+			}
+		}
+		assert.Equal(t, numTeams, len(matchCounts))
+		for _, count := range matchCounts {
+			assert.Equal(t, 12, count)
+		}
+		for _, count := range surrogateCounts {
+			assert.Equal(t, 1, count)
+		}
+		assert.Equal(t, len(matches)*TeamsPerMatch-12*numTeams, len(surrogateCounts))
+		// Teams shouldn't be partnered more than once beyond what the number of teams forces.
+		maxPartnerCount := (2*12+numTeams-2)/(numTeams-1) + 1
+		for pair, count := range partnerCounts {
+			assert.True(t, count <= maxPartnerCount, "Teams %v are partnered %d times", pair, count)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Functions for predicting when unplayed matches will actually start, based on how the event has been running.
+
+package tournament
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"time"
+)
+
+const (
+	// Number of the most recent match-to-match cycles to average when measuring the cycle time.
+	cycleTimeSampleSize = 5
+
+	// A gap between two scheduled matches this many times longer than the one before it is taken to be a break
+	// between schedule blocks, which matches that are running ahead of schedule can't eat into.
+	blockBreakFactor = 2
+)
+
+// The predicted timing of the matches that have yet to be played.
+type SchedulePrediction struct {
+	CycleTimeSec int
+	SlipSec      int
+	Matches      []PredictedMatch
+}
+
+// An unplayed match along with the time it is expected to start.
+type PredictedMatch struct {
+	model.Match
+	PredictedTime time.Time
+}
+
+// Predicts the start times of the matches after the last one to have been started, given in schedule order. Matches
+// are assumed to follow each other at the recently measured cycle time, or at their scheduled spacing if no cycles
+// have been measured yet, except that the first match of a schedule block won't start earlier than scheduled.
+func PredictSchedule(matches []model.Match, scheduleBlocks []model.ScheduleBlock,
+	currentTime time.Time) *SchedulePrediction {
+	blockStarts := findBlockStarts(matches, scheduleBlocks)
+	prediction := &SchedulePrediction{CycleTimeSec: measureCycleTimeSec(matches, blockStarts),
+		Matches: []PredictedMatch{}}
+	cycleTime := time.Duration(prediction.CycleTimeSec) * time.Second
+
+	lastStartedIndex := -1
+	for i, match := range matches {
+		if !match.StartedAt.IsZero() || match.Status == "complete" {
+			lastStartedIndex = i
+		}
+	}
+	var previousTime time.Time
+	if lastStartedIndex >= 0 {
+		previousTime = matches[lastStartedIndex].StartedAt
+		if previousTime.IsZero() {
+			previousTime = matches[lastStartedIndex].Time
+		}
+	}
+
+	for i := lastStartedIndex + 1; i < len(matches); i++ {
+		match := matches[i]
+		predictedTime := match.Time
+		if i > 0 {
+			step := cycleTime
+			if blockStarts[i] {
+				if step == 0 && i > 1 {
+					step = matches[i-1].Time.Sub(matches[i-2].Time)
+				}
+				predictedTime = previousTime.Add(step)
+				if predictedTime.Before(match.Time) {
+					predictedTime = match.Time
+				}
+			} else {
+				if step == 0 {
+					step = match.Time.Sub(matches[i-1].Time)
+				}
+				predictedTime = previousTime.Add(step)
+			}
+		}
+		if i == lastStartedIndex+1 {
+			// The next match can't start any earlier than right now.
+			if predictedTime.Before(currentTime) {
+				predictedTime = currentTime
+			}
+			prediction.SlipSec = int(predictedTime.Sub(match.Time).Seconds())
+		}
+		prediction.Matches = append(prediction.Matches, PredictedMatch{match, predictedTime})
+		previousTime = predictedTime
+	}
+
+	return prediction
+}
+
+// Returns a description of how far ahead of or behind schedule the next match is expected to start.
+func (prediction *SchedulePrediction) SlipDescription() string {
+	if len(prediction.Matches) == 0 {
+		return "All matches have been played"
+	}
+	slipMin := (prediction.SlipSec + 30) / 60
+	if prediction.SlipSec < 0 {
+		slipMin = (prediction.SlipSec - 30) / 60
+	}
+	switch {
+	case slipMin == 0:
+		return "On schedule"
+	case slipMin == 1:
+		return "Behind by 1 minute"
+	case slipMin > 1:
+		return fmt.Sprintf("Behind by %d minutes", slipMin)
+	case slipMin == -1:
+		return "Ahead by 1 minute"
+	default:
+		return fmt.Sprintf("Ahead by %d minutes", -slipMin)
+	}
+}
+
+// Returns the average time between the starts of the most recent consecutive matches within the same block, or zero
+// if there aren't any.
+func measureCycleTimeSec(matches []model.Match, blockStarts []bool) int {
+	var cycleTimes []time.Duration
+	for i := 1; i < len(matches); i++ {
+		if matches[i].StartedAt.IsZero() || matches[i-1].StartedAt.IsZero() || blockStarts[i] {
+			continue
+		}
+		if cycleTime := matches[i].StartedAt.Sub(matches[i-1].StartedAt); cycleTime > 0 {
+			cycleTimes = append(cycleTimes, cycleTime)
+		}
+	}
+	if len(cycleTimes) == 0 {
+		return 0
+	}
+	if len(cycleTimes) > cycleTimeSampleSize {
+		cycleTimes = cycleTimes[len(cycleTimes)-cycleTimeSampleSize:]
+	}
+	var total time.Duration
+	for _, cycleTime := range cycleTimes {
+		total += cycleTime
+	}
+	return int((total / time.Duration(len(cycleTimes))).Seconds())
+}
+
+// Returns whether each match is the first of a new schedule block. The given schedule blocks are used to tell where
+// each one starts, falling back to looking for breaks in the schedule for any match that none of them covers.
+func findBlockStarts(matches []model.Match, scheduleBlocks []model.ScheduleBlock) []bool {
+	blockStarts := make([]bool, len(matches))
+	previousBlockIndex := -1
+	for i, match := range matches {
+		blockIndex := -1
+		for j, block := range scheduleBlocks {
+			if !match.Time.Before(block.StartTime) && match.Time.Before(block.EndTime()) {
+				blockIndex = j
+				break
+			}
+		}
+		if blockIndex >= 0 {
+			blockStarts[i] = i > 0 && blockIndex != previousBlockIndex
+		} else {
+			blockStarts[i] = isBlockStart(matches, i)
+		}
+		previousBlockIndex = blockIndex
+	}
+	return blockStarts
+}
+
+// Returns true if there is a break in the schedule right before the match at the given index.
+func isBlockStart(matches []model.Match, index int) bool {
+	if index < 2 {
+		return false
+	}
+	return matches[index].Time.Sub(matches[index-1].Time) >
+		blockBreakFactor*matches[index-1].Time.Sub(matches[index-2].Time)
+}
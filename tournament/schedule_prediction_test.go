@@ -0,0 +1,120 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package tournament
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPredictScheduleNotStarted(t *testing.T) {
+	matches := buildPredictionMatches()
+
+	prediction := PredictSchedule(matches, nil, matches[0].Time.Add(-time.Hour))
+	assert.Equal(t, 0, prediction.CycleTimeSec)
+	assert.Equal(t, 0, prediction.SlipSec)
+	assert.Equal(t, "On schedule", prediction.SlipDescription())
+	if assert.Equal(t, 8, len(prediction.Matches)) {
+		for i, match := range prediction.Matches {
+			assert.Equal(t, matches[i].Time, match.PredictedTime)
+		}
+	}
+
+	// Check that the schedule slips if the first match is late to start.
+	prediction = PredictSchedule(matches, nil, matches[0].Time.Add(10*time.Minute))
+	assert.Equal(t, 600, prediction.SlipSec)
+	assert.Equal(t, "Behind by 10 minutes", prediction.SlipDescription())
+	assert.Equal(t, matches[3].Time.Add(10*time.Minute), prediction.Matches[3].PredictedTime)
+	assert.Equal(t, matches[4].Time, prediction.Matches[4].PredictedTime)
+}
+
+func TestPredictScheduleBehind(t *testing.T) {
+	matches := buildPredictionMatches()
+	for i := 0; i < 3; i++ {
+		matches[i].StartedAt = matches[i].Time.Add(time.Duration(i) * 2 * time.Minute)
+		matches[i].Status = "complete"
+	}
+
+	// Cycles are 8 minutes instead of the scheduled 6, and the break absorbs most of the slip.
+	prediction := PredictSchedule(matches, nil, matches[2].StartedAt.Add(time.Minute))
+	assert.Equal(t, 480, prediction.CycleTimeSec)
+	assert.Equal(t, 360, prediction.SlipSec)
+	assert.Equal(t, "Behind by 6 minutes", prediction.SlipDescription())
+	if assert.Equal(t, 5, len(prediction.Matches)) {
+		assert.Equal(t, matches[3].Id, prediction.Matches[0].Id)
+		assert.Equal(t, matches[3].Time.Add(6*time.Minute), prediction.Matches[0].PredictedTime)
+		assert.Equal(t, matches[4].Time, prediction.Matches[1].PredictedTime)
+		assert.Equal(t, matches[4].Time.Add(8*time.Minute), prediction.Matches[2].PredictedTime)
+		assert.Equal(t, matches[4].Time.Add(24*time.Minute), prediction.Matches[4].PredictedTime)
+	}
+
+	// Check that a match running long pushes back the next one.
+	prediction = PredictSchedule(matches, nil, matches[2].StartedAt.Add(20*time.Minute))
+	assert.Equal(t, 18*60, prediction.SlipSec)
+	assert.Equal(t, matches[2].StartedAt.Add(20*time.Minute), prediction.Matches[0].PredictedTime)
+}
+
+func TestPredictScheduleAhead(t *testing.T) {
+	matches := buildPredictionMatches()
+	for i := 0; i < 3; i++ {
+		matches[i].StartedAt = matches[i].Time.Add(-time.Duration(i) * time.Minute)
+	}
+	matches[0].Status = "complete"
+	matches[1].Status = "complete"
+
+	prediction := PredictSchedule(matches, nil, matches[2].StartedAt.Add(time.Minute))
+	assert.Equal(t, 300, prediction.CycleTimeSec)
+	assert.Equal(t, -180, prediction.SlipSec)
+	assert.Equal(t, "Ahead by 3 minutes", prediction.SlipDescription())
+	assert.Equal(t, matches[4].Time, prediction.Matches[1].PredictedTime)
+
+	for i := range matches {
+		matches[i].Status = "complete"
+	}
+	prediction = PredictSchedule(matches, nil, time.Now())
+	assert.Empty(t, prediction.Matches)
+	assert.Equal(t, "All matches have been played", prediction.SlipDescription())
+}
+
+func TestPredictScheduleWithBlocks(t *testing.T) {
+	// Without a break in the schedule, the saved blocks are needed to tell where the second one starts.
+	matches := buildPredictionMatches()
+	for i := range matches {
+		matches[i].Time = matches[0].Time.Add(time.Duration(i) * 6 * time.Minute)
+	}
+	scheduleBlocks := []model.ScheduleBlock{{StartTime: matches[0].Time, NumMatches: 4, MatchSpacingSec: 360},
+		{StartTime: matches[4].Time, NumMatches: 4, MatchSpacingSec: 360}}
+	for i := 0; i < 3; i++ {
+		matches[i].StartedAt = matches[i].Time.Add(-time.Duration(i) * time.Minute)
+	}
+
+	prediction := PredictSchedule(matches, scheduleBlocks, matches[2].StartedAt.Add(time.Minute))
+	assert.Equal(t, 300, prediction.CycleTimeSec)
+	assert.Equal(t, matches[3].Time.Add(-3*time.Minute), prediction.Matches[0].PredictedTime)
+	assert.Equal(t, matches[4].Time, prediction.Matches[1].PredictedTime)
+	prediction = PredictSchedule(matches, nil, matches[2].StartedAt.Add(time.Minute))
+	assert.Equal(t, matches[4].Time.Add(-4*time.Minute), prediction.Matches[1].PredictedTime)
+
+	// Check that the breaks in the schedule are used for any matches that aren't covered by a saved block.
+	matches = buildPredictionMatches()
+	assert.Equal(t, []bool{false, false, false, false, true, false, false, false},
+		findBlockStarts(matches, scheduleBlocks[:1]))
+	assert.Equal(t, []bool{false, false, false, false, true, false, false, false}, findBlockStarts(matches, nil))
+}
+
+// Returns two blocks of four matches spaced six minutes apart, with an hour between the blocks.
+func buildPredictionMatches() []model.Match {
+	startTime := time.Unix(1500000000, 0).UTC()
+	matches := make([]model.Match, 8)
+	for i := range matches {
+		matches[i].Id = i + 1
+		matches[i].Time = startTime.Add(time.Duration(i) * 6 * time.Minute)
+		if i >= 4 {
+			matches[i].Time = matches[i].Time.Add(time.Hour)
+		}
+	}
+	return matches
+}
@@ -27,7 +27,8 @@ func (web *Web) announcerDisplayHandler(w http.ResponseWriter, r *http.Request)
 	}
 	data := struct {
 		*model.EventSettings
-	}{web.arena.EventSettings}
+		ScoreElements map[string]string
+	}{web.arena.EventSettings, game.OverridableScoreElements}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
 		handleWebErr(w, err)
@@ -92,9 +93,12 @@ func (web *Web) announcerDisplayWebsocketHandler(w http.ResponseWriter, r *http.
 		return
 	}
 	data = struct {
-		RedScore  int
-		BlueScore int
-	}{web.arena.RedScoreSummary().Score, web.arena.BlueScoreSummary().Score}
+		RedScore      int
+		BlueScore     int
+		RedOverrides  []game.ScoreOverride
+		BlueOverrides []game.ScoreOverride
+	}{web.arena.RedScoreSummary().Score, web.arena.BlueScoreSummary().Score,
+		web.arena.RedRealtimeScore.Overrides, web.arena.BlueRealtimeScore.Overrides}
 	err = websocket.Write("realtimeScore", data)
 	if err != nil {
 		log.Printf("Websocket error: %s", err)
@@ -137,9 +141,12 @@ func (web *Web) announcerDisplayWebsocketHandler(w http.ResponseWriter, r *http.
 				}
 				messageType = "realtimeScore"
 				message = struct {
-					RedScore  int
-					BlueScore int
-				}{web.arena.RedScoreSummary().Score, web.arena.BlueScoreSummary().Score}
+					RedScore      int
+					BlueScore     int
+					RedOverrides  []game.ScoreOverride
+					BlueOverrides []game.ScoreOverride
+				}{web.arena.RedScoreSummary().Score, web.arena.BlueScoreSummary().Score,
+					web.arena.RedRealtimeScore.Overrides, web.arena.BlueRealtimeScore.Overrides}
 			case _, ok := <-scorePostedListener:
 				if !ok {
 					return
@@ -154,10 +161,13 @@ func (web *Web) announcerDisplayWebsocketHandler(w http.ResponseWriter, r *http.
 					BlueFouls        []game.Foul
 					RedCards         map[string]string
 					BlueCards        map[string]string
+					RedOverrides     []game.ScoreOverride
+					BlueOverrides    []game.ScoreOverride
 				}{web.arena.SavedMatch.CapitalizedType(), web.arena.SavedMatch.DisplayName,
 					web.arena.SavedMatchResult.RedScoreSummary(), web.arena.SavedMatchResult.BlueScoreSummary(),
 					web.arena.SavedMatchResult.RedScore.Fouls, web.arena.SavedMatchResult.BlueScore.Fouls,
-					web.arena.SavedMatchResult.RedCards, web.arena.SavedMatchResult.BlueCards}
+					web.arena.SavedMatchResult.RedCards, web.arena.SavedMatchResult.BlueCards,
+					web.arena.SavedMatchResult.RedOverrides, web.arena.SavedMatchResult.BlueOverrides}
 			case _, ok := <-audienceDisplayListener:
 				if !ok {
 					return
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"github.com/Team254/cheesy-arena/game"
 	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/tournament"
 	"github.com/gorilla/mux"
 	"net/http"
 )
@@ -17,6 +18,7 @@ type MatchResultWithSummary struct {
 	model.MatchResult
 	RedSummary  *game.ScoreSummary
 	BlueSummary *game.ScoreSummary
+	Timeline    []model.AllianceScoringEvent
 }
 
 type MatchWithResult struct {
@@ -24,6 +26,11 @@ type MatchWithResult struct {
 	Result *MatchResultWithSummary
 }
 
+type SchedulePredictionWithDescription struct {
+	*tournament.SchedulePrediction
+	SlipDescription string
+}
+
 type RankingWithNickname struct {
 	game.Ranking
 	Nickname string
@@ -55,6 +62,7 @@ func (web *Web) matchesApiHandler(w http.ResponseWriter, r *http.Request) {
 			matchResultWithSummary = &MatchResultWithSummary{MatchResult: *matchResult}
 			matchResultWithSummary.RedSummary = matchResult.RedScoreSummary()
 			matchResultWithSummary.BlueSummary = matchResult.BlueScoreSummary()
+			matchResultWithSummary.Timeline = matchResult.Timeline()
 		}
 		matchesWithResults[i].Result = matchResultWithSummary
 	}
@@ -73,6 +81,34 @@ func (web *Web) matchesApiHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Generates a JSON dump of the predicted start times of the unplayed matches and how far the schedule has slipped.
+func (web *Web) scheduleApiHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsReader(w, r) {
+		return
+	}
+
+	vars := mux.Vars(r)
+	prediction, err := web.predictSchedule(vars["type"])
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	jsonData, err := json.MarshalIndent(SchedulePredictionWithDescription{prediction, prediction.SlipDescription()},
+		"", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
 // Generates a JSON dump of the sponsor slides for use by the audience display.
 func (web *Web) sponsorSlidesApiHandler(w http.ResponseWriter, r *http.Request) {
 	if !web.userIsReader(w, r) {
@@ -188,3 +224,80 @@ func (web *Web) alliancesApiHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// Generates a JSON dump of the round-robin playoff standings, or an empty list if the playoffs aren't a round-robin.
+func (web *Web) roundRobinStandingsApiHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsReader(w, r) {
+		return
+	}
+
+	standings := []tournament.RoundRobinStanding{}
+	if web.arena.EventSettings.ElimType == "roundRobin" {
+		var err error
+		standings, err = tournament.CalculateRoundRobinStandings(web.arena.Database)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(standings, "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a JSON dump of the field readiness and stack light state, for use by external indicators.
+func (web *Web) fieldStatusApiHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsReader(w, r) {
+		return
+	}
+
+	jsonData, err := json.MarshalIndent(web.arena.GetFieldReadyStatus(), "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a JSON dump of the network configuration that would be applied for the given match. Restricted to admins
+// since it includes the team WPA keys.
+func (web *Web) networkConfigApiHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	_, preview, err := web.getNetworkPreview(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	jsonData, err := json.MarshalIndent(preview, "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
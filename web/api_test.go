@@ -5,9 +5,12 @@ package web
 
 import (
 	"encoding/json"
+	"github.com/Team254/cheesy-arena/field"
 	"github.com/Team254/cheesy-arena/game"
 	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/tournament"
 	"github.com/stretchr/testify/assert"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -36,11 +39,46 @@ func TestMatchesApi(t *testing.T) {
 	if assert.Equal(t, 2, len(matchesData)) {
 		assert.Equal(t, match1.Id, matchesData[0].Match.Id)
 		assert.Equal(t, *matchResult1, matchesData[0].Result.MatchResult)
+		if assert.Equal(t, 3, len(matchesData[0].Result.Timeline)) {
+			assert.Equal(t, "blue", matchesData[0].Result.Timeline[1].Alliance)
+		}
 		assert.Equal(t, match2.Id, matchesData[1].Match.Id)
 		assert.Nil(t, matchesData[1].Result)
 	}
 }
 
+func TestScheduleApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	startTime := time.Now().Add(-20 * time.Minute).Truncate(time.Second)
+	for i := 0; i < 5; i++ {
+		match := model.Match{Type: "qualification", DisplayName: strconv.Itoa(i + 1),
+			Time: startTime.Add(time.Duration(i) * 10 * time.Minute), Red1: 254}
+		if i == 0 {
+			match.StartedAt = match.Time.Add(5 * time.Minute)
+			match.Status = "complete"
+		}
+		web.arena.Database.CreateMatch(&match)
+	}
+
+	recorder := web.getHttpResponse("/api/schedule/qualification")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/json", recorder.HeaderMap["Content-Type"][0])
+	var predictionData SchedulePredictionWithDescription
+	err := json.Unmarshal([]byte(recorder.Body.String()), &predictionData)
+	assert.Nil(t, err)
+	assert.Equal(t, "Behind by 10 minutes", predictionData.SlipDescription)
+	if assert.Equal(t, 4, len(predictionData.Matches)) {
+		assert.Equal(t, "2", predictionData.Matches[0].DisplayName)
+		assert.Equal(t, 254, predictionData.Matches[0].Red1)
+		assert.InDelta(t, startTime.Add(50*time.Minute).Unix(), predictionData.Matches[3].PredictedTime.Unix(), 2)
+	}
+
+	recorder = web.getHttpResponse("/api/schedule/practice")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "All matches have been played")
+}
+
 func TestRankingsApi(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -123,3 +161,60 @@ func TestAlliancesApi(t *testing.T) {
 		}
 	}
 }
+
+func TestRoundRobinStandingsApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	tournament.CreateTestAlliances(web.arena.Database, 4)
+
+	// Check that no standings are given for other playoff formats.
+	recorder := web.getHttpResponse("/api/round_robin_standings")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/json", recorder.HeaderMap["Content-Type"][0])
+	assert.Equal(t, "[]", recorder.Body.String())
+
+	web.arena.EventSettings.ElimType = "roundRobin"
+	web.arena.EventSettings.RoundRobinMatchesPerAlliance = 3
+	web.arena.Database.SaveEventSettings(web.arena.EventSettings)
+	tournament.UpdateEliminationSchedule(web.arena.Database, time.Unix(0, 0))
+	match, _ := web.arena.Database.GetMatchByName("elimination", "RR-1")
+	web.arena.Database.CreateMatchResult(&model.MatchResult{MatchId: match.Id, PlayNumber: 1,
+		MatchType: "elimination", RedScore: &game.Score{Takeoffs: 1}, BlueScore: &game.Score{Takeoffs: 3}})
+	match.Status = "complete"
+	match.Winner = "B"
+	web.arena.Database.SaveMatch(match)
+
+	recorder = web.getHttpResponse("/api/round_robin_standings")
+	assert.Equal(t, 200, recorder.Code)
+	var standings []tournament.RoundRobinStanding
+	err := json.Unmarshal([]byte(recorder.Body.String()), &standings)
+	assert.Nil(t, err)
+	if assert.Equal(t, 4, len(standings)) {
+		assert.Equal(t, 4, standings[0].AllianceId)
+		assert.Equal(t, []int{4, 40, 400}, standings[0].TeamIds)
+		assert.Equal(t, 1, standings[0].Wins)
+		assert.Equal(t, 150.0, standings[0].AverageScore)
+		assert.Equal(t, 1, standings[1].AllianceId)
+		assert.Equal(t, 1, standings[1].Losses)
+		assert.Equal(t, 50.0, standings[1].AverageScore)
+		assert.Equal(t, 0, standings[2].MatchesPlayed)
+	}
+}
+
+func TestFieldStatusApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/api/field_status")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/json", recorder.HeaderMap["Content-Type"][0])
+	var fieldStatus field.FieldReadyStatus
+	err := json.Unmarshal([]byte(recorder.Body.String()), &fieldStatus)
+	assert.Nil(t, err)
+	assert.Equal(t, field.PreMatch, fieldStatus.MatchState)
+	assert.False(t, fieldStatus.CanStartMatch)
+	assert.False(t, fieldStatus.RedAllianceReady)
+	assert.True(t, fieldStatus.StackLight.Red)
+	assert.True(t, fieldStatus.StackLight.Blue)
+	assert.False(t, fieldStatus.StackLight.Green)
+	assert.Equal(t, 6, len(fieldStatus.StationsReady))
+}
@@ -106,12 +106,14 @@ func (web *Web) audienceDisplayWebsocketHandler(w http.ResponseWriter, r *http.R
 		return
 	}
 	data = struct {
-		Match     *model.Match
-		MatchName string
-		RedScore  *game.ScoreSummary
-		BlueScore *game.ScoreSummary
+		Match      *model.Match
+		MatchName  string
+		RedScore   *game.ScoreSummary
+		BlueScore  *game.ScoreSummary
+		Tiebreaker string
 	}{web.arena.SavedMatch, web.arena.SavedMatch.CapitalizedType(),
-		web.arena.SavedMatchResult.RedScoreSummary(), web.arena.SavedMatchResult.BlueScoreSummary()}
+		web.arena.SavedMatchResult.RedScoreSummary(), web.arena.SavedMatchResult.BlueScoreSummary(),
+		game.ElimTiebreakers[web.arena.SavedMatch.Tiebreaker]}
 	err = websocket.Write("setFinalScore", data)
 	if err != nil {
 		log.Printf("Websocket error: %s", err)
@@ -168,12 +170,14 @@ func (web *Web) audienceDisplayWebsocketHandler(w http.ResponseWriter, r *http.R
 				}
 				messageType = "setFinalScore"
 				message = struct {
-					Match     *model.Match
-					MatchName string
-					RedScore  *game.ScoreSummary
-					BlueScore *game.ScoreSummary
+					Match      *model.Match
+					MatchName  string
+					RedScore   *game.ScoreSummary
+					BlueScore  *game.ScoreSummary
+					Tiebreaker string
 				}{web.arena.SavedMatch, web.arena.SavedMatch.CapitalizedType(),
-					web.arena.SavedMatchResult.RedScoreSummary(), web.arena.SavedMatchResult.BlueScoreSummary()}
+					web.arena.SavedMatchResult.RedScoreSummary(), web.arena.SavedMatchResult.BlueScoreSummary(),
+					game.ElimTiebreakers[web.arena.SavedMatch.Tiebreaker]}
 			case sound, ok := <-playSoundListener:
 				if !ok {
 					return
@@ -0,0 +1,58 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for reviewing how long the field took to cycle between matches.
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/tournament"
+	"net/http"
+)
+
+// Shows the cycle time report for the match type given in the request, defaulting to qualification.
+func (web *Web) cycleTimesHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsReader(w, r) {
+		return
+	}
+
+	matchType := r.URL.Query().Get("type")
+	if matchType != "practice" && matchType != "elimination" {
+		matchType = "qualification"
+	}
+	report, err := web.buildCycleTimeReport(matchType)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	template, err := web.parseFiles("templates/cycle_times.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		MatchType string
+		Report    *tournament.CycleTimeReport
+	}{web.arena.EventSettings, matchType, report}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Measures the cycle times of the played matches of the given type, grouped by their saved schedule blocks.
+func (web *Web) buildCycleTimeReport(matchType string) (*tournament.CycleTimeReport, error) {
+	matches, err := web.arena.Database.GetMatchesByType(matchType)
+	if err != nil {
+		return nil, err
+	}
+	scheduleBlocks, err := web.arena.Database.GetScheduleBlocksByMatchType(matchType)
+	if err != nil {
+		return nil, err
+	}
+	return tournament.CalculateCycleTimes(matches, scheduleBlocks), nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCycleTimes(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/cycle_times")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "No qualification matches have been played yet.")
+
+	createCycleTimeMatches(web)
+	recorder = web.getHttpResponse("/cycle_times?type=qualification")
+	assert.Equal(t, 200, recorder.Code)
+	body := recorder.Body.String()
+	assert.Contains(t, body, "<td>All matches</td>")
+	assert.Contains(t, body, "<td>Morning</td>")
+	assert.Contains(t, body, "<td class=\"text-center\">7:00</td>")
+	assert.Contains(t, body, "<td class=\"text-center\">0:45</td>")
+
+	recorder = web.getHttpResponse("/cycle_times?type=practice")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "No practice matches have been played yet.")
+}
+
+// Creates two played qualification matches seven minutes apart in a saved block named "Morning".
+func createCycleTimeMatches(web *Web) {
+	startTime := time.Date(2017, 7, 13, 19, 40, 0, 0, time.Local)
+	web.arena.Database.CreateScheduleBlock(&model.ScheduleBlock{MatchType: "qualification", Name: "Morning",
+		StartTime: startTime, NumMatches: 2, MatchSpacingSec: 360})
+	match1 := model.Match{Type: "qualification", DisplayName: "1", Time: startTime, StartedAt: startTime,
+		EndedAt: startTime.Add(150 * time.Second), ScoresCommittedAt: startTime.Add(195 * time.Second),
+		FieldResetAt: startTime.Add(4 * time.Minute)}
+	match2 := model.Match{Type: "qualification", DisplayName: "2", Time: startTime.Add(6 * time.Minute),
+		StartedAt: startTime.Add(7 * time.Minute)}
+	web.arena.Database.CreateMatch(&match1)
+	web.arena.Database.CreateMatch(&match2)
+}
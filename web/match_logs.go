@@ -0,0 +1,124 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for viewing the logs recorded for each team during a match.
+
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/gorilla/mux"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+const (
+	bandwidthChartWidth  = 600
+	bandwidthChartHeight = 150
+)
+
+// Bandwidth usage of one team over the course of a match, scaled for display as an SVG chart.
+type BandwidthChart struct {
+	model.BandwidthLog
+	Summary         model.BandwidthSummary
+	LimitMbps       float64
+	MaxMbps         float64
+	LimitY          float64
+	ToRobotPoints   string
+	FromRobotPoints string
+}
+
+// Shows the bandwidth usage charts for each team in the given match.
+func (web *Web) matchLogsHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsReader(w, r) {
+		return
+	}
+
+	vars := mux.Vars(r)
+	matchId, _ := strconv.Atoi(vars["matchId"])
+	match, err := web.arena.Database.GetMatchById(matchId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if match == nil {
+		handleWebErr(w, fmt.Errorf("Error: No such match: %d", matchId))
+		return
+	}
+	bandwidthLogs, err := web.arena.Database.GetBandwidthLogsForMatch(matchId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	var charts []BandwidthChart
+	for _, station := range []string{"R1", "R2", "R3", "B1", "B2", "B3"} {
+		for _, bandwidthLog := range bandwidthLogs {
+			if bandwidthLog.AllianceStation == station {
+				chart, err := web.buildBandwidthChart(bandwidthLog)
+				if err != nil {
+					handleWebErr(w, err)
+					return
+				}
+				charts = append(charts, *chart)
+			}
+		}
+	}
+
+	template, err := web.parseFiles("templates/match_logs.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Match       *model.Match
+		Charts      []BandwidthChart
+		ChartWidth  int
+		ChartHeight int
+	}{web.arena.EventSettings, match, charts, bandwidthChartWidth, bandwidthChartHeight}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Scales the given log to fit the chart, with the vertical axis covering both the peak usage and the team's limit.
+func (web *Web) buildBandwidthChart(bandwidthLog model.BandwidthLog) (*BandwidthChart, error) {
+	chart := BandwidthChart{BandwidthLog: bandwidthLog, Summary: bandwidthLog.Summarize(),
+		LimitMbps: web.arena.EventSettings.BandwidthAlertMbps}
+	team, err := web.arena.Database.GetTeamById(bandwidthLog.TeamId)
+	if err != nil {
+		return nil, err
+	}
+	if team != nil && team.BandwidthLimitMbps > 0 {
+		chart.LimitMbps = team.BandwidthLimitMbps
+	}
+
+	chart.MaxMbps = math.Ceil(math.Max(math.Max(chart.Summary.PeakMBpsToRobot, chart.Summary.PeakMBpsFromRobot),
+		math.Max(chart.LimitMbps, 1)))
+	matchDurationSec := float64(game.MatchTiming.AutoDurationSec + game.MatchTiming.PauseDurationSec +
+		game.MatchTiming.TeleopDurationSec)
+	scaleX := func(matchTimeSec float64) float64 {
+		return math.Min(matchTimeSec/matchDurationSec, 1) * bandwidthChartWidth
+	}
+	scaleY := func(mbps float64) float64 {
+		return bandwidthChartHeight - mbps/chart.MaxMbps*bandwidthChartHeight
+	}
+
+	var toRobotPoints, fromRobotPoints bytes.Buffer
+	for _, sample := range bandwidthLog.Samples {
+		toRobotPoints.WriteString(fmt.Sprintf("%.1f,%.1f ", scaleX(sample.MatchTimeSec), scaleY(sample.MBpsToRobot)))
+		fromRobotPoints.WriteString(fmt.Sprintf("%.1f,%.1f ", scaleX(sample.MatchTimeSec),
+			scaleY(sample.MBpsFromRobot)))
+	}
+	chart.ToRobotPoints = toRobotPoints.String()
+	chart.FromRobotPoints = fromRobotPoints.String()
+	chart.LimitY = scaleY(chart.LimitMbps)
+	return &chart, nil
+}
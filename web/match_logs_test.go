@@ -0,0 +1,42 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestMatchLogs(t *testing.T) {
+	web := setupTestWeb(t)
+
+	match := model.Match{Type: "qualification", DisplayName: "3"}
+	web.arena.Database.CreateMatch(&match)
+	recorder := web.getHttpResponse("/match_review/1/logs")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "No bandwidth usage was recorded")
+
+	web.arena.Database.CreateTeam(&model.Team{Id: 254, BandwidthLimitMbps: 4})
+	web.arena.Database.SaveBandwidthLog(&model.BandwidthLog{MatchId: 1, TeamId: 1114, AllianceStation: "B2",
+		Samples: []model.BandwidthSample{{MatchTimeSec: 0, MBpsToRobot: 1, MBpsFromRobot: 2}}})
+	web.arena.Database.SaveBandwidthLog(&model.BandwidthLog{MatchId: 1, TeamId: 254, AllianceStation: "R1",
+		Samples: []model.BandwidthSample{{MatchTimeSec: 0, MBpsToRobot: 2, MBpsFromRobot: 1},
+			{MatchTimeSec: 76, MBpsToRobot: 6, MBpsFromRobot: 0}}})
+	recorder = web.getHttpResponse("/match_review/1/logs")
+	assert.Equal(t, 200, recorder.Code)
+	body := recorder.Body.String()
+	assert.Contains(t, body, "R1: Team 254")
+	assert.Contains(t, body, "to robot peak 6.00 /\n          avg 4.00 Mbps")
+	assert.Contains(t, body, "points=\"0.0,100.0 300.0,0.0 \"")
+	assert.Contains(t, body, "limit (4 Mbps)")
+	assert.Contains(t, body, "B2: Team 1114")
+	assert.Contains(t, body, "limit (7 Mbps)")
+	assert.True(t, strings.Index(body, "R1: Team 254") < strings.Index(body, "B2: Team 1114"))
+
+	recorder = web.getHttpResponse("/match_review/2/logs")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "No such match")
+}
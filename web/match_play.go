@@ -21,11 +21,12 @@ import (
 )
 
 type MatchPlayListItem struct {
-	Id          int
-	DisplayName string
-	Time        string
-	Status      string
-	ColorClass  string
+	Id            int
+	DisplayName   string
+	Time          string
+	PredictedTime string
+	Status        string
+	ColorClass    string
 }
 
 type MatchPlayList []MatchPlayListItem
@@ -77,6 +78,15 @@ func (web *Web) matchPlayHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	isReplay := matchResult != nil
+	scheduleStatus := ""
+	if currentMatchType != "test" {
+		prediction, err := web.predictSchedule(currentMatchType)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		scheduleStatus = prediction.SlipDescription()
+	}
 	data := struct {
 		*model.EventSettings
 		MatchesByType     map[string]MatchPlayList
@@ -84,7 +94,9 @@ func (web *Web) matchPlayHandler(w http.ResponseWriter, r *http.Request) {
 		Match             *model.Match
 		AllowSubstitution bool
 		IsReplay          bool
-	}{web.arena.EventSettings, matchesByType, currentMatchType, web.arena.CurrentMatch, allowSubstitution, isReplay}
+		ScheduleStatus    string
+	}{web.arena.EventSettings, matchesByType, currentMatchType, web.arena.CurrentMatch, allowSubstitution, isReplay,
+		scheduleStatus}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
 		handleWebErr(w, err)
@@ -346,6 +358,12 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 				websocket.WriteError(err.Error())
 				continue
 			}
+		case "retryNetworkSetup":
+			err = web.arena.RetryNetworkSetup()
+			if err != nil {
+				websocket.WriteError(err.Error())
+				continue
+			}
 		case "abortMatch":
 			err = web.arena.AbortMatch()
 			if err != nil {
@@ -353,6 +371,24 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 				continue
 			}
 		case "commitResults":
+			args := struct {
+				ConfirmWarnings bool
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				websocket.WriteError(err.Error())
+				continue
+			}
+			validation := web.validateCurrentMatchResult()
+			if len(validation.Errors) > 0 || len(validation.Warnings) > 0 && !args.ConfirmWarnings {
+				// Send the problems back to be fixed or confirmed instead of committing.
+				err = websocket.Write("scoreValidation", validation)
+				if err != nil {
+					log.Printf("Websocket error: %s", err)
+					return
+				}
+				continue
+			}
 			err = web.commitCurrentMatchScore()
 			if err != nil {
 				websocket.WriteError(err.Error())
@@ -423,13 +459,53 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 	}
 }
 
+// Sets the winner of the given match from its result, applying the configured tiebreakers to a tied playoff match.
+func (web *Web) determineMatchWinner(match *model.Match, matchResult *model.MatchResult) error {
+	redScore := matchResult.RedScoreSummary()
+	blueScore := matchResult.BlueScoreSummary()
+	match.Tiebreaker = ""
+	if redScore.Score > blueScore.Score {
+		match.Winner = "R"
+	} else if redScore.Score < blueScore.Score {
+		match.Winner = "B"
+	} else if match.Type == "elimination" {
+		tiebreakers, err := game.ParseElimTiebreakers(web.arena.EventSettings.ElimTiebreakers)
+		if err != nil {
+			return err
+		}
+		match.Winner, match.Tiebreaker = game.BreakElimTie(redScore, blueScore, tiebreakers)
+	} else {
+		match.Winner = "T"
+	}
+	return nil
+}
+
 // Saves the given match and result to the database, supplanting any previous result for the match.
 func (web *Web) commitMatchScore(match *model.Match, matchResult *model.MatchResult, loadToShowBuffer bool) error {
+	if match.Type != "test" {
+		// Upgrade any yellow cards for teams that already had one to red cards before the score is corrected for them.
+		carriedYellowCards, err := tournament.GetCarriedYellowCards(web.arena.Database, match)
+		if err != nil {
+			return err
+		}
+		isPlayoff := match.Type == "elimination"
+		game.EscalateCards(matchResult.RedCards, matchResult.RedCardReasons, carriedYellowCards, isPlayoff)
+		game.EscalateCards(matchResult.BlueCards, matchResult.BlueCardReasons, carriedYellowCards, isPlayoff)
+	}
+
 	if match.Type == "elimination" {
 		// Adjust the score if necessary for an elimination DQ.
 		matchResult.CorrectEliminationScore()
 	}
 
+	if match.Type != "test" {
+		// Determine the winner before the result is shown, since it may have been decided by a tiebreaker.
+		err := web.determineMatchWinner(match, matchResult)
+		if err != nil {
+			return err
+		}
+	}
+
 	if loadToShowBuffer {
 		// Store the result in the buffer to be shown in the audience display.
 		web.arena.SavedMatch = match
@@ -469,15 +545,6 @@ func (web *Web) commitMatchScore(match *model.Match, matchResult *model.MatchRes
 
 	// Update and save the match record to the database.
 	match.Status = "complete"
-	redScore := matchResult.RedScoreSummary()
-	blueScore := matchResult.BlueScoreSummary()
-	if redScore.Score > blueScore.Score {
-		match.Winner = "R"
-	} else if redScore.Score < blueScore.Score {
-		match.Winner = "B"
-	} else {
-		match.Winner = "T"
-	}
 	err := web.arena.Database.SaveMatch(match)
 	if err != nil {
 		return err
@@ -542,7 +609,12 @@ func (web *Web) commitMatchScore(match *model.Match, matchResult *model.MatchRes
 func (web *Web) getCurrentMatchResult() *model.MatchResult {
 	return &model.MatchResult{MatchId: web.arena.CurrentMatch.Id, MatchType: web.arena.CurrentMatch.Type,
 		RedScore: &web.arena.RedRealtimeScore.CurrentScore, BlueScore: &web.arena.BlueRealtimeScore.CurrentScore,
-		RedCards: web.arena.RedRealtimeScore.Cards, BlueCards: web.arena.BlueRealtimeScore.Cards}
+		RedCards: web.arena.RedRealtimeScore.Cards, BlueCards: web.arena.BlueRealtimeScore.Cards,
+		RedTimeline: web.arena.RedRealtimeScore.Timeline, BlueTimeline: web.arena.BlueRealtimeScore.Timeline,
+		RedOverrides: web.arena.RedRealtimeScore.Overrides, BlueOverrides: web.arena.BlueRealtimeScore.Overrides,
+		RedCardReasons: web.arena.RedRealtimeScore.CardReasons, BlueCardReasons: web.arena.BlueRealtimeScore.CardReasons,
+		RedScoreSources:  web.arena.RedRealtimeScore.ScoreSources(),
+		BlueScoreSources: web.arena.BlueRealtimeScore.ScoreSources()}
 }
 
 // Saves the realtime result as the final score for the match currently loaded into the arena.
@@ -550,6 +622,22 @@ func (web *Web) commitCurrentMatchScore() error {
 	return web.commitMatchScore(web.arena.CurrentMatch, web.getCurrentMatchResult(), true)
 }
 
+// Checks the realtime result of the match currently loaded into the arena for impossible or unlikely scores.
+func (web *Web) validateCurrentMatchResult() *model.MatchResultValidation {
+	return model.ValidateMatchResult(web.arena.CurrentMatch, web.getCurrentMatchResult(), web.getBypassedTeams())
+}
+
+// Returns the set of teams that are bypassed in the match currently loaded into the arena.
+func (web *Web) getBypassedTeams() map[int]bool {
+	bypassedTeams := make(map[int]bool)
+	for _, allianceStation := range web.arena.AllianceStations {
+		if allianceStation.Bypass && allianceStation.Team != nil {
+			bypassedTeams[allianceStation.Team.Id] = true
+		}
+	}
+	return bypassedTeams
+}
+
 // Helper function to implement the required interface for Sort.
 func (list MatchPlayList) Len() int {
 	return len(list)
@@ -572,6 +660,15 @@ func (web *Web) buildMatchPlayList(matchType string) (MatchPlayList, error) {
 		return MatchPlayList{}, err
 	}
 
+	scheduleBlocks, err := web.arena.Database.GetScheduleBlocksByMatchType(matchType)
+	if err != nil {
+		return MatchPlayList{}, err
+	}
+	predictedTimes := make(map[int]time.Time)
+	for _, predictedMatch := range tournament.PredictSchedule(matches, scheduleBlocks, time.Now()).Matches {
+		predictedTimes[predictedMatch.Id] = predictedMatch.PredictedTime
+	}
+
 	prefix := ""
 	if matchType == "practice" {
 		prefix = "P"
@@ -583,6 +680,9 @@ func (web *Web) buildMatchPlayList(matchType string) (MatchPlayList, error) {
 		matchPlayList[i].Id = match.Id
 		matchPlayList[i].DisplayName = prefix + match.DisplayName
 		matchPlayList[i].Time = match.Time.Local().Format("3:04 PM")
+		if predictedTime, ok := predictedTimes[match.Id]; ok {
+			matchPlayList[i].PredictedTime = predictedTime.Local().Format("3:04 PM")
+		}
 		matchPlayList[i].Status = match.Status
 		switch match.Winner {
 		case "R":
@@ -604,3 +704,16 @@ func (web *Web) buildMatchPlayList(matchType string) (MatchPlayList, error) {
 
 	return matchPlayList, nil
 }
+
+// Predicts the start times of the unplayed matches of the given type.
+func (web *Web) predictSchedule(matchType string) (*tournament.SchedulePrediction, error) {
+	matches, err := web.arena.Database.GetMatchesByType(matchType)
+	if err != nil {
+		return nil, err
+	}
+	scheduleBlocks, err := web.arena.Database.GetScheduleBlocksByMatchType(matchType)
+	if err != nil {
+		return nil, err
+	}
+	return tournament.PredictSchedule(matches, scheduleBlocks, time.Now()), nil
+}
@@ -41,6 +41,27 @@ func TestMatchPlay(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "Q1")
 	assert.Contains(t, recorder.Body.String(), "SF1-1")
 	assert.Contains(t, recorder.Body.String(), "SF1-2")
+	assert.NotContains(t, recorder.Body.String(), "roundRobinStandings")
+
+	// Check that the round-robin standings screen can be chosen if the playoffs are a round-robin.
+	web.arena.EventSettings.ElimType = "roundRobin"
+	recorder = web.getHttpResponse("/match_play")
+	assert.Contains(t, recorder.Body.String(), "value=\"roundRobinStandings\"")
+}
+
+func TestMatchPlayScheduleStatus(t *testing.T) {
+	web := setupTestWeb(t)
+	currentMatchType = "qualification"
+	defer func() { currentMatchType = "" }()
+
+	startTime := time.Now().Add(-time.Hour)
+	web.arena.Database.CreateMatch(&model.Match{Type: "qualification", DisplayName: "1", Time: startTime})
+	web.arena.Database.CreateMatch(&model.Match{Type: "qualification", DisplayName: "2",
+		Time: startTime.Add(6 * time.Minute)})
+	recorder := web.getHttpResponse("/match_play")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Behind by 60 minutes")
+	assert.Contains(t, recorder.Body.String(), "<th>Predicted</th>")
 }
 
 func TestMatchPlayLoad(t *testing.T) {
@@ -187,7 +208,28 @@ func TestCommitEliminationTie(t *testing.T) {
 	web.arena.Database.SaveMatch(match)
 	web.commitMatchScore(match, matchResult, false)
 	match, _ = web.arena.Database.GetMatchById(1)
-	assert.Equal(t, "T", match.Winner) // No elimination tiebreakers.
+	assert.Equal(t, "B", match.Winner)
+	assert.Equal(t, "foulPoints", match.Tiebreaker)
+
+	// Check that the configured tiebreaker chain is used and that an unbroken tie is replayed.
+	web.arena.EventSettings.ElimTiebreakers = "autoPoints,rotorPoints"
+	web.commitMatchScore(match, matchResult, false)
+	match, _ = web.arena.Database.GetMatchById(1)
+	assert.Equal(t, "T", match.Winner)
+	assert.Equal(t, "replay", match.Tiebreaker)
+	matchResult.RedScore.AutoFuelHigh = 1
+	matchResult.RedScore.FuelHigh = 12
+	web.commitMatchScore(match, matchResult, false)
+	match, _ = web.arena.Database.GetMatchById(1)
+	assert.Equal(t, "R", match.Winner)
+	assert.Equal(t, "autoPoints", match.Tiebreaker)
+
+	// A decisive score shouldn't record a tiebreaker.
+	matchResult.BlueScore.AutoMobility = 3
+	web.commitMatchScore(match, matchResult, false)
+	match, _ = web.arena.Database.GetMatchById(1)
+	assert.Equal(t, "B", match.Winner)
+	assert.Equal(t, "", match.Tiebreaker)
 }
 
 func TestCommitCards(t *testing.T) {
@@ -236,6 +278,39 @@ func TestCommitCards(t *testing.T) {
 	assert.Equal(t, 533, matchResult.BlueScoreSummary().Score)
 }
 
+func TestCommitCardEscalation(t *testing.T) {
+	web := setupTestWeb(t)
+
+	match1 := &model.Match{Type: "qualification", DisplayName: "1", Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5,
+		Blue3: 6}
+	web.arena.Database.CreateMatch(match1)
+	match2 := &model.Match{Type: "qualification", DisplayName: "2", Red1: 5, Red2: 7, Red3: 8, Blue1: 9, Blue2: 10,
+		Blue3: 11}
+	web.arena.Database.CreateMatch(match2)
+	matchResult := model.NewMatchResult()
+	matchResult.MatchId = match1.Id
+	matchResult.BlueCards = map[string]string{"5": "yellow"}
+	assert.Nil(t, web.commitMatchScore(match1, matchResult, false))
+
+	// Check that a second yellow card is upgraded to a red card.
+	matchResult = model.NewMatchResult()
+	matchResult.MatchId = match2.Id
+	matchResult.RedCards = map[string]string{"5": "yellow", "7": "yellow"}
+	matchResult.RedCardReasons = map[string]string{"5": "G25 tipping"}
+	assert.Nil(t, web.commitMatchScore(match2, matchResult, false))
+	matchResult, _ = web.arena.Database.GetMatchResultForMatch(match2.Id)
+	assert.Equal(t, map[string]string{"5": "red", "7": "yellow"}, matchResult.RedCards)
+	assert.Equal(t, "Second yellow card: G25 tipping", matchResult.RedCardReasons["5"])
+
+	// Check that replaying the first match doesn't upgrade its own card.
+	matchResult = model.NewMatchResult()
+	matchResult.MatchId = match1.Id
+	matchResult.BlueCards = map[string]string{"5": "yellow"}
+	assert.Nil(t, web.commitMatchScore(match1, matchResult, false))
+	matchResult, _ = web.arena.Database.GetMatchResultForMatch(match1.Id)
+	assert.Equal(t, "yellow", matchResult.BlueCards["5"])
+}
+
 func TestMatchPlayWebsocketCommands(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -280,6 +355,8 @@ func TestMatchPlayWebsocketCommands(t *testing.T) {
 	ws.Write("toggleBypass", "R3")
 	readWebsocketType(t, ws, "status")
 	assert.Equal(t, false, web.arena.AllianceStations["R3"].Bypass)
+	ws.Write("retryNetworkSetup", nil)
+	assert.Contains(t, readWebsocketError(t, ws), "Advanced network security is not enabled")
 
 	// Go through match flow.
 	ws.Write("abortMatch", nil)
@@ -323,6 +400,50 @@ func TestMatchPlayWebsocketCommands(t *testing.T) {
 	assert.Equal(t, "logo", web.arena.AllianceStationDisplayScreen)
 }
 
+func TestMatchPlayWebsocketCommitValidation(t *testing.T) {
+	web := setupTestWeb(t)
+
+	match := model.Match{Type: "qualification", DisplayName: "1", Red1: 1001, Red2: 1002, Red3: 1003, Blue1: 1004,
+		Blue2: 1005, Blue3: 1006}
+	web.arena.Database.CreateMatch(&match)
+	assert.Nil(t, web.arena.LoadMatch(&match))
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial(wsUrl+"/match_play/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := &Websocket{conn, new(sync.Mutex)}
+	readWebsocketMultiple(t, ws, 7)
+	web.arena.MatchState = field.PostMatch
+
+	// Impossible results can't be committed even when confirmed.
+	web.arena.BlueRealtimeScore.CurrentScore.Fouls = []game.Foul{{game.Rule{"G22", false}, 1001, 30, 0, false, false}}
+	ws.Write("commitResults", map[string]interface{}{"confirmWarnings": true})
+	validation := readWebsocketType(t, ws, "scoreValidation").(map[string]interface{})
+	assert.Equal(t, []interface{}{"Blue foul G22 at 30.0 seconds is against team 1001, who is on the other alliance."},
+		validation["Errors"])
+	matchResult, _ := web.arena.Database.GetMatchResultForMatch(match.Id)
+	assert.Nil(t, matchResult)
+
+	// Unlikely results need to be confirmed.
+	web.arena.BlueRealtimeScore.CurrentScore.Fouls = []game.Foul{{game.Rule{"G22", false}, 1004, 30, 0, false, false}}
+	web.arena.RedRealtimeScore.CurrentScore.AutoMobility = 3
+	web.arena.AllianceStations["R1"].Bypass = true
+	ws.Write("commitResults", nil)
+	validation = readWebsocketType(t, ws, "scoreValidation").(map[string]interface{})
+	assert.Empty(t, validation["Errors"])
+	assert.Equal(t, []interface{}{"Red auto mobility of 3 is more than the 2 robots that weren't bypassed."},
+		validation["Warnings"])
+	matchResult, _ = web.arena.Database.GetMatchResultForMatch(match.Id)
+	assert.Nil(t, matchResult)
+	ws.Write("commitResults", map[string]interface{}{"confirmWarnings": true})
+	readWebsocketType(t, ws, "reload")
+	matchResult, _ = web.arena.Database.GetMatchResultForMatch(match.Id)
+	if assert.NotNil(t, matchResult) {
+		assert.Equal(t, 3, matchResult.RedScore.AutoMobility)
+	}
+}
+
 func TestMatchPlayWebsocketNotifications(t *testing.T) {
 	web := setupTestWeb(t)
 
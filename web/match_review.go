@@ -7,6 +7,7 @@ package web
 
 import (
 	"fmt"
+	"github.com/Team254/cheesy-arena/game"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/gorilla/mux"
 	"net/http"
@@ -21,6 +22,7 @@ type MatchReviewListItem struct {
 	BlueTeams   []int
 	RedScore    int
 	BlueScore   int
+	Tiebreaker  string
 	ColorClass  string
 }
 
@@ -80,26 +82,12 @@ func (web *Web) matchReviewEditGetHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	template, err := web.parseFiles("templates/edit_match_result.html", "templates/base.html")
-	if err != nil {
-		handleWebErr(w, err)
-		return
-	}
 	matchResultJson, err := matchResult.Serialize()
 	if err != nil {
 		handleWebErr(w, err)
 		return
 	}
-	data := struct {
-		*model.EventSettings
-		Match           *model.Match
-		MatchResultJson *model.MatchResultDb
-	}{web.arena.EventSettings, match, matchResultJson}
-	err = template.ExecuteTemplate(w, "base", data)
-	if err != nil {
-		handleWebErr(w, err)
-		return
-	}
+	web.renderEditMatchResult(w, r, match, matchResultJson, nil)
 }
 
 // Updates the results for a match.
@@ -114,11 +102,18 @@ func (web *Web) matchReviewEditPostHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// Only the scores and cards are editable, so carry the rest of the existing result over.
+	matchResultJson, err := matchResult.Serialize()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
 	r.ParseForm()
-	matchResultJson := model.MatchResultDb{Id: matchResult.Id, MatchId: match.Id, PlayNumber: matchResult.PlayNumber,
-		MatchType: matchResult.MatchType, RedScoreJson: r.PostFormValue("redScoreJson"),
-		BlueScoreJson: r.PostFormValue("blueScoreJson"), RedCardsJson: r.PostFormValue("redCardsJson"),
-		BlueCardsJson: r.PostFormValue("blueCardsJson")}
+	matchResultJson.MatchId = match.Id
+	matchResultJson.RedScoreJson = r.PostFormValue("redScoreJson")
+	matchResultJson.BlueScoreJson = r.PostFormValue("blueScoreJson")
+	matchResultJson.RedCardsJson = r.PostFormValue("redCardsJson")
+	matchResultJson.BlueCardsJson = r.PostFormValue("blueCardsJson")
 
 	// Deserialize the JSON using the same mechanism as to store scoring information in the database.
 	matchResult, err = matchResultJson.Deserialize()
@@ -127,6 +122,17 @@ func (web *Web) matchReviewEditPostHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// Don't save results that can't have happened, and make sure unlikely ones are intentional.
+	var bypassedTeams map[int]bool
+	if isCurrent {
+		bypassedTeams = web.getBypassedTeams()
+	}
+	validation := model.ValidateMatchResult(match, matchResult, bypassedTeams)
+	if len(validation.Errors) > 0 || len(validation.Warnings) > 0 && r.PostFormValue("confirmWarnings") != "true" {
+		web.renderEditMatchResult(w, r, match, matchResultJson, validation)
+		return
+	}
+
 	if isCurrent {
 		// If editing the current match, just save it back to memory.
 		web.arena.RedRealtimeScore.CurrentScore = *matchResult.RedScore
@@ -146,6 +152,61 @@ func (web *Web) matchReviewEditPostHandler(w http.ResponseWriter, r *http.Reques
 	}
 }
 
+// Shows the timeline of scoring events recorded for a match.
+func (web *Web) matchReviewTimelineHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsReader(w, r) {
+		return
+	}
+
+	match, matchResult, _, err := web.getMatchResultFromRequest(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	template, err := web.parseFiles("templates/match_timeline.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Match            *model.Match
+		Timeline         []model.AllianceScoringEvent
+		RedOverrides     []game.ScoreOverride
+		BlueOverrides    []game.ScoreOverride
+		RedScoreSources  map[string]string
+		BlueScoreSources map[string]string
+		ScoreElements    map[string]string
+	}{web.arena.EventSettings, match, matchResult.Timeline(), matchResult.RedOverrides, matchResult.BlueOverrides,
+		matchResult.RedScoreSources, matchResult.BlueScoreSources, game.OverridableScoreElements}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+func (web *Web) renderEditMatchResult(w http.ResponseWriter, r *http.Request, match *model.Match,
+	matchResultJson *model.MatchResultDb, validation *model.MatchResultValidation) {
+	template, err := web.parseFiles("templates/edit_match_result.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Match           *model.Match
+		MatchResultJson *model.MatchResultDb
+		Validation      *model.MatchResultValidation
+	}{web.arena.EventSettings, match, matchResultJson, validation}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
 // Load the match result for the match referenced in the HTTP query string.
 func (web *Web) getMatchResultFromRequest(r *http.Request) (*model.Match, *model.MatchResult, bool, error) {
 	vars := mux.Vars(r)
@@ -204,6 +265,7 @@ func (web *Web) buildMatchReviewList(matchType string) ([]MatchReviewListItem, e
 			matchReviewList[i].RedScore = matchResult.RedScoreSummary().Score
 			matchReviewList[i].BlueScore = matchResult.BlueScoreSummary().Score
 		}
+		matchReviewList[i].Tiebreaker = game.ElimTiebreakers[match.Tiebreaker]
 		switch match.Winner {
 		case "R":
 			matchReviewList[i].ColorClass = "danger"
@@ -17,7 +17,8 @@ func TestMatchReview(t *testing.T) {
 	match1 := model.Match{Type: "practice", DisplayName: "1", Status: "complete", Winner: "R"}
 	match2 := model.Match{Type: "practice", DisplayName: "2"}
 	match3 := model.Match{Type: "qualification", DisplayName: "1", Status: "complete", Winner: "B"}
-	match4 := model.Match{Type: "elimination", DisplayName: "SF1-1", Status: "complete", Winner: "T"}
+	match4 := model.Match{Type: "elimination", DisplayName: "SF1-1", Status: "complete", Winner: "B",
+		Tiebreaker: "rotorPoints"}
 	match5 := model.Match{Type: "elimination", DisplayName: "SF1-2"}
 	web.arena.Database.CreateMatch(&match1)
 	web.arena.Database.CreateMatch(&match2)
@@ -33,6 +34,7 @@ func TestMatchReview(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "Q1")
 	assert.Contains(t, recorder.Body.String(), "SF1-1")
 	assert.Contains(t, recorder.Body.String(), "SF1-2")
+	assert.Contains(t, recorder.Body.String(), "Most rotor points")
 }
 
 func TestMatchReviewEditExistingResult(t *testing.T) {
@@ -63,7 +65,7 @@ func TestMatchReviewEditExistingResult(t *testing.T) {
 
 	// Update the score to something else.
 	postBody := "redScoreJson={\"AutoMobility\":3}&blueScoreJson={\"Rotors\":3," +
-		"\"Fouls\":[{\"TeamId\":973,\"Rule\":\"G22\"}]}&redCardsJson={\"105\":\"yellow\"}&blueCardsJson={}"
+		"\"Fouls\":[{\"TeamId\":1004,\"Rule\":\"G22\"}]}&redCardsJson={\"1001\":\"yellow\"}&blueCardsJson={}"
 	recorder = web.postHttpResponse(fmt.Sprintf("/match_review/%d/edit", match.Id), postBody)
 	assert.Equal(t, 303, recorder.Code)
 
@@ -75,6 +77,37 @@ func TestMatchReviewEditExistingResult(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "120") // The blue score
 }
 
+func TestMatchReviewTimeline(t *testing.T) {
+	web := setupTestWeb(t)
+
+	match := model.Match{Type: "qualification", DisplayName: "7", Status: "complete", Red1: 1001, Red2: 1002,
+		Red3: 1003, Blue1: 1004, Blue2: 1005, Blue3: 1006}
+	web.arena.Database.CreateMatch(&match)
+	recorder := web.getHttpResponse(fmt.Sprintf("/match_review/%d/timeline", match.Id))
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "No scoring events were recorded")
+
+	web.arena.Database.CreateMatchResult(model.BuildTestMatchResult(match.Id, 1))
+	recorder = web.getHttpResponse(fmt.Sprintf("/match_review/%d/timeline", match.Id))
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "0:48")
+	assert.Contains(t, recorder.Body.String(), "Rotor 2 turning")
+	assert.Contains(t, recorder.Body.String(), "Foul G22 on 973")
+	assert.Contains(t, recorder.Body.String(), "Touchpad 3 light out")
+	assert.Contains(t, recorder.Body.String(), "Score Sources")
+
+	// Editing the score shouldn't discard the timeline.
+	postBody := "redScoreJson={\"AutoMobility\":3}&blueScoreJson={}&redCardsJson={}&blueCardsJson={}"
+	recorder = web.postHttpResponse(fmt.Sprintf("/match_review/%d/edit", match.Id), postBody)
+	assert.Equal(t, 303, recorder.Code)
+	matchResult, _ := web.arena.Database.GetMatchResultForMatch(match.Id)
+	assert.Equal(t, 2, len(matchResult.RedTimeline))
+	assert.Equal(t, 1, len(matchResult.BlueTimeline))
+
+	recorder = web.getHttpResponse("/match_review/12345/timeline")
+	assert.Equal(t, 500, recorder.Code)
+}
+
 func TestMatchReviewCreateNewResult(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -95,7 +128,7 @@ func TestMatchReviewCreateNewResult(t *testing.T) {
 
 	// Update the score to something else.
 	postBody := "redScoreJson={\"AutoRotors\":1}&blueScoreJson={\"FuelHigh\":30," +
-		"\"Fouls\":[{\"TeamId\":973,\"Rule\":\"G22\"}]}&redCardsJson={\"105\":\"yellow\"}&blueCardsJson={}"
+		"\"Fouls\":[{\"TeamId\":1004,\"Rule\":\"G22\"}]}&redCardsJson={\"1001\":\"yellow\"}&blueCardsJson={}"
 	recorder = web.postHttpResponse(fmt.Sprintf("/match_review/%d/edit", match.Id), postBody)
 	assert.Equal(t, 303, recorder.Code)
 
@@ -106,3 +139,34 @@ func TestMatchReviewCreateNewResult(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "65") // The red score
 	assert.Contains(t, recorder.Body.String(), "10") // The blue score
 }
+
+func TestMatchReviewEditValidation(t *testing.T) {
+	web := setupTestWeb(t)
+
+	match := model.Match{Type: "qualification", DisplayName: "12", Red1: 1001, Red2: 1002, Red3: 1003, Blue1: 1004,
+		Blue2: 1005, Blue3: 1006}
+	web.arena.Database.CreateMatch(&match)
+
+	// Impossible results shouldn't be saved.
+	postBody := "redScoreJson={\"AutoMobility\":4}&blueScoreJson={}&redCardsJson={}&blueCardsJson={\"1001\":\"red\"}"
+	recorder := web.postHttpResponse(fmt.Sprintf("/match_review/%d/edit", match.Id), postBody)
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Red auto mobility of 4 is more than the 3 teams on the alliance.")
+	assert.Contains(t, recorder.Body.String(), "Blue red card is for team 1001, who is on the other alliance.")
+	assert.NotContains(t, recorder.Body.String(), "confirmWarnings")
+	matchResult, _ := web.arena.Database.GetMatchResultForMatch(match.Id)
+	assert.Nil(t, matchResult)
+
+	// Unlikely results for the current match should need to be confirmed.
+	assert.Nil(t, web.arena.LoadMatch(&match))
+	web.arena.AllianceStations["R2"].Bypass = true
+	postBody = "redScoreJson={\"AutoMobility\":3}&blueScoreJson={}&redCardsJson={}&blueCardsJson={}"
+	recorder = web.postHttpResponse("/match_review/current/edit", postBody)
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Red auto mobility of 3 is more than the 2 robots that")
+	assert.Contains(t, recorder.Body.String(), "confirmWarnings")
+	assert.Equal(t, 0, web.arena.RedRealtimeScore.CurrentScore.AutoMobility)
+	recorder = web.postHttpResponse("/match_review/current/edit", postBody+"&confirmWarnings=true")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, 3, web.arena.RedRealtimeScore.CurrentScore.AutoMobility)
+}
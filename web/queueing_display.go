@@ -0,0 +1,94 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web handlers for the queueing display.
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"io"
+	"log"
+	"net/http"
+)
+
+// Renders the queueing display which shows the upcoming matches and when they are expected to start.
+func (web *Web) queueingDisplayHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsReader(w, r) {
+		return
+	}
+
+	matchType := r.URL.Query().Get("type")
+	if matchType == "" {
+		matchType = currentMatchType
+	}
+	if matchType == "" || matchType == "test" {
+		matchType = "qualification"
+	}
+
+	template, err := web.parseFiles("templates/queueing_display.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		MatchType string
+	}{web.arena.EventSettings, matchType}
+	err = template.ExecuteTemplate(w, "queueing_display.html", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// The websocket endpoint for the queueing display, used only to force reloads remotely.
+func (web *Web) queueingDisplayWebsocketHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsReader(w, r) {
+		return
+	}
+
+	websocket, err := NewWebsocket(w, r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer websocket.Close()
+
+	reloadDisplaysListener := web.arena.ReloadDisplaysNotifier.Listen()
+	defer close(reloadDisplaysListener)
+
+	// Spin off a goroutine to listen for notifications and pass them on through the websocket.
+	go func() {
+		for {
+			var messageType string
+			var message interface{}
+			select {
+			case _, ok := <-reloadDisplaysListener:
+				if !ok {
+					return
+				}
+				messageType = "reload"
+				message = nil
+			}
+			err = websocket.Write(messageType, message)
+			if err != nil {
+				// The client has probably closed the connection; nothing to do here.
+				return
+			}
+		}
+	}()
+
+	// Loop, waiting for commands and responding to them, until the client closes the connection.
+	for {
+		_, _, err := websocket.Read()
+		if err != nil {
+			if err == io.EOF {
+				// Client has closed the connection; nothing to do here.
+				return
+			}
+			log.Printf("Websocket error: %s", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestQueueingDisplay(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/displays/queueing?type=practice")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Queueing Display - Untitled Event - Cheesy Arena")
+	assert.Contains(t, recorder.Body.String(), "var matchType = \"practice\";")
+}
+
+func TestQueueingDisplayWebsocket(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial(wsUrl+"/displays/queueing/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := &Websocket{conn, new(sync.Mutex)}
+
+	recorder := web.getHttpResponse("/setup/field/reload_displays")
+	assert.Equal(t, 303, recorder.Code)
+	readWebsocketType(t, ws, "reload")
+}
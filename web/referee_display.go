@@ -10,6 +10,7 @@ import (
 	"github.com/Team254/cheesy-arena/field"
 	"github.com/Team254/cheesy-arena/game"
 	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/tournament"
 	"github.com/mitchellh/mapstructure"
 	"io"
 	"log"
@@ -29,7 +30,24 @@ func (web *Web) refereeDisplayHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	rules, err := web.arena.Database.GetAllRules()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
 	match := web.arena.CurrentMatch
+	carriedYellowCards, err := tournament.GetCarriedYellowCards(web.arena.Database, match)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	cardHistory, err := tournament.GetCardHistory(web.arena.Database)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
 	matchType := match.CapitalizedType()
 	red1 := web.arena.AllianceStations["R1"].Team
 	if red1 == nil {
@@ -57,23 +75,41 @@ func (web *Web) refereeDisplayHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	data := struct {
 		*model.EventSettings
-		MatchType        string
-		MatchDisplayName string
-		Red1             *model.Team
-		Red2             *model.Team
-		Red3             *model.Team
-		Blue1            *model.Team
-		Blue2            *model.Team
-		Blue3            *model.Team
-		RedFouls         []game.Foul
-		BlueFouls        []game.Foul
-		RedCards         map[string]string
-		BlueCards        map[string]string
-		Rules            []game.Rule
-		EntryEnabled     bool
+		MatchType         string
+		MatchDisplayName  string
+		Red1              *model.Team
+		Red2              *model.Team
+		Red3              *model.Team
+		Blue1             *model.Team
+		Blue2             *model.Team
+		Blue3             *model.Team
+		RedFouls          []game.Foul
+		BlueFouls         []game.Foul
+		RedCards          map[string]string
+		BlueCards         map[string]string
+		Rules             []model.Rule
+		RedScore          *game.Score
+		BlueScore         *game.Score
+		RedOverrides      []game.ScoreOverride
+		BlueOverrides     []game.ScoreOverride
+		ScoreElements     map[string]string
+		RedScorers        map[string]field.ScorerState
+		BlueScorers       map[string]field.ScorerState
+		RedDisagreements  []string
+		BlueDisagreements []string
+		ScorerElements    map[string]string
+		CarriedCards      map[int]bool
+		CardHistory       map[int][]tournament.TeamCard
+		IsPlayoff         bool
+		EntryEnabled      bool
 	}{web.arena.EventSettings, matchType, match.DisplayName, red1, red2, red3, blue1, blue2, blue3,
 		web.arena.RedRealtimeScore.CurrentScore.Fouls, web.arena.BlueRealtimeScore.CurrentScore.Fouls,
-		web.arena.RedRealtimeScore.Cards, web.arena.BlueRealtimeScore.Cards, game.Rules,
+		web.arena.RedRealtimeScore.Cards, web.arena.BlueRealtimeScore.Cards, rules,
+		&web.arena.RedRealtimeScore.CurrentScore, &web.arena.BlueRealtimeScore.CurrentScore,
+		web.arena.RedRealtimeScore.Overrides, web.arena.BlueRealtimeScore.Overrides, game.OverridableScoreElements,
+		web.arena.RedRealtimeScore.ScorerStates(), web.arena.BlueRealtimeScore.ScorerStates(),
+		web.arena.RedRealtimeScore.Disagreements(), web.arena.BlueRealtimeScore.Disagreements(), field.AllScorerElements(),
+		carriedYellowCards, cardHistory, match.Type == "elimination",
 		!(web.arena.RedRealtimeScore.FoulsCommitted && web.arena.BlueRealtimeScore.FoulsCommitted)}
 	err = template.ExecuteTemplate(w, "referee_display.html", data)
 	if err != nil {
@@ -95,6 +131,8 @@ func (web *Web) refereeDisplayWebsocketHandler(w http.ResponseWriter, r *http.Re
 
 	matchLoadTeamsListener := web.arena.MatchLoadTeamsNotifier.Listen()
 	defer close(matchLoadTeamsListener)
+	scorerDisagreementListener := web.arena.ScorerDisagreementNotifier.Listen()
+	defer close(scorerDisagreementListener)
 	reloadDisplaysListener := web.arena.ReloadDisplaysNotifier.Listen()
 	defer close(reloadDisplaysListener)
 
@@ -110,6 +148,12 @@ func (web *Web) refereeDisplayWebsocketHandler(w http.ResponseWriter, r *http.Re
 				}
 				messageType = "reload"
 				message = nil
+			case _, ok := <-scorerDisagreementListener:
+				if !ok {
+					return
+				}
+				messageType = "reload"
+				message = nil
 			case _, ok := <-reloadDisplaysListener:
 				if !ok {
 					return
@@ -152,8 +196,8 @@ func (web *Web) refereeDisplayWebsocketHandler(w http.ResponseWriter, r *http.Re
 			}
 
 			// Add the foul to the correct alliance's list.
-			foul := game.Foul{Rule: game.Rule{RuleNumber: args.Rule, IsTechnical: args.IsTechnical},
-				TeamId: args.TeamId, TimeInMatchSec: web.arena.MatchTimeSec()}
+			foul := game.NewFoul(game.Rule{RuleNumber: args.Rule, IsTechnical: args.IsTechnical}, args.TeamId,
+				web.arena.MatchTimeSec())
 			if args.Alliance == "red" {
 				web.arena.RedRealtimeScore.CurrentScore.Fouls =
 					append(web.arena.RedRealtimeScore.CurrentScore.Fouls, foul)
@@ -186,7 +230,7 @@ func (web *Web) refereeDisplayWebsocketHandler(w http.ResponseWriter, r *http.Re
 				fouls = &web.arena.BlueRealtimeScore.CurrentScore.Fouls
 			}
 			for i, foul := range *fouls {
-				if foul == deleteFoul {
+				if foul.IsSameCall(&deleteFoul) {
 					*fouls = append((*fouls)[:i], (*fouls)[i+1:]...)
 					break
 				}
@@ -197,6 +241,7 @@ func (web *Web) refereeDisplayWebsocketHandler(w http.ResponseWriter, r *http.Re
 				Alliance string
 				TeamId   int
 				Card     string
+				Reason   string
 			}{}
 			err = mapstructure.Decode(data, &args)
 			if err != nil {
@@ -205,14 +250,108 @@ func (web *Web) refereeDisplayWebsocketHandler(w http.ResponseWriter, r *http.Re
 			}
 
 			// Set the card in the correct alliance's score.
-			var cards map[string]string
+			realtimeScore := web.arena.BlueRealtimeScore
 			if args.Alliance == "red" {
-				cards = web.arena.RedRealtimeScore.Cards
-			} else {
-				cards = web.arena.BlueRealtimeScore.Cards
+				realtimeScore = web.arena.RedRealtimeScore
+			}
+			teamId := strconv.Itoa(args.TeamId)
+			realtimeScore.Cards[teamId] = args.Card
+			realtimeScore.CardReasons[teamId] = args.Reason
+
+			// Upgrade the card if the team or its playoff alliance already has a yellow card.
+			carriedYellowCards, err := tournament.GetCarriedYellowCards(web.arena.Database, web.arena.CurrentMatch)
+			if err != nil {
+				websocket.WriteError(err.Error())
+				continue
+			}
+			escalatedTeams := game.EscalateCards(realtimeScore.Cards, realtimeScore.CardReasons, carriedYellowCards,
+				web.arena.CurrentMatch.Type == "elimination")
+			if len(escalatedTeams) > 0 {
+				// Reload the display to show the upgraded cards.
+				err = websocket.Write("reload", nil)
+				if err != nil {
+					log.Printf("Websocket error: %s", err)
+					return
+				}
 			}
-			cards[strconv.Itoa(args.TeamId)] = args.Card
 			continue
+		case "overrideScore":
+			args := struct {
+				Alliance string
+				Element  string
+				Value    int
+				Reason   string
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				websocket.WriteError(err.Error())
+				continue
+			}
+
+			// Replace the sensor-derived value in the correct alliance's score.
+			realtimeScore := web.arena.BlueRealtimeScore
+			if args.Alliance == "red" {
+				realtimeScore = web.arena.RedRealtimeScore
+			}
+			if realtimeScore.FoulsCommitted {
+				websocket.WriteError("Cannot override score: Match has already been committed.")
+				continue
+			}
+			err = realtimeScore.OverrideScore(args.Element, args.Value, args.Reason, web.arena.MatchTimeSec(),
+				web.arena.MatchState == field.PostMatch)
+			if err != nil {
+				websocket.WriteError(err.Error())
+				continue
+			}
+			web.arena.RealtimeScoreNotifier.Notify(nil)
+			web.arena.ScoreOverridesNotifier.Notify(nil)
+		case "clearOverride":
+			args := struct {
+				Alliance string
+				Element  string
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				websocket.WriteError(err.Error())
+				continue
+			}
+
+			realtimeScore := web.arena.BlueRealtimeScore
+			if args.Alliance == "red" {
+				realtimeScore = web.arena.RedRealtimeScore
+			}
+			if realtimeScore.FoulsCommitted {
+				websocket.WriteError("Cannot clear override: Match has already been committed.")
+				continue
+			}
+			realtimeScore.ClearOverride(args.Element)
+			web.arena.RealtimeScoreNotifier.Notify(nil)
+			web.arena.ScoreOverridesNotifier.Notify(nil)
+		case "resolveDisagreement":
+			args := struct {
+				Alliance string
+				Element  string
+				Value    int
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				websocket.WriteError(err.Error())
+				continue
+			}
+
+			// Apply the head referee's decision to all of the correct alliance's scorers.
+			realtimeScore := web.arena.BlueRealtimeScore
+			if args.Alliance == "red" {
+				realtimeScore = web.arena.RedRealtimeScore
+			}
+			err = realtimeScore.ResolveDisagreement(args.Element, args.Value)
+			if err != nil {
+				websocket.WriteError(err.Error())
+				continue
+			}
+			web.arena.RealtimeScoreNotifier.Notify(nil)
+			web.arena.ScorerDisagreementNotifier.Notify(nil)
+			continue // The disagreement notification will trigger the reload.
 		case "signalReset":
 			if web.arena.MatchState != field.PostMatch {
 				// Don't allow clearing the field until the match is over.
@@ -5,6 +5,7 @@ package web
 
 import (
 	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/model"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"sync"
@@ -18,6 +19,13 @@ func TestRefereeDisplay(t *testing.T) {
 	recorder := web.getHttpResponse("/displays/referee")
 	assert.Equal(t, 200, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "Referee Display - Untitled Event - Cheesy Arena")
+
+	// Check that the rule descriptions from the catalogue are shown.
+	rule, _ := web.arena.Database.GetRuleById(1)
+	rule.Description = "Drive team members must stay in their station."
+	web.arena.Database.SaveRule(rule)
+	recorder = web.getHttpResponse("/displays/referee")
+	assert.Contains(t, recorder.Body.String(), "Drive team members must stay in their station.")
 }
 
 func TestRefereeDisplayWebsocket(t *testing.T) {
@@ -100,6 +108,46 @@ func TestRefereeDisplayWebsocket(t *testing.T) {
 		assert.Equal(t, "red", web.arena.BlueRealtimeScore.Cards["1680"])
 	}
 
+	// Test score overrides.
+	web.arena.BlueRealtimeScore.CurrentScore.FuelHigh = 12
+	overrideData := struct {
+		Alliance string
+		Element  string
+		Value    int
+		Reason   string
+	}{"blue", "FuelHigh", 30, ""}
+	ws.Write("overrideScore", overrideData)
+	readWebsocketType(t, ws, "error")
+	overrideData.Reason = "Boiler sensor jammed"
+	ws.Write("overrideScore", overrideData)
+	readWebsocketType(t, ws, "reload")
+	assert.Equal(t, 30, web.arena.BlueRealtimeScore.CurrentScore.FuelHigh)
+	if assert.Equal(t, 1, len(web.arena.BlueRealtimeScore.Overrides)) {
+		assert.Equal(t, 12, web.arena.BlueRealtimeScore.Overrides[0].OriginalValue)
+		assert.Equal(t, "Boiler sensor jammed", web.arena.BlueRealtimeScore.Overrides[0].Reason)
+	}
+	recorder := web.getHttpResponse("/displays/referee")
+	assert.Contains(t, recorder.Body.String(), "Boiler sensor jammed")
+	ws.Write("clearOverride", overrideData)
+	readWebsocketType(t, ws, "reload")
+	assert.Equal(t, 12, web.arena.BlueRealtimeScore.CurrentScore.FuelHigh)
+	assert.Empty(t, web.arena.BlueRealtimeScore.Overrides)
+
+	// Check that overrides made after the match are marked as such and can't be cleared once it has been committed.
+	web.arena.MatchState = field.PostMatch
+	ws.Write("overrideScore", overrideData)
+	readWebsocketType(t, ws, "reload")
+	if assert.Equal(t, 1, len(web.arena.BlueRealtimeScore.Overrides)) {
+		assert.True(t, web.arena.BlueRealtimeScore.Overrides[0].PostMatch)
+	}
+	web.arena.BlueRealtimeScore.FoulsCommitted = true
+	ws.Write("clearOverride", overrideData)
+	readWebsocketType(t, ws, "error")
+	assert.Equal(t, 1, len(web.arena.BlueRealtimeScore.Overrides))
+	web.arena.BlueRealtimeScore.FoulsCommitted = false
+	ws.Write("clearOverride", overrideData)
+	readWebsocketType(t, ws, "reload")
+
 	// Test field reset and match committing.
 	web.arena.MatchState = field.PostMatch
 	ws.Write("signalReset", nil)
@@ -118,3 +166,40 @@ func TestRefereeDisplayWebsocket(t *testing.T) {
 	web.arena.MatchLoadTeamsNotifier.Notify(nil)
 	readWebsocketType(t, ws, "reload")
 }
+
+func TestRefereeDisplayCardEscalation(t *testing.T) {
+	web := setupTestWeb(t)
+
+	match1 := &model.Match{Type: "qualification", DisplayName: "1", Status: "complete", Red1: 254, Red2: 1114,
+		Red3: 2056, Blue1: 1678, Blue2: 973, Blue3: 148}
+	web.arena.Database.CreateMatch(match1)
+	matchResult := model.NewMatchResult()
+	matchResult.MatchId = match1.Id
+	matchResult.RedCards = map[string]string{"254": "yellow"}
+	matchResult.RedCardReasons = map[string]string{"254": "G20 pinning"}
+	web.arena.Database.CreateMatchResult(matchResult)
+	match2 := &model.Match{Type: "qualification", DisplayName: "2", Red1: 1678, Red2: 973, Red3: 148,
+		Blue1: 254, Blue2: 1114, Blue3: 2056}
+	web.arena.Database.CreateMatch(match2)
+	assert.Nil(t, web.arena.LoadMatch(match2))
+
+	// Check that the card status and history of each team are shown.
+	recorder := web.getHttpResponse("/displays/referee")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Has a yellow card; another will be a red card")
+	assert.Contains(t, recorder.Body.String(), "Q1: yellow")
+	assert.Contains(t, recorder.Body.String(), "(G20 pinning)")
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial(wsUrl+"/displays/referee/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := &Websocket{conn, new(sync.Mutex)}
+
+	ws.Write("card", map[string]interface{}{"Alliance": "blue", "TeamId": 1114, "Card": "yellow", "Reason": "G22"})
+	ws.Write("card", map[string]interface{}{"Alliance": "blue", "TeamId": 254, "Card": "yellow", "Reason": "G25"})
+	readWebsocketType(t, ws, "reload")
+	assert.Equal(t, map[string]string{"1114": "yellow", "254": "red"}, web.arena.BlueRealtimeScore.Cards)
+	assert.Equal(t, "Second yellow card: G25", web.arena.BlueRealtimeScore.CardReasons["254"])
+}
@@ -6,12 +6,15 @@
 package web
 
 import (
+	"bytes"
 	"fmt"
+	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/tournament"
 	"github.com/gorilla/mux"
 	"github.com/jung-kurt/gofpdf"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 // Generates a CSV-formatted report of the qualification rankings.
@@ -238,6 +241,147 @@ func (web *Web) schedulePdfReportHandler(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// Generates a CSV-formatted report of the cycle times of the played matches of the given type, followed by their
+// summaries by block and by hour.
+func (web *Web) cycleTimesCsvReportHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsReader(w, r) {
+		return
+	}
+
+	vars := mux.Vars(r)
+	report, err := web.buildCycleTimeReport(vars["type"])
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// Don't set the content type as "text/csv", as that will trigger an automatic download in the browser.
+	w.Header().Set("Content-Type", "text/plain")
+	var buffer bytes.Buffer
+	buffer.WriteString("Match,Block,ScheduledTime,StartedAt,EndedAt,ScoresCommittedAt,FieldResetAt," +
+		"ScheduledCycleTimeSec,CycleTimeSec,CommitTimeSec,ResetToStartSec\r\n")
+	for _, match := range report.Matches {
+		buffer.WriteString(fmt.Sprintf("%s,%s,%s,%s,%s,%s,%s,%d,%d,%d,%d\r\n", match.DisplayName, match.Block,
+			formatMilestone(match.Time), formatMilestone(match.StartedAt), formatMilestone(match.EndedAt),
+			formatMilestone(match.ScoresCommittedAt), formatMilestone(match.FieldResetAt),
+			match.ScheduledCycleTimeSec, match.CycleTimeSec, match.CommitTimeSec, match.ResetToStartSec))
+	}
+	buffer.WriteString("\r\nGroup,Name,Matches,Cycles,AvgCycleTimeSec,MinCycleTimeSec,MaxCycleTimeSec," +
+		"AvgCommitTimeSec,AvgResetToStartSec\r\n")
+	writeSummary := func(group string, summary *tournament.CycleTimeSummary) {
+		buffer.WriteString(fmt.Sprintf("%s,%s,%d,%d,%d,%d,%d,%d,%d\r\n", group, summary.Name, summary.NumMatches,
+			summary.NumCycles, summary.AvgCycleTimeSec, summary.MinCycleTimeSec, summary.MaxCycleTimeSec,
+			summary.AvgCommitTimeSec, summary.AvgResetToStartSec))
+	}
+	writeSummary("Overall", &report.Overall)
+	for i := range report.ByBlock {
+		writeSummary("Block", &report.ByBlock[i])
+	}
+	for i := range report.ByHour {
+		writeSummary("Hour", &report.ByHour[i])
+	}
+	_, err = w.Write(buffer.Bytes())
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a PDF-formatted report of the cycle times of the played matches of the given type.
+func (web *Web) cycleTimesPdfReportHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsReader(w, r) {
+		return
+	}
+
+	vars := mux.Vars(r)
+	report, err := web.buildCycleTimeReport(vars["type"])
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// The widths of the table columns in mm, stored here so that they can be referenced for each row.
+	colWidths := map[string]float64{"Name": 45, "Matches": 20, "Cycle": 22, "Commit": 30, "Reset": 34, "Match": 20,
+		"Block": 35, "Started": 40, "Time": 25}
+	rowHeight := 6.5
+
+	pdf := gofpdf.New("P", "mm", "Letter", "font")
+	pdf.AddPage()
+
+	// Render the summary table.
+	pdf.SetFont("Arial", "B", 10)
+	pdf.SetFillColor(220, 220, 220)
+	title := fmt.Sprintf("Cycle Times (%s) - %s", vars["type"], web.arena.EventSettings.Name)
+	pdf.CellFormat(195, rowHeight, title, "", 1, "C", false, 0, "")
+	pdf.CellFormat(colWidths["Name"], rowHeight, "", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Matches"], rowHeight, "Matches", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Cycle"], rowHeight, "Avg Cycle", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Cycle"], rowHeight, "Min", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Cycle"], rowHeight, "Max", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Commit"], rowHeight, "Avg Commit", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Reset"], rowHeight, "Avg Reset to Start", "1", 1, "C", true, 0, "")
+	renderSummary := func(summary *tournament.CycleTimeSummary, bold bool) {
+		if bold {
+			pdf.SetFont("Arial", "B", 10)
+		} else {
+			pdf.SetFont("Arial", "", 10)
+		}
+		pdf.CellFormat(colWidths["Name"], rowHeight, summary.Name, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(colWidths["Matches"], rowHeight, strconv.Itoa(summary.NumMatches), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(colWidths["Cycle"], rowHeight, tournament.FormatCycleTime(summary.AvgCycleTimeSec), "1", 0,
+			"C", false, 0, "")
+		pdf.CellFormat(colWidths["Cycle"], rowHeight, tournament.FormatCycleTime(summary.MinCycleTimeSec), "1", 0,
+			"C", false, 0, "")
+		pdf.CellFormat(colWidths["Cycle"], rowHeight, tournament.FormatCycleTime(summary.MaxCycleTimeSec), "1", 0,
+			"C", false, 0, "")
+		pdf.CellFormat(colWidths["Commit"], rowHeight, tournament.FormatCycleTime(summary.AvgCommitTimeSec), "1", 0,
+			"C", false, 0, "")
+		pdf.CellFormat(colWidths["Reset"], rowHeight, tournament.FormatCycleTime(summary.AvgResetToStartSec), "1",
+			1, "C", false, 0, "")
+	}
+	renderSummary(&report.Overall, true)
+	for i := range report.ByBlock {
+		renderSummary(&report.ByBlock[i], false)
+	}
+	for i := range report.ByHour {
+		renderSummary(&report.ByHour[i], false)
+	}
+
+	// Render the per-match table.
+	pdf.Ln(rowHeight)
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(colWidths["Match"], rowHeight, "Match", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Block"], rowHeight, "Block", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Started"], rowHeight, "Started", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Time"], rowHeight, "Sched. Cycle", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Time"], rowHeight, "Cycle", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Time"], rowHeight, "Commit", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Time"], rowHeight, "Reset to Start", "1", 1, "C", true, 0, "")
+	pdf.SetFont("Arial", "", 10)
+	for _, match := range report.Matches {
+		pdf.CellFormat(colWidths["Match"], rowHeight, match.DisplayName, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(colWidths["Block"], rowHeight, match.Block, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(colWidths["Started"], rowHeight, match.StartedAt.Local().Format("Mon 03:04:05 PM"), "1", 0,
+			"C", false, 0, "")
+		pdf.CellFormat(colWidths["Time"], rowHeight, tournament.FormatCycleTime(match.ScheduledCycleTimeSec), "1",
+			0, "C", false, 0, "")
+		pdf.CellFormat(colWidths["Time"], rowHeight, tournament.FormatCycleTime(match.CycleTimeSec), "1", 0, "C",
+			false, 0, "")
+		pdf.CellFormat(colWidths["Time"], rowHeight, tournament.FormatCycleTime(match.CommitTimeSec), "1", 0, "C",
+			false, 0, "")
+		pdf.CellFormat(colWidths["Time"], rowHeight, tournament.FormatCycleTime(match.ResetToStartSec), "1", 1, "C",
+			false, 0, "")
+	}
+
+	// Write out the PDF file as the HTTP response.
+	w.Header().Set("Content-Type", "application/pdf")
+	err = pdf.Output(w)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
 // Generates a CSV-formatted report of the team list.
 func (web *Web) teamsCsvReportHandler(w http.ResponseWriter, r *http.Request) {
 	if !web.userIsReader(w, r) {
@@ -333,6 +477,104 @@ func (web *Web) wpaKeysCsvReportHandler(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// Generates a PDF of cards showing each team's network name and WPA key, to be handed out for radio programming.
+func (web *Web) wpaKeysPdfReportHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	teams, err := web.arena.Database.GetAllTeams()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// The card dimensions in mm, laid out in two columns of five cards per page.
+	cardWidth := 97.5
+	cardHeight := 50.0
+	cardsPerPage := 10
+	margin := 10.0
+
+	pdf := gofpdf.New("P", "mm", "Letter", "font")
+	for i, team := range teams {
+		if i%cardsPerPage == 0 {
+			pdf.AddPage()
+		}
+		x := margin + float64(i%2)*cardWidth
+		y := margin + float64(i%cardsPerPage/2)*cardHeight
+		pdf.SetDrawColor(150, 150, 150)
+		pdf.Rect(x, y, cardWidth, cardHeight, "D")
+
+		pdf.SetXY(x, y+4)
+		pdf.SetFont("Arial", "", 9)
+		pdf.CellFormat(cardWidth, 5, web.arena.EventSettings.Name, "", 2, "C", false, 0, "")
+		pdf.SetFont("Arial", "B", 28)
+		pdf.CellFormat(cardWidth, 14, fmt.Sprintf("Team %d", team.Id), "", 2, "C", false, 0, "")
+		pdf.SetFont("Arial", "", 12)
+		pdf.CellFormat(cardWidth, 7, fmt.Sprintf("Network (SSID): %d", team.Id), "", 2, "C", false, 0, "")
+		pdf.SetFont("Courier", "B", 14)
+		wpaKey := team.WpaKey
+		if wpaKey == "" {
+			wpaKey = "(not set)"
+		}
+		pdf.CellFormat(cardWidth, 8, "WPA Key: "+wpaKey, "", 2, "C", false, 0, "")
+		pdf.SetFont("Arial", "I", 8)
+		pdf.CellFormat(cardWidth, 6, "Program your radio with these settings at the radio kiosk.", "", 2, "C",
+			false, 0, "")
+	}
+
+	// Write out the PDF file as the HTTP response.
+	w.Header().Set("Content-Type", "application/pdf")
+	err = pdf.Output(w)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a CSV-formatted report of the peak and average bandwidth used by each team in each match.
+func (web *Web) connectionCsvReportHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsReader(w, r) {
+		return
+	}
+
+	bandwidthLogs, err := web.arena.Database.GetAllBandwidthLogs()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// Don't set the content type as "text/csv", as that will trigger an automatic download in the browser.
+	w.Header().Set("Content-Type", "text/plain")
+	var buffer bytes.Buffer
+	buffer.WriteString("Match,Station,Team,PeakMbpsToRobot,AverageMbpsToRobot,PeakMbpsFromRobot," +
+		"AverageMbpsFromRobot\r\n")
+	matches := make(map[int]*model.Match)
+	for _, bandwidthLog := range bandwidthLogs {
+		match, ok := matches[bandwidthLog.MatchId]
+		if !ok {
+			match, err = web.arena.Database.GetMatchById(bandwidthLog.MatchId)
+			if err != nil {
+				handleWebErr(w, err)
+				return
+			}
+			matches[bandwidthLog.MatchId] = match
+		}
+		if match == nil {
+			continue
+		}
+		summary := bandwidthLog.Summarize()
+		buffer.WriteString(fmt.Sprintf("%s %s,%s,%d,%.2f,%.2f,%.2f,%.2f\r\n", match.CapitalizedType(),
+			match.DisplayName, bandwidthLog.AllianceStation, bandwidthLog.TeamId, summary.PeakMBpsToRobot,
+			summary.AverageMBpsToRobot, summary.PeakMBpsFromRobot, summary.AverageMBpsFromRobot))
+	}
+	_, err = w.Write(buffer.Bytes())
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
 // Returns the text to display if a team is a surrogate.
 func surrogateText(isSurrogate bool) string {
 	if isSurrogate {
@@ -341,3 +583,11 @@ func surrogateText(isSurrogate bool) string {
 		return ""
 	}
 }
+
+// Returns the given match milestone in local time, or an empty string if it wasn't recorded.
+func formatMilestone(milestone time.Time) string {
+	if milestone.IsZero() {
+		return ""
+	}
+	return milestone.Local().Format("2006-01-02 15:04:05")
+}
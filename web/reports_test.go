@@ -81,6 +81,35 @@ func TestSchedulePdfReport(t *testing.T) {
 	assert.Equal(t, "application/pdf", recorder.HeaderMap["Content-Type"][0])
 }
 
+func TestCycleTimesCsvReport(t *testing.T) {
+	web := setupTestWeb(t)
+
+	createCycleTimeMatches(web)
+	recorder := web.getHttpResponse("/reports/csv/cycle_times/qualification")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "text/plain", recorder.HeaderMap["Content-Type"][0])
+	expectedBody := "Match,Block,ScheduledTime,StartedAt,EndedAt,ScoresCommittedAt,FieldResetAt," +
+		"ScheduledCycleTimeSec,CycleTimeSec,CommitTimeSec,ResetToStartSec\r\n" +
+		"1,Morning,2017-07-13 19:40:00,2017-07-13 19:40:00,2017-07-13 19:42:30,2017-07-13 19:43:15," +
+		"2017-07-13 19:44:00,0,0,45,0\r\n" +
+		"2,Morning,2017-07-13 19:46:00,2017-07-13 19:47:00,,,,360,420,0,180\r\n\r\n" +
+		"Group,Name,Matches,Cycles,AvgCycleTimeSec,MinCycleTimeSec,MaxCycleTimeSec,AvgCommitTimeSec," +
+		"AvgResetToStartSec\r\nOverall,All matches,2,1,420,420,420,45,180\r\n" +
+		"Block,Morning,2,1,420,420,420,45,180\r\nHour,Thu 19:00,2,1,420,420,420,45,180\r\n"
+	assert.Equal(t, expectedBody, recorder.Body.String())
+}
+
+func TestCycleTimesPdfReport(t *testing.T) {
+	web := setupTestWeb(t)
+
+	createCycleTimeMatches(web)
+
+	// Can't really parse the PDF content and check it, so just check that what's sent back is a PDF.
+	recorder := web.getHttpResponse("/reports/pdf/cycle_times/qualification")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/pdf", recorder.HeaderMap["Content-Type"][0])
+}
+
 func TestTeamsCsvReport(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -127,3 +156,39 @@ func TestWpaKeysCsvReport(t *testing.T) {
 	assert.Equal(t, "attachment; filename=wpa_keys.csv", recorder.HeaderMap["Content-Disposition"][0])
 	assert.Equal(t, "254,12345678\r\n1114,9876543210\r\n", recorder.Body.String())
 }
+
+func TestWpaKeysPdfReport(t *testing.T) {
+	web := setupTestWeb(t)
+
+	// Check that an empty report can still be generated.
+	recorder := web.getHttpResponse("/reports/pdf/wpa_keys")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/pdf", recorder.HeaderMap["Content-Type"][0])
+
+	for i := 1; i <= 12; i++ {
+		web.arena.Database.CreateTeam(&model.Team{Id: i, WpaKey: "abcdefgh"})
+	}
+	recorder = web.getHttpResponse("/reports/pdf/wpa_keys")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/pdf", recorder.HeaderMap["Content-Type"][0])
+}
+
+func TestConnectionCsvReport(t *testing.T) {
+	web := setupTestWeb(t)
+
+	web.arena.Database.CreateMatch(&model.Match{Type: "qualification", DisplayName: "1"})
+	web.arena.Database.CreateMatch(&model.Match{Type: "elimination", DisplayName: "F-1"})
+	web.arena.Database.SaveBandwidthLog(&model.BandwidthLog{MatchId: 2, TeamId: 1114, AllianceStation: "B3",
+		Samples: []model.BandwidthSample{{MatchTimeSec: 1, MBpsToRobot: 3, MBpsFromRobot: 1}}})
+	web.arena.Database.SaveBandwidthLog(&model.BandwidthLog{MatchId: 1, TeamId: 254, AllianceStation: "R1",
+		Samples: []model.BandwidthSample{{MatchTimeSec: 1, MBpsToRobot: 2, MBpsFromRobot: 1},
+			{MatchTimeSec: 2, MBpsToRobot: 4, MBpsFromRobot: 0.5}}})
+
+	recorder := web.getHttpResponse("/reports/csv/connection")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "text/plain", recorder.HeaderMap["Content-Type"][0])
+	expectedBody := "Match,Station,Team,PeakMbpsToRobot,AverageMbpsToRobot,PeakMbpsFromRobot," +
+		"AverageMbpsFromRobot\r\nQualification 1,R1,254,4.00,3.00,1.00,0.75\r\n" +
+		"Playoff F-1,B3,1114,3.00,3.00,1.00,1.00\r\n"
+	assert.Equal(t, expectedBody, recorder.Body.String())
+}
@@ -11,9 +11,11 @@ import (
 	"github.com/Team254/cheesy-arena/game"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/gorilla/mux"
+	"github.com/mitchellh/mapstructure"
 	"io"
 	"log"
 	"net/http"
+	"reflect"
 )
 
 // Renders the scoring interface which enables input of scores in real-time.
@@ -29,6 +31,12 @@ func (web *Web) scoringDisplayHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	scorerId, err := getScorerId(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
 	template, err := web.parseFiles("templates/scoring_display.html", "templates/base.html")
 	if err != nil {
 		handleWebErr(w, err)
@@ -36,8 +44,10 @@ func (web *Web) scoringDisplayHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	data := struct {
 		*model.EventSettings
-		Alliance string
-	}{web.arena.EventSettings, alliance}
+		Alliance      string
+		ScorerId      string
+		ScoreElements map[string]string
+	}{web.arena.EventSettings, alliance, scorerId, game.OverridableScoreElements}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
 		handleWebErr(w, err)
@@ -66,6 +76,11 @@ func (web *Web) scoringDisplayWebsocketHandler(w http.ResponseWriter, r *http.Re
 		score = &web.arena.BlueRealtimeScore
 		scoreSummaryFunc = web.arena.BlueScoreSummary
 	}
+	scorerId, err := getScorerId(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
 	autoCommitted := false
 
 	websocket, err := NewWebsocket(w, r)
@@ -75,20 +90,37 @@ func (web *Web) scoringDisplayWebsocketHandler(w http.ResponseWriter, r *http.Re
 	}
 	defer websocket.Close()
 
+	// Register the scorer up front so that their inputs are compared against the others' even if they never change
+	// anything from its initial value.
+	(*score).RegisterScorer(scorerId)
+
 	matchLoadTeamsListener := web.arena.MatchLoadTeamsNotifier.Listen()
 	defer close(matchLoadTeamsListener)
 	matchTimeListener := web.arena.MatchTimeNotifier.Listen()
 	defer close(matchTimeListener)
+	scoreOverridesListener := web.arena.ScoreOverridesNotifier.Listen()
+	defer close(scoreOverridesListener)
+	scorerDisagreementListener := web.arena.ScorerDisagreementNotifier.Listen()
+	defer close(scorerDisagreementListener)
+	manualScoringListener := web.arena.ManualScoringNotifier.Listen()
+	defer close(manualScoringListener)
 	reloadDisplaysListener := web.arena.ReloadDisplaysNotifier.Listen()
 	defer close(reloadDisplaysListener)
 
+	scoreMessage := func() interface{} {
+		return struct {
+			Score                  *field.RealtimeScore
+			ScoreSummary           *game.ScoreSummary
+			AutoCommitted          bool
+			Scorer                 *field.ScorerState
+			Disagreements          []string
+			ManualScoringAvailable bool
+		}{*score, scoreSummaryFunc(), autoCommitted, (*score).ScorerState(scorerId), (*score).Disagreements(),
+			web.arena.ManualScoringAvailable()}
+	}
+
 	// Send the various notifications immediately upon connection.
-	data := struct {
-		Score         *field.RealtimeScore
-		ScoreSummary  *game.ScoreSummary
-		AutoCommitted bool
-	}{*score, scoreSummaryFunc(), autoCommitted}
-	err = websocket.Write("score", data)
+	err = websocket.Write("score", scoreMessage())
 	if err != nil {
 		log.Printf("Websocket error: %s", err)
 		return
@@ -117,6 +149,24 @@ func (web *Web) scoringDisplayWebsocketHandler(w http.ResponseWriter, r *http.Re
 				}
 				messageType = "matchTime"
 				message = MatchTimeMessage{web.arena.MatchState, matchTimeSec.(int)}
+			case _, ok := <-scoreOverridesListener:
+				if !ok {
+					return
+				}
+				messageType = "overrides"
+				message = (*score).Overrides
+			case _, ok := <-scorerDisagreementListener:
+				if !ok {
+					return
+				}
+				messageType = "score"
+				message = scoreMessage()
+			case _, ok := <-manualScoringListener:
+				if !ok {
+					return
+				}
+				messageType = "score"
+				message = scoreMessage()
 			case _, ok := <-reloadDisplaysListener:
 				if !ok {
 					return
@@ -144,19 +194,62 @@ func (web *Web) scoringDisplayWebsocketHandler(w http.ResponseWriter, r *http.Re
 			return
 		}
 
+		disagreements := (*score).Disagreements()
 		switch messageType {
 		case "mobility":
 			if !autoCommitted {
-				if (*score).CurrentScore.AutoMobility < 3 {
-					(*score).CurrentScore.AutoMobility++
-				}
+				(*score).UpdateScorer(scorerId, func(scorer *field.ScorerState) {
+					if scorer.AutoMobility < 3 {
+						scorer.AutoMobility++
+					}
+				})
 			}
 		case "undoMobility":
 			if !autoCommitted {
-				if (*score).CurrentScore.AutoMobility > 0 {
-					(*score).CurrentScore.AutoMobility--
-				}
+				(*score).UpdateScorer(scorerId, func(scorer *field.ScorerState) {
+					if scorer.AutoMobility > 0 {
+						scorer.AutoMobility--
+					}
+				})
+			}
+		case "manualScoring":
+			enabled, ok := data.(bool)
+			if !ok {
+				websocket.WriteError(fmt.Sprintf("Failed to parse '%s' message.", messageType))
+				continue
+			}
+			if enabled && !web.arena.ManualScoringAvailable() {
+				websocket.WriteError("Cannot enable manual scoring while the field sensors are working.")
+				continue
+			}
+			(*score).SetManualScoring(enabled)
+		case "manualScore":
+			args := struct {
+				Element string
+				Delta   int
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				websocket.WriteError(err.Error())
+				continue
+			}
+			if !(*score).ManualScoring {
+				websocket.WriteError("Cannot score manually: Manual scoring is not enabled.")
+				continue
+			}
+			if (*score).TeleopCommitted {
+				websocket.WriteError("Cannot score manually: Score has already been committed.")
+				continue
+			}
+			if _, ok := game.OverridableScoreElements[args.Element]; !ok {
+				websocket.WriteError(fmt.Sprintf("Invalid score element '%s'.", args.Element))
+				continue
 			}
+
+			// Record the count against this scorer so that it is compared with the others' rather than added to theirs.
+			(*score).UpdateScorer(scorerId, func(scorer *field.ScorerState) {
+				scorer.ScoreManually(args.Element, args.Delta)
+			})
 		case "commit":
 			if web.arena.MatchState != field.PreMatch || web.arena.CurrentMatch.Type == "test" {
 				autoCommitted = true
@@ -170,8 +263,14 @@ func (web *Web) scoringDisplayWebsocketHandler(w http.ResponseWriter, r *http.Re
 				continue
 			}
 
+			if err = (*score).CommitScorer(scorerId); err != nil {
+				websocket.WriteError(err.Error())
+				if !reflect.DeepEqual((*score).Disagreements(), disagreements) {
+					web.arena.ScorerDisagreementNotifier.Notify(nil)
+				}
+				continue
+			}
 			autoCommitted = true
-			(*score).TeleopCommitted = true
 			web.arena.ScoringStatusNotifier.Notify(nil)
 		default:
 			websocket.WriteError(fmt.Sprintf("Invalid message type '%s'.", messageType))
@@ -179,17 +278,25 @@ func (web *Web) scoringDisplayWebsocketHandler(w http.ResponseWriter, r *http.Re
 		}
 
 		web.arena.RealtimeScoreNotifier.Notify(nil)
+		if !reflect.DeepEqual((*score).Disagreements(), disagreements) {
+			web.arena.ScorerDisagreementNotifier.Notify(nil)
+		}
 
 		// Send out the score again after handling the command, as it most likely changed as a result.
-		data = struct {
-			Score         *field.RealtimeScore
-			ScoreSummary  *game.ScoreSummary
-			AutoCommitted bool
-		}{*score, scoreSummaryFunc(), autoCommitted}
-		err = websocket.Write("score", data)
+		err = websocket.Write("score", scoreMessage())
 		if err != nil {
 			log.Printf("Websocket error: %s", err)
 			return
 		}
 	}
 }
+
+// Returns the identity of the scorer using the display, for alliances that are scored by more than one person.
+// Every display must identify its scorer, so that two tablets can't unknowingly share the same inputs.
+func getScorerId(r *http.Request) (string, error) {
+	scorerId := r.URL.Query().Get("scorer")
+	if scorerId == "" {
+		return "", fmt.Errorf("Missing scorer ID; the display URL must include a 'scorer' parameter.")
+	}
+	return scorerId, nil
+}
@@ -5,6 +5,7 @@ package web
 
 import (
 	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/game"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"sync"
@@ -17,11 +18,16 @@ func TestScoringDisplay(t *testing.T) {
 	recorder := web.getHttpResponse("/displays/scoring/invalidalliance")
 	assert.Equal(t, 500, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "Invalid alliance")
-	recorder = web.getHttpResponse("/displays/scoring/red")
+	recorder = web.getHttpResponse("/displays/scoring/red?scorer=1")
 	assert.Equal(t, 200, recorder.Code)
-	recorder = web.getHttpResponse("/displays/scoring/blue")
+	recorder = web.getHttpResponse("/displays/scoring/blue?scorer=1")
 	assert.Equal(t, 200, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "Scoring - Untitled Event - Cheesy Arena")
+
+	// Check that a display that doesn't identify its scorer is rejected.
+	recorder = web.getHttpResponse("/displays/scoring/red")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Missing scorer ID")
 }
 
 func TestScoringDisplayWebsocket(t *testing.T) {
@@ -29,13 +35,15 @@ func TestScoringDisplayWebsocket(t *testing.T) {
 
 	server, wsUrl := web.startTestServer()
 	defer server.Close()
-	_, _, err := websocket.DefaultDialer.Dial(wsUrl+"/displays/scoring/blorpy/websocket", nil)
+	_, _, err := websocket.DefaultDialer.Dial(wsUrl+"/displays/scoring/blorpy/websocket?scorer=1", nil)
 	assert.NotNil(t, err)
-	redConn, _, err := websocket.DefaultDialer.Dial(wsUrl+"/displays/scoring/red/websocket", nil)
+	_, _, err = websocket.DefaultDialer.Dial(wsUrl+"/displays/scoring/red/websocket", nil)
+	assert.NotNil(t, err)
+	redConn, _, err := websocket.DefaultDialer.Dial(wsUrl+"/displays/scoring/red/websocket?scorer=1", nil)
 	assert.Nil(t, err)
 	defer redConn.Close()
 	redWs := &Websocket{redConn, new(sync.Mutex)}
-	blueConn, _, err := websocket.DefaultDialer.Dial(wsUrl+"/displays/scoring/blue/websocket", nil)
+	blueConn, _, err := websocket.DefaultDialer.Dial(wsUrl+"/displays/scoring/blue/websocket?scorer=1", nil)
 	assert.Nil(t, err)
 	defer blueConn.Close()
 	blueWs := &Websocket{blueConn, new(sync.Mutex)}
@@ -96,3 +104,132 @@ func TestScoringDisplayWebsocket(t *testing.T) {
 	assert.Equal(t, field.NewRealtimeScore(), web.arena.RedRealtimeScore)
 	assert.Equal(t, field.NewRealtimeScore(), web.arena.BlueRealtimeScore)
 }
+
+func TestScoringDisplayWebsocketMultipleScorers(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn1, _, err := websocket.DefaultDialer.Dial(wsUrl+"/displays/scoring/red/websocket?scorer=1", nil)
+	assert.Nil(t, err)
+	defer conn1.Close()
+	scorer1Ws := &Websocket{conn1, new(sync.Mutex)}
+	conn2, _, err := websocket.DefaultDialer.Dial(wsUrl+"/displays/scoring/red/websocket?scorer=2", nil)
+	assert.Nil(t, err)
+	defer conn2.Close()
+	scorer2Ws := &Websocket{conn2, new(sync.Mutex)}
+	refereeConn, _, err := websocket.DefaultDialer.Dial(wsUrl+"/displays/referee/websocket", nil)
+	assert.Nil(t, err)
+	defer refereeConn.Close()
+	refereeWs := &Websocket{refereeConn, new(sync.Mutex)}
+	readWebsocketType(t, scorer1Ws, "score")
+	readWebsocketType(t, scorer1Ws, "matchTime")
+	readWebsocketType(t, scorer2Ws, "score")
+	readWebsocketType(t, scorer2Ws, "matchTime")
+
+	// The scorers should be tracked independently and flagged whenever they disagree, including with a connected
+	// scorer who hasn't entered anything yet.
+	scorer1Ws.Write("mobility", nil)
+	readWebsocketType(t, scorer1Ws, "score")
+	readWebsocketType(t, scorer1Ws, "score")
+	readWebsocketType(t, scorer2Ws, "score")
+	readWebsocketType(t, refereeWs, "reload")
+	assert.Equal(t, []string{"AutoMobility"}, web.arena.RedRealtimeScore.Disagreements())
+	assert.Equal(t, 0, web.arena.RedRealtimeScore.CurrentScore.AutoMobility)
+	scorer2Ws.Write("mobility", nil)
+	readWebsocketType(t, scorer2Ws, "score")
+	readWebsocketType(t, scorer2Ws, "score")
+	readWebsocketType(t, scorer1Ws, "score")
+	readWebsocketType(t, refereeWs, "reload")
+	assert.Empty(t, web.arena.RedRealtimeScore.Disagreements())
+	assert.Equal(t, 1, web.arena.RedRealtimeScore.CurrentScore.AutoMobility)
+	scorer2Ws.Write("mobility", nil)
+	readWebsocketType(t, scorer2Ws, "score")
+	readWebsocketType(t, scorer2Ws, "score")
+	readWebsocketType(t, scorer1Ws, "score")
+	readWebsocketType(t, refereeWs, "reload")
+	assert.Equal(t, []string{"AutoMobility"}, web.arena.RedRealtimeScore.Disagreements())
+	assert.Equal(t, 1, web.arena.RedRealtimeScore.CurrentScore.AutoMobility)
+	recorder := web.getHttpResponse("/displays/referee")
+	assert.Contains(t, recorder.Body.String(), "Scorer Disagreements")
+	assert.Contains(t, recorder.Body.String(), "Scorer 2: 2")
+
+	// Committing should be blocked until the head referee resolves the disagreement.
+	web.arena.MatchState = field.PostMatch
+	scorer1Ws.Write("commitMatch", nil)
+	readWebsocketType(t, scorer1Ws, "error")
+	assert.False(t, web.arena.RedRealtimeScore.TeleopCommitted)
+	refereeWs.Write("resolveDisagreement", map[string]interface{}{"Alliance": "red", "Element": "AutoMobility",
+		"Value": 2})
+	readWebsocketType(t, refereeWs, "reload")
+	readWebsocketType(t, scorer1Ws, "score")
+	readWebsocketType(t, scorer2Ws, "score")
+	assert.Empty(t, web.arena.RedRealtimeScore.Disagreements())
+	assert.Equal(t, 2, web.arena.RedRealtimeScore.CurrentScore.AutoMobility)
+
+	scorer1Ws.Write("commitMatch", nil)
+	readWebsocketType(t, scorer1Ws, "score")
+	assert.False(t, web.arena.RedRealtimeScore.TeleopCommitted)
+	scorer2Ws.Write("commitMatch", nil)
+	readWebsocketType(t, scorer2Ws, "score")
+	assert.True(t, web.arena.RedRealtimeScore.TeleopCommitted)
+}
+
+func TestScoringDisplayWebsocketManualScoring(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial(wsUrl+"/displays/scoring/blue/websocket?scorer=1", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := &Websocket{conn, new(sync.Mutex)}
+	readWebsocketType(t, ws, "score")
+	readWebsocketType(t, ws, "matchTime")
+
+	ws.Write("manualScore", map[string]interface{}{"Element": "FuelHigh", "Delta": 1})
+	readWebsocketType(t, ws, "error")
+	ws.Write("manualScoring", true)
+	readWebsocketType(t, ws, "score")
+	assert.True(t, web.arena.BlueRealtimeScore.ManualScoring)
+	ws.Write("manualScore", map[string]interface{}{"Element": "FuelHigh", "Delta": 1})
+	ws.Write("manualScore", map[string]interface{}{"Element": "FuelHigh", "Delta": 1})
+	ws.Write("manualScore", map[string]interface{}{"Element": "Rotors", "Delta": 1})
+	ws.Write("manualScore", map[string]interface{}{"Element": "AutoMobility", "Delta": 1})
+	readWebsocketType(t, ws, "score")
+	readWebsocketType(t, ws, "score")
+	readWebsocketType(t, ws, "score")
+	readWebsocketType(t, ws, "error")
+	assert.Equal(t, 2, web.arena.BlueRealtimeScore.CurrentScore.FuelHigh)
+	assert.Equal(t, 1, web.arena.BlueRealtimeScore.CurrentScore.Rotors)
+	assert.Equal(t, game.ManualSource, web.getCurrentMatchResult().BlueScoreSources["FuelHigh"])
+	assert.Equal(t, game.SensorSource, web.getCurrentMatchResult().RedScoreSources["FuelHigh"])
+
+	// A second scorer's counts should be compared with the first's rather than added to them.
+	conn2, _, err := websocket.DefaultDialer.Dial(wsUrl+"/displays/scoring/blue/websocket?scorer=2", nil)
+	assert.Nil(t, err)
+	defer conn2.Close()
+	ws2 := &Websocket{conn2, new(sync.Mutex)}
+	readWebsocketType(t, ws2, "score")
+	readWebsocketType(t, ws2, "matchTime")
+	ws2.Write("manualScore", map[string]interface{}{"Element": "FuelHigh", "Delta": 1})
+	ws2.Write("manualScore", map[string]interface{}{"Element": "FuelHigh", "Delta": 1})
+	ws2.Write("manualScore", map[string]interface{}{"Element": "Rotors", "Delta": 1})
+	for i := 0; i < 5; i++ {
+		readWebsocketType(t, ws2, "score")
+	}
+	readWebsocketType(t, ws, "score")
+	readWebsocketType(t, ws, "score")
+	assert.Empty(t, web.arena.BlueRealtimeScore.Disagreements())
+	assert.Equal(t, 2, web.arena.BlueRealtimeScore.CurrentScore.FuelHigh)
+	assert.Equal(t, 1, web.arena.BlueRealtimeScore.CurrentScore.Rotors)
+
+	// Manual scoring shouldn't be allowed while the field sensors are working.
+	ws.Write("manualScoring", false)
+	readWebsocketType(t, ws, "score")
+	web.arena.EventSettings.PlcAddress = "10.0.100.10"
+	web.arena.Plc.IsHealthy = true
+	ws.Write("manualScoring", true)
+	readWebsocketType(t, ws, "error")
+	assert.False(t, web.arena.BlueRealtimeScore.ManualScoring)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/Team254/cheesy-arena/field"
 	"github.com/Team254/cheesy-arena/model"
 	"net/http"
+	"strconv"
 )
 
 // Shows the field configuration page.
@@ -29,8 +30,9 @@ func (web *Web) fieldGetHandler(w http.ResponseWriter, r *http.Request) {
 		Inputs                  []bool
 		Counters                []uint16
 		Coils                   []bool
+		SensorFilters           []field.SensorFilterStatus
 	}{web.arena.EventSettings, web.arena.AllianceStationDisplays, web.arena.FieldTestMode, web.arena.Plc.Inputs[:],
-		web.arena.Plc.Counters[:], web.arena.Plc.Coils[:]}
+		web.arena.Plc.Counters[:], web.arena.Plc.Coils[:], web.arena.Plc.GetSensorFilterStatuses()}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
 		handleWebErr(w, err)
@@ -119,3 +121,46 @@ func (web *Web) fieldTestPostHandler(w http.ResponseWriter, r *http.Request) {
 	web.arena.FieldTestMode = mode
 	http.Redirect(w, r, "/setup/field", 303)
 }
+
+// Updates the debounce/filter thresholds for a single PLC sensor.
+func (web *Web) fieldSensorFilterPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	sensor := r.PostFormValue("sensor")
+	sensorFilter, err := web.arena.Database.GetSensorFilterBySensor(sensor)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	isNew := sensorFilter == nil
+	if isNew {
+		sensorFilter = &model.SensorFilter{Sensor: sensor}
+	}
+	sensorFilter.MinOnMs, _ = strconv.Atoi(r.PostFormValue("minOnMs"))
+	sensorFilter.GlitchRejectMs, _ = strconv.Atoi(r.PostFormValue("glitchRejectMs"))
+	sensorFilter.MaxCountRate, _ = strconv.Atoi(r.PostFormValue("maxCountRate"))
+	err = field.ValidateSensorFilter(sensorFilter)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	if isNew {
+		err = web.arena.Database.CreateSensorFilter(sensorFilter)
+	} else {
+		err = web.arena.Database.SaveSensorFilter(sensorFilter)
+	}
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	sensorFilters, err := web.arena.Database.GetAllSensorFilters()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	web.arena.Plc.SetSensorFilters(sensorFilters)
+	http.Redirect(w, r, "/setup/field", 303)
+}
@@ -27,3 +27,34 @@ func TestSetupField(t *testing.T) {
 	assert.Equal(t, 303, recorder.Code)
 	assert.Equal(t, "rotor2", web.arena.FieldTestMode)
 }
+
+func TestSetupFieldSensorFilter(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/setup/field")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "blueHighBoilerCount")
+
+	recorder = web.postHttpResponse("/setup/field/sensor_filter",
+		"sensor=redTouchpad2&minOnMs=30&glitchRejectMs=150&maxCountRate=0")
+	assert.Equal(t, 303, recorder.Code)
+	sensorFilter, _ := web.arena.Database.GetSensorFilterBySensor("redTouchpad2")
+	if assert.NotNil(t, sensorFilter) {
+		assert.Equal(t, 30, sensorFilter.MinOnMs)
+		assert.Equal(t, 150, sensorFilter.GlitchRejectMs)
+	}
+	recorder = web.postHttpResponse("/setup/field/sensor_filter",
+		"sensor=redTouchpad2&minOnMs=40&glitchRejectMs=150&maxCountRate=0")
+	assert.Equal(t, 303, recorder.Code)
+	sensorFilter, _ = web.arena.Database.GetSensorFilterBySensor("redTouchpad2")
+	assert.Equal(t, 40, sensorFilter.MinOnMs)
+	recorder = web.getHttpResponse("/setup/field")
+	assert.Contains(t, recorder.Body.String(), "value=\"40\"")
+
+	// Check that invalid sensors and thresholds are rejected.
+	recorder = web.postHttpResponse("/setup/field/sensor_filter", "sensor=fieldEstop&minOnMs=100")
+	assert.Equal(t, 400, recorder.Code)
+	recorder = web.postHttpResponse("/setup/field/sensor_filter", "sensor=redRotor2Count&maxCountRate=-5")
+	assert.Equal(t, 400, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "cannot be negative")
+}
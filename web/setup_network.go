@@ -0,0 +1,101 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for previewing the team network configuration for a match before it is applied.
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/model"
+	"net/http"
+	"strconv"
+)
+
+// Shows the access point and switch configuration that would be applied for the selected match.
+func (web *Web) networkGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	match, preview, err := web.getNetworkPreview(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	var matches []model.Match
+	for _, matchType := range []string{"practice", "qualification", "elimination"} {
+		matchesOfType, err := web.arena.Database.GetMatchesByType(matchType)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		matches = append(matches, matchesOfType...)
+	}
+
+	template, err := web.parseFiles("templates/setup_network.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Match    *model.Match
+		Matches  []model.Match
+		Stations []string
+		Preview  *field.NetworkConfigPreview
+	}{web.arena.EventSettings, match, matches, []string{"R1", "R2", "R3", "B1", "B2", "B3"}, preview}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Downloads the network configuration preview for the selected match as plain text.
+func (web *Web) networkExportHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	match, preview, err := web.getNetworkPreview(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	filename := "network_current.txt"
+	if match != nil {
+		filename = fmt.Sprintf("network_%s_%s.txt", match.Type, match.DisplayName)
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	_, err = w.Write([]byte(preview.ExportText()))
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates the network configuration preview for the match given by the "matchId" query parameter, or for the teams
+// currently in the alliance stations if it is absent or zero.
+func (web *Web) getNetworkPreview(r *http.Request) (*model.Match, *field.NetworkConfigPreview, error) {
+	matchId, _ := strconv.Atoi(r.URL.Query().Get("matchId"))
+	var match *model.Match
+	if matchId != 0 {
+		var err error
+		match, err = web.arena.Database.GetMatchById(matchId)
+		if err != nil {
+			return nil, nil, err
+		}
+		if match == nil {
+			return nil, nil, fmt.Errorf("Invalid match ID %d.", matchId)
+		}
+	}
+	preview, err := web.arena.PreviewNetworkConfig(match)
+	if err != nil {
+		return nil, nil, err
+	}
+	return match, preview, nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetupNetwork(t *testing.T) {
+	web := setupTestWeb(t)
+
+	// Point the switch at a port that will refuse the connection right away.
+	web.arena.EventSettings.SwitchAddress = "127.0.0.1"
+	web.arena.LoadSettings()
+	web.arena.Database.CreateTeam(&model.Team{Id: 254, WpaKey: "aaaaaaaa"})
+	match := model.Match{Type: "qualification", DisplayName: "12", Red2: 254, Blue1: 1114}
+	web.arena.Database.CreateMatch(&match)
+
+	recorder := web.getHttpResponse("/setup/field/network")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "qualification 12")
+	assert.NotContains(t, recorder.Body.String(), "selected")
+
+	recorder = web.getHttpResponse("/setup/field/network?matchId=1")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "selected")
+	assert.Contains(t, recorder.Body.String(), "<b>254</b>")
+	assert.Contains(t, recorder.Body.String(), "Invalid WPA key")
+	assert.Contains(t, recorder.Body.String(), "interface Vlan20")
+
+	recorder = web.getHttpResponse("/setup/field/network?matchId=2")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid match ID 2.")
+
+	recorder = web.getHttpResponse("/setup/field/network/export?matchId=1")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "text/plain", recorder.HeaderMap["Content-Type"][0])
+	assert.Equal(t, "attachment; filename=network_qualification_12.txt",
+		recorder.HeaderMap["Content-Disposition"][0])
+	assert.Contains(t, recorder.Body.String(), "R2: 254 (VLAN 20)\n")
+
+	recorder = web.getHttpResponse("/api/field/network?matchId=1")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/json", recorder.HeaderMap["Content-Type"][0])
+	var preview field.NetworkConfigPreview
+	err := json.Unmarshal([]byte(recorder.Body.String()), &preview)
+	assert.Nil(t, err)
+	assert.Equal(t, 1114, preview.Teams["B1"].Id)
+	assert.Contains(t, preview.SwitchCommands, "interface Vlan40")
+}
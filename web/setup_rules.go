@@ -0,0 +1,136 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for managing the catalogue of penalized rules and the foul point values.
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"net/http"
+	"strconv"
+)
+
+// Shows the rule catalogue configuration page.
+func (web *Web) rulesGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	web.renderRules(w, r, "")
+}
+
+// Saves the new or modified rule to the catalogue, or deletes it.
+func (web *Web) rulesPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	ruleId, _ := strconv.Atoi(r.PostFormValue("id"))
+	rule, err := web.arena.Database.GetRuleById(ruleId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if r.PostFormValue("action") == "delete" {
+		if rule != nil {
+			err = web.arena.Database.DeleteRule(rule)
+		}
+	} else {
+		isNew := rule == nil
+		if isNew {
+			rule = new(model.Rule)
+		}
+		rule.RuleNumber = r.PostFormValue("ruleNumber")
+		rule.IsTechnical = r.PostFormValue("isTechnical") == "on"
+		rule.Description = r.PostFormValue("description")
+		rule.TriggersCard = r.PostFormValue("triggersCard") == "on"
+		rule.AwardsRankingPoint = r.PostFormValue("awardsRankingPoint") == "on"
+		if err = model.ValidateRule(rule); err != nil {
+			web.renderRules(w, r, err.Error())
+			return
+		}
+		var rules []model.Rule
+		rules, err = web.arena.Database.GetAllRules()
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		for _, otherRule := range rules {
+			if otherRule.Id != rule.Id && otherRule.GameRule() == rule.GameRule() {
+				web.renderRules(w, r, "A rule with the same number and severity already exists.")
+				return
+			}
+		}
+		if isNew {
+			err = web.arena.Database.CreateRule(rule)
+		} else {
+			err = web.arena.Database.SaveRule(rule)
+		}
+	}
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	if err = web.arena.LoadRules(); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	http.Redirect(w, r, "/setup/rules", 303)
+}
+
+// Saves the point values of fouls and technical fouls for the event.
+func (web *Web) rulesPointValuesPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	foulPointValue, err := strconv.Atoi(r.PostFormValue("foulPointValue"))
+	if err != nil || foulPointValue < 0 {
+		web.renderRules(w, r, "Foul point value must be a non-negative integer.")
+		return
+	}
+	techFoulPointValue, err := strconv.Atoi(r.PostFormValue("techFoulPointValue"))
+	if err != nil || techFoulPointValue < 0 {
+		web.renderRules(w, r, "Technical foul point value must be a non-negative integer.")
+		return
+	}
+	eventSettings := web.arena.EventSettings
+	eventSettings.FoulPointValue = foulPointValue
+	eventSettings.TechFoulPointValue = techFoulPointValue
+	err = web.arena.Database.SaveEventSettings(eventSettings)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	if err = web.arena.LoadRules(); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	http.Redirect(w, r, "/setup/rules", 303)
+}
+
+func (web *Web) renderRules(w http.ResponseWriter, r *http.Request, errorMessage string) {
+	template, err := web.parseFiles("templates/setup_rules.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	rules, err := web.arena.Database.GetAllRules()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Rules        []model.Rule
+		ErrorMessage string
+	}{web.arena.EventSettings, rules, errorMessage}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
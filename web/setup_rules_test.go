@@ -0,0 +1,75 @@
+// Copyright 2017 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetupRules(t *testing.T) {
+	web := setupTestWeb(t)
+	defer func() {
+		game.FoulPenalties.FoulPointValue = 5
+		game.FoulPenalties.TechFoulPointValue = 25
+		game.FoulPenalties.RankingPointRules = map[game.Rule]bool{}
+	}()
+
+	recorder := web.getHttpResponse("/setup/rules")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "G22")
+	assert.Contains(t, recorder.Body.String(), "value=\"25\"")
+
+	// Add a new rule.
+	recorder = web.postHttpResponse("/setup/rules", "id=0&ruleNumber=G30&isTechnical=on&description=No+climbing"+
+		"&awardsRankingPoint=on&action=save")
+	assert.Equal(t, 303, recorder.Code)
+	rules, _ := web.arena.Database.GetAllRules()
+	assert.Equal(t, 32, len(rules))
+	recorder = web.getHttpResponse("/setup/rules")
+	assert.Contains(t, recorder.Body.String(), "No climbing")
+	assert.True(t, game.FoulPenalties.RankingPointRules[game.Rule{"G30", true}])
+
+	// Check that invalid and duplicate rules are rejected.
+	recorder = web.postHttpResponse("/setup/rules", "id=0&ruleNumber=&action=save")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Rule number must not be blank.")
+	recorder = web.postHttpResponse("/setup/rules", "id=0&ruleNumber=G30&isTechnical=on&action=save")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "already exists")
+
+	// Modify and then delete a rule.
+	rule, _ := web.arena.Database.GetRuleById(1)
+	recorder = web.postHttpResponse("/setup/rules", "id=1&ruleNumber="+rule.RuleNumber+"&description=Safety"+
+		"&triggersCard=on&action=save")
+	assert.Equal(t, 303, recorder.Code)
+	rule, _ = web.arena.Database.GetRuleById(1)
+	assert.Equal(t, "Safety", rule.Description)
+	assert.True(t, rule.TriggersCard)
+	recorder = web.postHttpResponse("/setup/rules", "id=1&action=delete")
+	assert.Equal(t, 303, recorder.Code)
+	rule, _ = web.arena.Database.GetRuleById(1)
+	assert.Nil(t, rule)
+}
+
+func TestSetupRulesPointValues(t *testing.T) {
+	web := setupTestWeb(t)
+	defer func() {
+		game.FoulPenalties.FoulPointValue = 5
+		game.FoulPenalties.TechFoulPointValue = 25
+	}()
+
+	recorder := web.postHttpResponse("/setup/rules/point_values", "foulPointValue=3&techFoulPointValue=15")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, 3, web.arena.EventSettings.FoulPointValue)
+	assert.Equal(t, 15, web.arena.EventSettings.TechFoulPointValue)
+	foul := game.Foul{Rule: game.Rule{"G22", true}}
+	assert.Equal(t, 15, foul.PointValue())
+
+	recorder = web.postHttpResponse("/setup/rules/point_values", "foulPointValue=-1&techFoulPointValue=15")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Foul point value must be a non-negative integer.")
+	assert.Equal(t, 3, web.arena.EventSettings.FoulPointValue)
+}
@@ -16,7 +16,8 @@ import (
 
 // Global vars to hold schedules that are in the process of being generated.
 var cachedMatchType string
-var cachedScheduleBlocks []tournament.ScheduleBlock
+var cachedScheduleSource string
+var cachedScheduleBlocks []model.ScheduleBlock
 var cachedMatches []model.Match
 var cachedTeamFirstMatches map[int]string
 
@@ -26,9 +27,30 @@ func (web *Web) scheduleGetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(cachedScheduleBlocks) == 0 {
+	// Switch to the requested match type, discarding any schedule generated for the other one.
+	if matchType := r.URL.Query().Get("matchType"); matchType == "practice" || matchType == "qualification" {
+		if matchType != cachedMatchType {
+			cachedMatchType = matchType
+			cachedScheduleBlocks = nil
+			cachedMatches = nil
+			cachedTeamFirstMatches = nil
+		}
+	}
+	if cachedMatchType == "" {
 		cachedMatchType = "practice"
 	}
+	if cachedScheduleSource == "" {
+		cachedScheduleSource = "generated"
+	}
+	if len(cachedScheduleBlocks) == 0 {
+		// Pick up the blocks that were last used to schedule this match type.
+		scheduleBlocks, err := web.arena.Database.GetScheduleBlocksByMatchType(cachedMatchType)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		cachedScheduleBlocks = scheduleBlocks
+	}
 	web.renderSchedule(w, r, "")
 }
 
@@ -40,12 +62,18 @@ func (web *Web) scheduleGeneratePostHandler(w http.ResponseWriter, r *http.Reque
 
 	r.ParseForm()
 	cachedMatchType = r.PostFormValue("matchType")
+	cachedScheduleSource = r.PostFormValue("scheduleSource")
 	scheduleBlocks, err := getScheduleBlocks(r)
 	cachedScheduleBlocks = scheduleBlocks // Show the same blocks even if there is an error.
 	if err != nil {
 		web.renderSchedule(w, r, "Incomplete or invalid schedule block parameters specified.")
 		return
 	}
+	err = web.arena.Database.ReplaceScheduleBlocks(cachedMatchType, scheduleBlocks)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
 
 	// Build the schedule.
 	teams, err := web.arena.Database.GetAllTeams()
@@ -58,12 +86,18 @@ func (web *Web) scheduleGeneratePostHandler(w http.ResponseWriter, r *http.Reque
 			"generating the schedule.")
 		return
 	}
-	if len(teams) < 18 {
-		web.renderSchedule(w, r, fmt.Sprintf("There are only %d teams. There must be at least 18 teams to generate "+
-			"a schedule.", len(teams)))
-		return
+	var matches []model.Match
+	if cachedScheduleSource == "template" {
+		// The pre-computed schedule templates only cover events with at least 18 teams.
+		if len(teams) < 18 {
+			web.renderSchedule(w, r, fmt.Sprintf("There are only %d teams. There must be at least 18 teams to "+
+				"use a schedule template.", len(teams)))
+			return
+		}
+		matches, err = tournament.BuildRandomSchedule(teams, scheduleBlocks, cachedMatchType)
+	} else {
+		matches, err = tournament.BuildGeneratedSchedule(teams, scheduleBlocks, cachedMatchType)
 	}
-	matches, err := tournament.BuildRandomSchedule(teams, scheduleBlocks, r.PostFormValue("matchType"))
 	if err != nil {
 		web.renderSchedule(w, r, fmt.Sprintf("Error generating schedule: %s.", err.Error()))
 		return
@@ -91,6 +125,82 @@ func (web *Web) scheduleGeneratePostHandler(w http.ResponseWriter, r *http.Reque
 	http.Redirect(w, r, "/setup/schedule", 303)
 }
 
+// Moves the generated or saved schedule to the times given by the schedule blocks without changing the teams in any
+// of its matches.
+func (web *Web) scheduleRetimePostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	r.ParseForm()
+	cachedMatchType = r.PostFormValue("matchType")
+	scheduleBlocks, err := getScheduleBlocks(r)
+	cachedScheduleBlocks = scheduleBlocks
+	if err != nil {
+		web.renderSchedule(w, r, "Incomplete or invalid schedule block parameters specified.")
+		return
+	}
+
+	savedMatches, err := web.arena.Database.GetMatchesByType(cachedMatchType)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	hasGeneratedMatches := len(cachedMatches) > 0 && cachedMatches[0].Type == cachedMatchType
+	if len(savedMatches) == 0 && !hasGeneratedMatches {
+		web.renderSchedule(w, r, fmt.Sprintf("There is no %s schedule to re-time. Generate one first.",
+			cachedMatchType))
+		return
+	}
+	if err = tournament.UpdateMatchTimes(savedMatches, scheduleBlocks); err != nil {
+		web.renderSchedule(w, r, fmt.Sprintf("Error re-timing schedule: %s.", err.Error()))
+		return
+	}
+	if hasGeneratedMatches {
+		if err = tournament.UpdateMatchTimes(cachedMatches, scheduleBlocks); err != nil {
+			web.renderSchedule(w, r, fmt.Sprintf("Error re-timing schedule: %s.", err.Error()))
+			return
+		}
+	}
+	err = web.arena.Database.ReplaceScheduleBlocks(cachedMatchType, scheduleBlocks)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if len(savedMatches) == 0 {
+		http.Redirect(w, r, "/setup/schedule", 303)
+		return
+	}
+
+	for _, match := range savedMatches {
+		// Matches that have already been played keep the time they were scheduled for.
+		if match.Status == "complete" {
+			continue
+		}
+		err = web.arena.Database.SaveMatch(&match)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+
+	if web.arena.EventSettings.TbaPublishingEnabled && cachedMatchType != "practice" {
+		// Publish the new times to The Blue Alliance.
+		err = web.arena.TbaClient.DeletePublishedMatches()
+		if err != nil {
+			http.Error(w, "Failed to delete published matches: "+err.Error(), 500)
+			return
+		}
+		err = web.arena.TbaClient.PublishMatches(web.arena.Database)
+		if err != nil {
+			http.Error(w, "Failed to publish matches: "+err.Error(), 500)
+			return
+		}
+	}
+
+	http.Redirect(w, r, "/setup/schedule", 303)
+}
+
 // Publishes the schedule in the database to TBA
 func (web *Web) scheduleRepublishPostHandler(w http.ResponseWriter, r *http.Request) {
 	if web.arena.EventSettings.TbaPublishingEnabled {
@@ -168,6 +278,23 @@ func (web *Web) renderSchedule(w http.ResponseWriter, r *http.Request, errorMess
 		handleWebErr(w, err)
 		return
 	}
+
+	// Evaluate the schedule being reviewed, or the saved one if no schedule has been generated yet.
+	evaluatedMatches := cachedMatches
+	evaluatingSavedSchedule := false
+	if len(evaluatedMatches) == 0 && cachedMatchType != "" {
+		evaluatedMatches, err = web.arena.Database.GetMatchesByType(cachedMatchType)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		evaluatingSavedSchedule = true
+	}
+	var evaluation *tournament.ScheduleEvaluation
+	if len(evaluatedMatches) > 0 {
+		evaluation = tournament.EvaluateSchedule(evaluatedMatches)
+	}
+
 	template, err := web.parseFiles("templates/setup_schedule.html", "templates/base.html")
 	if err != nil {
 		handleWebErr(w, err)
@@ -176,13 +303,16 @@ func (web *Web) renderSchedule(w http.ResponseWriter, r *http.Request, errorMess
 	data := struct {
 		*model.EventSettings
 		MatchType        string
-		ScheduleBlocks   []tournament.ScheduleBlock
+		ScheduleSource   string
+		ScheduleBlocks   []model.ScheduleBlock
 		NumTeams         int
 		Matches          []model.Match
 		TeamFirstMatches map[int]string
+		Evaluation       *tournament.ScheduleEvaluation
+		EvaluatingSaved  bool
 		ErrorMessage     string
-	}{web.arena.EventSettings, cachedMatchType, cachedScheduleBlocks, len(teams), cachedMatches, cachedTeamFirstMatches,
-		errorMessage}
+	}{web.arena.EventSettings, cachedMatchType, cachedScheduleSource, cachedScheduleBlocks, len(teams), cachedMatches,
+		cachedTeamFirstMatches, evaluation, evaluatingSavedSchedule, errorMessage}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
 		handleWebErr(w, err)
@@ -191,15 +321,17 @@ func (web *Web) renderSchedule(w http.ResponseWriter, r *http.Request, errorMess
 }
 
 // Converts the post form variables into a slice of schedule blocks.
-func getScheduleBlocks(r *http.Request) ([]tournament.ScheduleBlock, error) {
+func getScheduleBlocks(r *http.Request) ([]model.ScheduleBlock, error) {
 	numScheduleBlocks, err := strconv.Atoi(r.PostFormValue("numScheduleBlocks"))
 	if err != nil {
-		return []tournament.ScheduleBlock{}, err
+		return []model.ScheduleBlock{}, err
 	}
 	var returnErr error
-	scheduleBlocks := make([]tournament.ScheduleBlock, numScheduleBlocks)
+	scheduleBlocks := make([]model.ScheduleBlock, numScheduleBlocks)
 	location, _ := time.LoadLocation("Local")
 	for i := 0; i < numScheduleBlocks; i++ {
+		scheduleBlocks[i].MatchType = r.PostFormValue("matchType")
+		scheduleBlocks[i].Name = r.PostFormValue(fmt.Sprintf("name%d", i))
 		scheduleBlocks[i].StartTime, err = time.ParseInLocation("2006-01-02 03:04:05 PM",
 			r.PostFormValue(fmt.Sprintf("startTime%d", i)), location)
 		if err != nil {
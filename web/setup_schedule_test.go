@@ -7,6 +7,7 @@ import (
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestSetupSchedule(t *testing.T) {
@@ -57,20 +58,27 @@ func TestSetupScheduleErrors(t *testing.T) {
 	assert.Equal(t, 200, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "No team list is configured.")
 
-	// Insufficient number of teams.
+	// Insufficient number of teams for a schedule template.
 	for i := 0; i < 17; i++ {
 		web.arena.Database.CreateTeam(&model.Team{Id: i + 101})
 	}
 	postData = "numScheduleBlocks=1&startTime0=2014-01-01 09:00:00 AM&numMatches0=7&matchSpacingSec0=480&" +
+		"matchType=practice&scheduleSource=template"
+	recorder = web.postHttpResponse("/setup/schedule/generate", postData)
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "There must be at least 18 teams to use a schedule template.")
+
+	// Too few matches for every team to play.
+	postData = "numScheduleBlocks=1&startTime0=2014-01-01 09:00:00 AM&numMatches0=2&matchSpacingSec0=480&" +
 		"matchType=practice"
 	recorder = web.postHttpResponse("/setup/schedule/generate", postData)
 	assert.Equal(t, 200, recorder.Code)
-	assert.Contains(t, recorder.Body.String(), "There must be at least 18 teams to generate a schedule.")
+	assert.Contains(t, recorder.Body.String(), "The schedule blocks don't have room for any matches")
 
 	// More matches per team than schedules exist for.
 	web.arena.Database.CreateTeam(&model.Team{Id: 118})
 	postData = "numScheduleBlocks=1&startTime0=2014-01-01 09:00:00 AM&numMatches0=700&matchSpacingSec0=480&" +
-		"matchType=practice"
+		"matchType=practice&scheduleSource=template"
 	recorder = web.postHttpResponse("/setup/schedule/generate", postData)
 	assert.Equal(t, 200, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "No schedule template exists for 18 teams and 233 matches")
@@ -96,3 +104,142 @@ func TestSetupScheduleErrors(t *testing.T) {
 	assert.Equal(t, 200, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "schedule of 2 practice matches already exists")
 }
+
+func TestSetupScheduleGenerated(t *testing.T) {
+	web := setupTestWeb(t)
+
+	// Check that a schedule can be generated for a team count that has no template.
+	for i := 0; i < 17; i++ {
+		web.arena.Database.CreateTeam(&model.Team{Id: i + 101})
+	}
+	postData := "numScheduleBlocks=1&startTime0=2014-01-01 09:00:00 AM&numMatches0=29&matchSpacingSec0=480&" +
+		"matchType=qualification&scheduleSource=generated"
+	recorder := web.postHttpResponse("/setup/schedule/generate", postData)
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.getHttpResponse("/setup/schedule")
+	assert.Contains(t, recorder.Body.String(), "2014-01-01 12:44:00") // Last match of the block.
+	recorder = web.postHttpResponse("/setup/schedule/save", "")
+	assert.Equal(t, 303, recorder.Code)
+	matches, err := web.arena.Database.GetMatchesByType("qualification")
+	assert.Nil(t, err)
+	assert.Equal(t, 29, len(matches))
+}
+
+func TestSetupScheduleEvaluation(t *testing.T) {
+	web := setupTestWeb(t)
+	cachedScheduleBlocks = nil
+	cachedMatches = nil
+
+	for i := 0; i < 18; i++ {
+		web.arena.Database.CreateTeam(&model.Team{Id: i + 101})
+	}
+	recorder := web.getHttpResponse("/setup/schedule")
+	assert.NotContains(t, recorder.Body.String(), "Schedule Quality")
+
+	// Check that the generated schedule is evaluated before it is saved.
+	postData := "numScheduleBlocks=1&startTime0=2014-01-01 09:00:00 AM&numMatches0=30&matchSpacingSec0=480&" +
+		"matchType=qualification&scheduleSource=generated"
+	recorder = web.postHttpResponse("/setup/schedule/generate", postData)
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.getHttpResponse("/setup/schedule")
+	assert.Contains(t, recorder.Body.String(), "Generated Schedule Quality")
+	assert.Contains(t, recorder.Body.String(), "18 / 30")
+	assert.Contains(t, recorder.Body.String(), "10-10")
+
+	// Check that the saved schedule is evaluated when there is no generated one.
+	web.postHttpResponse("/setup/schedule/save", "")
+	cachedMatches = nil
+	recorder = web.getHttpResponse("/setup/schedule")
+	assert.Contains(t, recorder.Body.String(), "Saved Schedule Quality")
+	assert.Contains(t, recorder.Body.String(), "18 / 30")
+}
+
+func TestSetupScheduleBlocksPersisted(t *testing.T) {
+	web := setupTestWeb(t)
+	cachedMatchType = ""
+	cachedScheduleBlocks = nil
+	cachedMatches = nil
+
+	for i := 0; i < 18; i++ {
+		web.arena.Database.CreateTeam(&model.Team{Id: i + 101})
+	}
+	postData := "numScheduleBlocks=2&name0=Day 1 PM&startTime0=2014-01-01 01:00:00 PM&numMatches0=10&" +
+		"matchSpacingSec0=480&name1=Day 2 AM&startTime1=2014-01-02 09:00:00 AM&numMatches1=20&" +
+		"matchSpacingSec1=480&matchType=qualification"
+	recorder := web.postHttpResponse("/setup/schedule/generate", postData)
+	assert.Equal(t, 303, recorder.Code)
+	scheduleBlocks, err := web.arena.Database.GetScheduleBlocksByMatchType("qualification")
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(scheduleBlocks)) {
+		assert.Equal(t, "Day 1 PM", scheduleBlocks[0].Name)
+		assert.Equal(t, 10, scheduleBlocks[0].NumMatches)
+		assert.Equal(t, "Day 2 AM", scheduleBlocks[1].Name)
+		assert.Equal(t, 20, scheduleBlocks[1].NumMatches)
+	}
+
+	// Check that switching match types shows the blocks saved for each one.
+	recorder = web.getHttpResponse("/setup/schedule?matchType=practice")
+	assert.Contains(t, recorder.Body.String(), "addBlock();")
+	assert.NotContains(t, recorder.Body.String(), "Day 1 PM")
+	recorder = web.getHttpResponse("/setup/schedule?matchType=qualification")
+	assert.Contains(t, recorder.Body.String(), "\"Day 1 PM\"")
+	assert.Contains(t, recorder.Body.String(), "\"Day 2 AM\"")
+}
+
+func TestSetupScheduleRetime(t *testing.T) {
+	web := setupTestWeb(t)
+	cachedMatches = nil
+
+	for i := 0; i < 18; i++ {
+		web.arena.Database.CreateTeam(&model.Team{Id: i + 101})
+	}
+	blockParams := "numScheduleBlocks=1&startTime0=2014-01-01 09:00:00 AM&numMatches0=30&matchSpacingSec0=480&" +
+		"matchType=qualification"
+
+	// Check that there has to be a schedule to re-time.
+	recorder := web.postHttpResponse("/setup/schedule/retime", blockParams)
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "There is no qualification schedule to re-time.")
+
+	// Re-time a generated schedule before it is saved.
+	recorder = web.postHttpResponse("/setup/schedule/generate", blockParams)
+	assert.Equal(t, 303, recorder.Code)
+	originalMatches := append([]model.Match{}, cachedMatches...)
+	retimeParams := "numScheduleBlocks=2&startTime0=2014-01-01 10:00:00 AM&numMatches0=10&matchSpacingSec0=420&" +
+		"startTime1=2014-01-01 01:00:00 PM&numMatches1=20&matchSpacingSec1=420&matchType=qualification"
+	recorder = web.postHttpResponse("/setup/schedule/retime", retimeParams)
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.getHttpResponse("/setup/schedule")
+	assert.Contains(t, recorder.Body.String(), "2014-01-01 11:03:00") // Last match of first block.
+	assert.Contains(t, recorder.Body.String(), "2014-01-01 15:13:00") // Last match of second block.
+	assert.Equal(t, originalMatches[5].Red1, cachedMatches[5].Red1)
+	assert.Equal(t, originalMatches[25].Blue3, cachedMatches[25].Blue3)
+
+	// Re-time the saved schedule, leaving alone the matches that have been played.
+	web.postHttpResponse("/setup/schedule/save", "")
+	matches, _ := web.arena.Database.GetMatchesByType("qualification")
+	matches[0].Status = "complete"
+	web.arena.Database.SaveMatch(&matches[0])
+	retimeParams = "numScheduleBlocks=1&startTime0=2014-01-01 11:00:00 AM&numMatches0=30&matchSpacingSec0=360&" +
+		"matchType=qualification"
+	recorder = web.postHttpResponse("/setup/schedule/retime", retimeParams)
+	assert.Equal(t, 303, recorder.Code)
+	retimedMatches, _ := web.arena.Database.GetMatchesByType("qualification")
+	if assert.Equal(t, 30, len(retimedMatches)) {
+		assert.Equal(t, matches[0].Time.Unix(), retimedMatches[0].Time.Unix())
+		assert.Equal(t, matches[1].Red1, retimedMatches[1].Red1)
+		assert.Equal(t, matches[1].Time.Add(time.Hour-time.Minute).Unix(), retimedMatches[1].Time.Unix())
+		assert.Equal(t, matches[29].Blue2, retimedMatches[29].Blue2)
+	}
+	scheduleBlocks, _ := web.arena.Database.GetScheduleBlocksByMatchType("qualification")
+	if assert.Equal(t, 1, len(scheduleBlocks)) {
+		assert.Equal(t, 360, scheduleBlocks[0].MatchSpacingSec)
+	}
+
+	// Check that the blocks must have room for every match.
+	retimeParams = "numScheduleBlocks=1&startTime0=2014-01-01 11:00:00 AM&numMatches0=20&matchSpacingSec0=360&" +
+		"matchType=qualification"
+	recorder = web.postHttpResponse("/setup/schedule/retime", retimeParams)
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "The schedule blocks only have room for 20 of the 30 matches")
+}
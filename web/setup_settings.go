@@ -7,7 +7,10 @@ package web
 
 import (
 	"fmt"
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/game"
 	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/tournament"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -33,7 +36,8 @@ func (web *Web) settingsPostHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	eventSettings := web.arena.EventSettings
+	// Edit a copy of the settings so that the current ones are left alone if any of the new values are invalid.
+	eventSettings := *web.arena.EventSettings
 	eventSettings.Name = r.PostFormValue("name")
 	match, _ := regexp.MatchString("^#([0-9A-Fa-f]{3}){1,2}$", r.PostFormValue("displayBackgroundColor"))
 	if !match {
@@ -47,9 +51,46 @@ func (web *Web) settingsPostHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	elimType := r.PostFormValue("elimType")
+	if elimType == "" {
+		elimType = "single"
+	}
+	playoffFormat, ok := tournament.PlayoffFormats[elimType]
+	if !ok {
+		web.renderSettings(w, r, "Invalid playoff format.")
+		return
+	}
+	if elimType != eventSettings.ElimType {
+		// The formats share match numbering, so switching mid-playoffs would reassign the teams of existing matches.
+		matches, err := web.arena.Database.GetMatchesByType("elimination")
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if len(matches) > 0 {
+			web.renderSettings(w, r, "Can't change the playoff format once playoff matches have been created.")
+			return
+		}
+	}
+	roundRobinMatchesPerAlliance, err := strconv.Atoi(r.PostFormValue("roundRobinMatchesPerAlliance"))
+	if err != nil {
+		roundRobinMatchesPerAlliance = eventSettings.RoundRobinMatchesPerAlliance
+	}
+
 	eventSettings.NumElimAlliances = numAlliances
+	eventSettings.ElimType = elimType
+	eventSettings.RoundRobinMatchesPerAlliance = roundRobinMatchesPerAlliance
+	if err := playoffFormat.CheckSettings(&eventSettings); err != nil {
+		web.renderSettings(w, r, err.Error()+".")
+		return
+	}
 	eventSettings.SelectionRound2Order = r.PostFormValue("selectionRound2Order")
 	eventSettings.SelectionRound3Order = r.PostFormValue("selectionRound3Order")
+	if _, err := game.ParseElimTiebreakers(r.PostFormValue("elimTiebreakers")); err != nil {
+		web.renderSettings(w, r, err.Error())
+		return
+	}
+	eventSettings.ElimTiebreakers = r.PostFormValue("elimTiebreakers")
 	eventSettings.TBADownloadEnabled = r.PostFormValue("TBADownloadEnabled") == "on"
 	eventSettings.TbaPublishingEnabled = r.PostFormValue("tbaPublishingEnabled") == "on"
 	eventSettings.TbaEventCode = r.PostFormValue("tbaEventCode")
@@ -58,20 +99,53 @@ func (web *Web) settingsPostHandler(w http.ResponseWriter, r *http.Request) {
 	eventSettings.StemTvPublishingEnabled = r.PostFormValue("stemTvPublishingEnabled") == "on"
 	eventSettings.StemTvEventCode = r.PostFormValue("stemTvEventCode")
 	eventSettings.NetworkSecurityEnabled = r.PostFormValue("networkSecurityEnabled") == "on"
+	if _, ok := field.AccessPointTypes[r.PostFormValue("apType")]; !ok {
+		web.renderSettings(w, r, "Invalid access point type.")
+		return
+	}
+	eventSettings.ApType = r.PostFormValue("apType")
 	eventSettings.ApAddress = r.PostFormValue("apAddress")
 	eventSettings.ApUsername = r.PostFormValue("apUsername")
 	eventSettings.ApPassword = r.PostFormValue("apPassword")
 	eventSettings.ApTeamChannel, _ = strconv.Atoi(r.PostFormValue("apTeamChannel"))
 	eventSettings.ApAdminChannel, _ = strconv.Atoi(r.PostFormValue("apAdminChannel"))
 	eventSettings.ApAdminWpaKey = r.PostFormValue("apAdminWpaKey")
+	if _, ok := field.SwitchTypes[r.PostFormValue("switchType")]; !ok {
+		web.renderSettings(w, r, "Invalid switch type.")
+		return
+	}
+	eventSettings.SwitchType = r.PostFormValue("switchType")
+	if r.PostFormValue("switchTransport") != "telnet" && r.PostFormValue("switchTransport") != "ssh" {
+		web.renderSettings(w, r, "Invalid switch transport.")
+		return
+	}
+	eventSettings.SwitchTransport = r.PostFormValue("switchTransport")
 	eventSettings.SwitchAddress = r.PostFormValue("switchAddress")
+	eventSettings.SwitchUsername = r.PostFormValue("switchUsername")
 	eventSettings.SwitchPassword = r.PostFormValue("switchPassword")
+	eventSettings.SwitchDryRun = r.PostFormValue("switchDryRun") == "on"
 	eventSettings.BandwidthMonitoringEnabled = r.PostFormValue("bandwidthMonitoringEnabled") == "on"
+	bandwidthAlertMbps, err := strconv.ParseFloat(r.PostFormValue("bandwidthAlertMbps"), 64)
+	if err != nil || bandwidthAlertMbps < 0 {
+		web.renderSettings(w, r, "Bandwidth alert threshold must be a non-negative number.")
+		return
+	}
+	eventSettings.BandwidthAlertMbps = bandwidthAlertMbps
+	switchPorts := []*int{&eventSettings.Red1SwitchPort, &eventSettings.Red2SwitchPort, &eventSettings.Red3SwitchPort,
+		&eventSettings.Blue1SwitchPort, &eventSettings.Blue2SwitchPort, &eventSettings.Blue3SwitchPort}
+	for i, station := range []string{"red1", "red2", "red3", "blue1", "blue2", "blue3"} {
+		port, _ := strconv.Atoi(r.PostFormValue(station + "SwitchPort"))
+		if port <= 0 {
+			web.renderSettings(w, r, "Switch ports must be positive integers.")
+			return
+		}
+		*switchPorts[i] = port
+	}
 	eventSettings.PlcAddress = r.PostFormValue("plcAddress")
 	eventSettings.AdminPassword = r.PostFormValue("adminPassword")
 	eventSettings.ReaderPassword = r.PostFormValue("readerPassword")
 
-	err := web.arena.Database.SaveEventSettings(eventSettings)
+	err = web.arena.Database.SaveEventSettings(&eventSettings)
 	if err != nil {
 		handleWebErr(w, err)
 		return
@@ -206,6 +280,11 @@ func (web *Web) clearDbHandler(w http.ResponseWriter, r *http.Request) {
 		handleWebErr(w, err)
 		return
 	}
+	err = web.arena.Database.TruncateBandwidthLogs()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
 	http.Redirect(w, r, "/setup/settings", 303)
 }
 
@@ -217,8 +296,13 @@ func (web *Web) renderSettings(w http.ResponseWriter, r *http.Request, errorMess
 	}
 	data := struct {
 		*model.EventSettings
-		ErrorMessage string
-	}{web.arena.EventSettings, errorMessage}
+		AccessPointTypes      map[string]string
+		SwitchTypes           map[string]string
+		PlayoffFormats        map[string]tournament.PlayoffFormat
+		ElimTiebreakerOptions map[string]string
+		ErrorMessage          string
+	}{web.arena.EventSettings, field.AccessPointTypes, field.SwitchTypes, tournament.PlayoffFormats,
+		game.ElimTiebreakers, errorMessage}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
 		handleWebErr(w, err)
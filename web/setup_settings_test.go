@@ -29,7 +29,10 @@ func TestSetupSettings(t *testing.T) {
 
 	// Change the settings and check the response.
 	recorder = web.postHttpResponse("/setup/settings", "name=Chezy Champs&code=CC&displayBackgroundColor=#ff00ff&"+
-		"numElimAlliances=16&tbaPublishingEnabled=on&tbaEventCode=2014cc&tbaSecretId=secretId&tbaSecret=tbasec")
+		"numElimAlliances=16&tbaPublishingEnabled=on&tbaEventCode=2014cc&tbaSecretId=secretId&tbaSecret=tbasec&"+
+		"apType=hostapd&switchType=procurve&switchTransport=ssh&bandwidthAlertMbps=4.5&red1SwitchPort=1&"+
+		"red2SwitchPort=2&red3SwitchPort=3&blue1SwitchPort=4&blue2SwitchPort=5&blue3SwitchPort=24&"+
+		"elimTiebreakers=autoPoints,replay&elimType=single")
 	assert.Equal(t, 303, recorder.Code)
 	recorder = web.getHttpResponse("/setup/settings")
 	assert.Contains(t, recorder.Body.String(), "Chezy Champs")
@@ -39,6 +42,35 @@ func TestSetupSettings(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "2014cc")
 	assert.Contains(t, recorder.Body.String(), "secretId")
 	assert.Contains(t, recorder.Body.String(), "tbasec")
+	assert.Contains(t, recorder.Body.String(), "value=\"hostapd\" selected")
+	assert.Contains(t, recorder.Body.String(), "value=\"procurve\" selected")
+	assert.Equal(t, "ssh", web.arena.EventSettings.SwitchTransport)
+	assert.Equal(t, 4.5, web.arena.EventSettings.BandwidthAlertMbps)
+	assert.Equal(t, 24, web.arena.EventSettings.Blue3SwitchPort)
+	assert.Equal(t, "autoPoints,replay", web.arena.EventSettings.ElimTiebreakers)
+	assert.Equal(t, "single", web.arena.EventSettings.ElimType)
+
+	// Switch to a double-elimination playoff.
+	recorder = web.postHttpResponse("/setup/settings", "numElimAlliances=8&displayBackgroundColor=#000&"+
+		"elimTiebreakers=replay&elimType=double&apType=openwrt&switchType=cisco&switchTransport=ssh&"+
+		"bandwidthAlertMbps=7&red1SwitchPort=1&red2SwitchPort=2&red3SwitchPort=3&blue1SwitchPort=4&"+
+		"blue2SwitchPort=5&blue3SwitchPort=6")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, "double", web.arena.EventSettings.ElimType)
+	recorder = web.getHttpResponse("/setup/settings")
+	assert.Contains(t, recorder.Body.String(), "value=\"double\" selected")
+
+	// Switch to a round-robin playoff.
+	recorder = web.postHttpResponse("/setup/settings", "numElimAlliances=4&displayBackgroundColor=#000&"+
+		"elimTiebreakers=replay&elimType=roundRobin&roundRobinMatchesPerAlliance=3&apType=openwrt&switchType=cisco&"+
+		"switchTransport=ssh&bandwidthAlertMbps=7&red1SwitchPort=1&red2SwitchPort=2&red3SwitchPort=3&"+
+		"blue1SwitchPort=4&blue2SwitchPort=5&blue3SwitchPort=6")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, "roundRobin", web.arena.EventSettings.ElimType)
+	assert.Equal(t, 3, web.arena.EventSettings.RoundRobinMatchesPerAlliance)
+	recorder = web.getHttpResponse("/setup/settings")
+	assert.Contains(t, recorder.Body.String(), "value=\"roundRobin\" selected")
+	assert.Contains(t, recorder.Body.String(), "Round-robin (top 2 to finals)")
 }
 
 func TestSetupSettingsInvalidValues(t *testing.T) {
@@ -47,10 +79,61 @@ func TestSetupSettingsInvalidValues(t *testing.T) {
 	// Invalid color value.
 	recorder := web.postHttpResponse("/setup/settings", "numAlliances=8&displayBackgroundColor=blorpy")
 	assert.Contains(t, recorder.Body.String(), "must be a valid hex color value")
+	recorder = web.postHttpResponse("/setup/settings", "name=Blorpy&numAlliances=1&displayBackgroundColor=#000")
+	assert.Contains(t, recorder.Body.String(), "must be between 2 and 16")
+	assert.NotEqual(t, "Blorpy", web.arena.EventSettings.Name)
 
 	// Invalid number of alliances.
 	recorder = web.postHttpResponse("/setup/settings", "numAlliances=1&displayBackgroundColor=#000")
 	assert.Contains(t, recorder.Body.String(), "must be between 2 and 16")
+
+	// Invalid playoff format.
+	recorder = web.postHttpResponse("/setup/settings", "numElimAlliances=8&displayBackgroundColor=#000&"+
+		"elimType=triple")
+	assert.Contains(t, recorder.Body.String(), "Invalid playoff format")
+	recorder = web.postHttpResponse("/setup/settings", "numElimAlliances=6&displayBackgroundColor=#000&"+
+		"elimType=double")
+	assert.Contains(t, recorder.Body.String(), "Double-elimination playoffs require 8 alliances")
+	recorder = web.postHttpResponse("/setup/settings", "numElimAlliances=5&displayBackgroundColor=#000&"+
+		"elimType=roundRobin&roundRobinMatchesPerAlliance=3")
+	assert.Contains(t, recorder.Body.String(), "Round-robin playoffs require an even number of alliances")
+	recorder = web.postHttpResponse("/setup/settings", "numElimAlliances=4&displayBackgroundColor=#000&"+
+		"elimType=roundRobin&roundRobinMatchesPerAlliance=0")
+	assert.Contains(t, recorder.Body.String(), "Round-robin playoffs require at least 1 match per alliance")
+	assert.Equal(t, 8, web.arena.EventSettings.NumElimAlliances)
+
+	// Changing the playoff format once playoff matches exist.
+	web.arena.Database.CreateMatch(&model.Match{Type: "elimination", DisplayName: "SF1-1"})
+	recorder = web.postHttpResponse("/setup/settings", "numElimAlliances=8&displayBackgroundColor=#000&"+
+		"elimType=double")
+	assert.Contains(t, recorder.Body.String(), "Can't change the playoff format once playoff matches")
+	assert.Equal(t, "single", web.arena.EventSettings.ElimType)
+
+	// Invalid elimination tiebreaker.
+	recorder = web.postHttpResponse("/setup/settings", "numElimAlliances=8&displayBackgroundColor=#000&"+
+		"elimTiebreakers=autoPoints,coinFlip")
+	assert.Contains(t, recorder.Body.String(), "Invalid elimination tiebreaker")
+
+	// Invalid access point type.
+	recorder = web.postHttpResponse("/setup/settings", "numElimAlliances=8&displayBackgroundColor=#000&apType=blorpy")
+	assert.Contains(t, recorder.Body.String(), "Invalid access point type")
+
+	// Invalid switch type and transport.
+	recorder = web.postHttpResponse("/setup/settings", "numElimAlliances=8&displayBackgroundColor=#000&"+
+		"apType=openwrt&switchType=blorpy&switchTransport=ssh")
+	assert.Contains(t, recorder.Body.String(), "Invalid switch type")
+	recorder = web.postHttpResponse("/setup/settings", "numElimAlliances=8&displayBackgroundColor=#000&"+
+		"apType=openwrt&switchType=cisco&switchTransport=blorpy")
+	assert.Contains(t, recorder.Body.String(), "Invalid switch transport")
+
+	// Invalid bandwidth alert threshold and switch ports.
+	recorder = web.postHttpResponse("/setup/settings", "numElimAlliances=8&displayBackgroundColor=#000&"+
+		"apType=openwrt&switchType=cisco&switchTransport=ssh&bandwidthAlertMbps=-1")
+	assert.Contains(t, recorder.Body.String(), "Bandwidth alert threshold must be a non-negative number")
+	recorder = web.postHttpResponse("/setup/settings", "numElimAlliances=8&displayBackgroundColor=#000&"+
+		"apType=openwrt&switchType=cisco&switchTransport=ssh&bandwidthAlertMbps=7&red1SwitchPort=6&"+
+		"red2SwitchPort=blorpy")
+	assert.Contains(t, recorder.Body.String(), "Switch ports must be positive integers")
 }
 
 func TestSetupSettingsClearDb(t *testing.T) {
@@ -7,6 +7,7 @@ package web
 
 import (
 	"bytes"
+	"encoding/csv"
 	"fmt"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/dchest/uniuri"
@@ -18,6 +19,14 @@ import (
 )
 
 const wpaKeyLength = 8
+const modifyTeamListErrorMessage = "You can't modify the team list once the qualification schedule has been " +
+	"generated. If you need to change the team list, clear all other data first on the Settings page."
+
+// A team whose WPA key would cause the team network configuration to fail when its match is loaded.
+type WpaKeyProblem struct {
+	TeamId int
+	Error  string
+}
 
 // Shows the team list.
 func (web *Web) teamsGetHandler(w http.ResponseWriter, r *http.Request) {
@@ -25,7 +34,7 @@ func (web *Web) teamsGetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	web.renderTeams(w, r, false)
+	web.renderTeams(w, r, "")
 }
 
 // Adds teams to the team list.
@@ -35,7 +44,7 @@ func (web *Web) teamsPostHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if !web.canModifyTeamList() {
-		web.renderTeams(w, r, true)
+		web.renderTeams(w, r, modifyTeamListErrorMessage)
 		return
 	}
 
@@ -69,7 +78,7 @@ func (web *Web) teamsClearHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if !web.canModifyTeamList() {
-		web.renderTeams(w, r, true)
+		web.renderTeams(w, r, modifyTeamListErrorMessage)
 		return
 	}
 
@@ -143,11 +152,21 @@ func (web *Web) teamEditPostHandler(w http.ResponseWriter, r *http.Request) {
 	team.Accomplishments = r.PostFormValue("accomplishments")
 	if web.arena.EventSettings.NetworkSecurityEnabled {
 		team.WpaKey = r.PostFormValue("wpaKey")
-		if len(team.WpaKey) < 8 || len(team.WpaKey) > 63 {
-			handleWebErr(w, fmt.Errorf("WPA key must be between 8 and 63 characters."))
+		if err = model.ValidateWpaKey(team.WpaKey); err != nil {
+			handleWebErr(w, err)
 			return
 		}
 	}
+	if web.arena.EventSettings.BandwidthMonitoringEnabled {
+		team.BandwidthLimitMbps = 0
+		if bandwidthLimit := r.PostFormValue("bandwidthLimitMbps"); bandwidthLimit != "" {
+			team.BandwidthLimitMbps, err = strconv.ParseFloat(bandwidthLimit, 64)
+			if err != nil || team.BandwidthLimitMbps < 0 {
+				handleWebErr(w, fmt.Errorf("Bandwidth limit must be a non-negative number."))
+				return
+			}
+		}
+	}
 	err = web.arena.Database.SaveTeam(team)
 	if err != nil {
 		handleWebErr(w, err)
@@ -163,7 +182,7 @@ func (web *Web) teamDeletePostHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if !web.canModifyTeamList() {
-		web.renderTeams(w, r, true)
+		web.renderTeams(w, r, modifyTeamListErrorMessage)
 		return
 	}
 
@@ -226,12 +245,108 @@ func (web *Web) teamsGenerateWpaKeysHandler(w http.ResponseWriter, r *http.Reque
 	http.Redirect(w, r, "/setup/teams", 303)
 }
 
-func (web *Web) renderTeams(w http.ResponseWriter, r *http.Request, showErrorMessage bool) {
+// Generates a new random WPA key for a single team, such as when its existing key has been compromised.
+func (web *Web) teamGenerateWpaKeyHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	vars := mux.Vars(r)
+	teamId, _ := strconv.Atoi(vars["id"])
+	team, err := web.arena.Database.GetTeamById(teamId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if team == nil {
+		http.Error(w, fmt.Sprintf("Error: No such team: %d", teamId), 400)
+		return
+	}
+	team.WpaKey = uniuri.NewLen(wpaKeyLength)
+	err = web.arena.Database.SaveTeam(team)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	http.Redirect(w, r, fmt.Sprintf("/setup/teams/%d/edit", teamId), 303)
+}
+
+// Accepts an uploaded CSV file of team numbers and WPA keys, in the same format as the WPA key report, and saves the
+// keys to the team models. Nothing is saved unless every line is valid.
+func (web *Web) teamsImportWpaKeysHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	file, _, err := r.FormFile("wpaKeysFile")
+	if err != nil {
+		web.renderTeams(w, r, "No WPA key file was specified.")
+		return
+	}
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		web.renderTeams(w, r, fmt.Sprintf("Could not parse the WPA key file: %s", err.Error()))
+		return
+	}
+
+	var teams []*model.Team
+	var problems []string
+	for i, record := range records {
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		teamId, err := strconv.Atoi(strings.TrimSpace(record[0]))
+		if err != nil && i == 0 {
+			// Skip the header row, if there is one.
+			continue
+		}
+		if err != nil || len(record) != 2 {
+			problems = append(problems, fmt.Sprintf("Line %d: expected a team number and a WPA key.", i+1))
+			continue
+		}
+		team, err := web.arena.Database.GetTeamById(teamId)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if team == nil {
+			problems = append(problems, fmt.Sprintf("Line %d: team %d is not in the team list.", i+1, teamId))
+			continue
+		}
+		team.WpaKey = record[1]
+		if err = model.ValidateWpaKey(team.WpaKey); err != nil {
+			problems = append(problems, fmt.Sprintf("Line %d: %s", i+1, err.Error()))
+			continue
+		}
+		teams = append(teams, team)
+	}
+	if len(problems) > 0 {
+		web.renderTeams(w, r, "No WPA keys were imported since the file has errors. "+strings.Join(problems, " "))
+		return
+	}
+
+	for _, team := range teams {
+		err = web.arena.Database.SaveTeam(team)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+	http.Redirect(w, r, "/setup/teams", 303)
+}
+
+func (web *Web) renderTeams(w http.ResponseWriter, r *http.Request, errorMessage string) {
 	teams, err := web.arena.Database.GetAllTeams()
 	if err != nil {
 		handleWebErr(w, err)
 		return
 	}
+	var wpaKeyProblems []WpaKeyProblem
+	if web.arena.EventSettings.NetworkSecurityEnabled {
+		wpaKeyProblems = getWpaKeyProblems(teams)
+	}
 
 	template, err := web.parseFiles("templates/setup_teams.html", "templates/base.html")
 	if err != nil {
@@ -240,9 +355,10 @@ func (web *Web) renderTeams(w http.ResponseWriter, r *http.Request, showErrorMes
 	}
 	data := struct {
 		*model.EventSettings
-		Teams            []model.Team
-		ShowErrorMessage bool
-	}{web.arena.EventSettings, teams, showErrorMessage}
+		Teams          []model.Team
+		WpaKeyProblems []WpaKeyProblem
+		ErrorMessage   string
+	}{web.arena.EventSettings, teams, wpaKeyProblems, errorMessage}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
 		handleWebErr(w, err)
@@ -250,6 +366,18 @@ func (web *Web) renderTeams(w http.ResponseWriter, r *http.Request, showErrorMes
 	}
 }
 
+// Returns the teams whose WPA keys are missing or invalid, so that they can be fixed before the event rather than
+// when the team's first match is loaded.
+func getWpaKeyProblems(teams []model.Team) []WpaKeyProblem {
+	var problems []WpaKeyProblem
+	for _, team := range teams {
+		if err := model.ValidateWpaKey(team.WpaKey); err != nil {
+			problems = append(problems, WpaKeyProblem{team.Id, err.Error()})
+		}
+	}
+	return problems
+}
+
 // Returns true if it is safe to change the team list (i.e. no matches/results exist yet).
 func (web *Web) canModifyTeamList() bool {
 	matches, err := web.arena.Database.GetMatchesByType("qualification")
@@ -4,6 +4,7 @@
 package web
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/stretchr/testify/assert"
@@ -199,6 +200,95 @@ func TestSetupTeamsWpaKeys(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "WPA key must be between 8 and 63 characters")
 }
 
+func TestSetupTeamsBandwidthLimit(t *testing.T) {
+	web := setupTestWeb(t)
+
+	web.arena.EventSettings.BandwidthMonitoringEnabled = true
+	web.arena.Database.CreateTeam(&model.Team{Id: 254})
+
+	recorder := web.getHttpResponse("/setup/teams/254/edit")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "bandwidthLimitMbps")
+	assert.NotContains(t, recorder.Body.String(), "wpaKey")
+
+	// Check setting and clearing a per-team bandwidth limit.
+	recorder = web.postHttpResponse("/setup/teams/254/edit", "bandwidthLimitMbps=3.5")
+	assert.Equal(t, 303, recorder.Code)
+	team, _ := web.arena.Database.GetTeamById(254)
+	assert.Equal(t, 3.5, team.BandwidthLimitMbps)
+	recorder = web.postHttpResponse("/setup/teams/254/edit", "bandwidthLimitMbps=")
+	assert.Equal(t, 303, recorder.Code)
+	team, _ = web.arena.Database.GetTeamById(254)
+	assert.Equal(t, 0.0, team.BandwidthLimitMbps)
+	recorder = web.postHttpResponse("/setup/teams/254/edit", "bandwidthLimitMbps=-2")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Bandwidth limit must be a non-negative number")
+
+	// Check that the limit is left alone while bandwidth monitoring is disabled.
+	web.postHttpResponse("/setup/teams/254/edit", "bandwidthLimitMbps=3.5")
+	web.arena.EventSettings.BandwidthMonitoringEnabled = false
+	recorder = web.postHttpResponse("/setup/teams/254/edit", "bandwidthLimitMbps=")
+	assert.Equal(t, 303, recorder.Code)
+	team, _ = web.arena.Database.GetTeamById(254)
+	assert.Equal(t, 3.5, team.BandwidthLimitMbps)
+}
+
+func TestSetupTeamsWpaKeyLifecycle(t *testing.T) {
+	web := setupTestWeb(t)
+
+	web.arena.EventSettings.NetworkSecurityEnabled = true
+	web.arena.Database.CreateTeam(&model.Team{Id: 254, WpaKey: "aaaaaaaa"})
+	web.arena.Database.CreateTeam(&model.Team{Id: 1114, WpaKey: "short"})
+	web.arena.Database.CreateTeam(&model.Team{Id: 2056})
+
+	// Check that the teams whose keys would fail are listed.
+	recorder := web.getHttpResponse("/setup/teams")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Team 1114</a>: WPA key must be between 8 and 63 characters.")
+	assert.Contains(t, recorder.Body.String(), "Team 2056</a>")
+	assert.NotContains(t, recorder.Body.String(), "Team 254</a>")
+
+	// Check disallowing characters that would break the access point configuration.
+	recorder = web.postHttpResponse("/setup/teams/254/edit", "wpaKey=it's+a+key")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "printable ASCII characters other than single quotes")
+
+	// Check regenerating the key for a single team.
+	recorder = web.postHttpResponse("/setup/teams/254/generate_wpa_key", "")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, "/setup/teams/254/edit", recorder.Header().Get("Location"))
+	team, _ := web.arena.Database.GetTeamById(254)
+	assert.NotEqual(t, "aaaaaaaa", team.WpaKey)
+	assert.Equal(t, 8, len(team.WpaKey))
+	recorder = web.postHttpResponse("/setup/teams/9999/generate_wpa_key", "")
+	assert.Equal(t, 400, recorder.Code)
+
+	// Check that an import with any errors doesn't change anything.
+	recorder = web.postFileHttpResponse("/setup/teams/import_wpa_keys", "wpaKeysFile",
+		bytes.NewBufferString("team,key\r\n254,bbbbbbbb\r\n1114,1234567\r\n9999,cccccccc\r\nfoo\r\n"))
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "No WPA keys were imported")
+	assert.Contains(t, recorder.Body.String(), "Line 3: WPA key must be between 8 and 63 characters.")
+	assert.Contains(t, recorder.Body.String(), "Line 4: team 9999 is not in the team list.")
+	assert.Contains(t, recorder.Body.String(), "Line 5: expected a team number and a WPA key.")
+	team, _ = web.arena.Database.GetTeamById(254)
+	assert.NotEqual(t, "bbbbbbbb", team.WpaKey)
+
+	// Check a successful import.
+	recorder = web.postFileHttpResponse("/setup/teams/import_wpa_keys", "wpaKeysFile",
+		bytes.NewBufferString("254,bbbbbbbb\r\n1114,\"key, with comma\"\r\n2056,dddddddd\r\n"))
+	assert.Equal(t, 303, recorder.Code)
+	team, _ = web.arena.Database.GetTeamById(254)
+	assert.Equal(t, "bbbbbbbb", team.WpaKey)
+	team, _ = web.arena.Database.GetTeamById(1114)
+	assert.Equal(t, "key, with comma", team.WpaKey)
+	recorder = web.getHttpResponse("/setup/teams")
+	assert.NotContains(t, recorder.Body.String(), "WPA keys that would cause")
+
+	recorder = web.postHttpResponse("/setup/teams/import_wpa_keys", "")
+	assert.Contains(t, recorder.Body.String(), "No WPA key file was specified.")
+}
+
 func TestSetupTeamsPublish(t *testing.T) {
 	web := setupTestWeb(t)
 
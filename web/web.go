@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"github.com/Team254/cheesy-arena/field"
 	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/tournament"
 	"github.com/gorilla/mux"
 	"log"
 	"net/http"
@@ -51,6 +52,9 @@ func NewWeb(arena *field.Arena) *Web {
 			}
 			return dict, nil
 		},
+
+		// Formats a duration in seconds as minutes and seconds.
+		"cycleTime": tournament.FormatCycleTime,
 	}
 
 	return web
@@ -136,11 +140,14 @@ func (web *Web) newHandler() http.Handler {
 	router.HandleFunc("/setup/teams/{id}/edit", web.teamEditGetHandler).Methods("GET")
 	router.HandleFunc("/setup/teams/{id}/edit", web.teamEditPostHandler).Methods("POST")
 	router.HandleFunc("/setup/teams/{id}/delete", web.teamDeletePostHandler).Methods("POST")
+	router.HandleFunc("/setup/teams/{id}/generate_wpa_key", web.teamGenerateWpaKeyHandler).Methods("POST")
 	router.HandleFunc("/setup/teams/publish", web.teamsPublishHandler).Methods("POST")
 	router.HandleFunc("/setup/teams/generate_wpa_keys", web.teamsGenerateWpaKeysHandler).Methods("GET")
+	router.HandleFunc("/setup/teams/import_wpa_keys", web.teamsImportWpaKeysHandler).Methods("POST")
 	router.HandleFunc("/setup/schedule", web.scheduleGetHandler).Methods("GET")
 	router.HandleFunc("/setup/schedule/generate", web.scheduleGeneratePostHandler).Methods("POST")
 	router.HandleFunc("/setup/schedule/republish", web.scheduleRepublishPostHandler).Methods("POST")
+	router.HandleFunc("/setup/schedule/retime", web.scheduleRetimePostHandler).Methods("POST")
 	router.HandleFunc("/setup/schedule/save", web.scheduleSavePostHandler).Methods("POST")
 	router.HandleFunc("/setup/alliance_selection", web.allianceSelectionGetHandler).Methods("GET")
 	router.HandleFunc("/setup/alliance_selection", web.allianceSelectionPostHandler).Methods("POST")
@@ -152,14 +159,25 @@ func (web *Web) newHandler() http.Handler {
 	router.HandleFunc("/setup/field", web.fieldPostHandler).Methods("POST")
 	router.HandleFunc("/setup/field/reload_displays", web.fieldReloadDisplaysHandler).Methods("GET")
 	router.HandleFunc("/setup/field/test", web.fieldTestPostHandler).Methods("POST")
+	router.HandleFunc("/setup/field/sensor_filter", web.fieldSensorFilterPostHandler).Methods("POST")
+	router.HandleFunc("/setup/field/network", web.networkGetHandler).Methods("GET")
+	router.HandleFunc("/setup/field/network/export", web.networkExportHandler).Methods("GET")
 	router.HandleFunc("/setup/lower_thirds", web.lowerThirdsGetHandler).Methods("GET")
 	router.HandleFunc("/setup/lower_thirds/websocket", web.lowerThirdsWebsocketHandler).Methods("GET")
+	router.HandleFunc("/setup/rules", web.rulesGetHandler).Methods("GET")
+	router.HandleFunc("/setup/rules", web.rulesPostHandler).Methods("POST")
+	router.HandleFunc("/setup/rules/point_values", web.rulesPointValuesPostHandler).Methods("POST")
 	router.HandleFunc("/setup/sponsor_slides", web.sponsorSlidesGetHandler).Methods("GET")
 	router.HandleFunc("/setup/sponsor_slides", web.sponsorSlidesPostHandler).Methods("POST")
 	router.HandleFunc("/api/matches/{type}", web.matchesApiHandler).Methods("GET")
 	router.HandleFunc("/api/rankings", web.rankingsApiHandler).Methods("GET")
+	router.HandleFunc("/api/schedule/{type}", web.scheduleApiHandler).Methods("GET")
 	router.HandleFunc("/api/sponsor_slides", web.sponsorSlidesApiHandler).Methods("GET")
 	router.HandleFunc("/api/alliances", web.alliancesApiHandler).Methods("GET")
+	router.HandleFunc("/api/round_robin_standings", web.roundRobinStandingsApiHandler).Methods("GET")
+	router.HandleFunc("/api/field_status", web.fieldStatusApiHandler).Methods("GET")
+	router.HandleFunc("/api/field/network", web.networkConfigApiHandler).Methods("GET")
+	router.HandleFunc("/cycle_times", web.cycleTimesHandler).Methods("GET")
 	router.HandleFunc("/match_play", web.matchPlayHandler).Methods("GET")
 	router.HandleFunc("/match_play/{matchId}/load", web.matchPlayLoadHandler).Methods("GET")
 	router.HandleFunc("/match_play/{matchId}/show_result", web.matchPlayShowResultHandler).Methods("GET")
@@ -167,6 +185,10 @@ func (web *Web) newHandler() http.Handler {
 	router.HandleFunc("/match_review", web.matchReviewHandler).Methods("GET")
 	router.HandleFunc("/match_review/{matchId}/edit", web.matchReviewEditGetHandler).Methods("GET")
 	router.HandleFunc("/match_review/{matchId}/edit", web.matchReviewEditPostHandler).Methods("POST")
+	router.HandleFunc("/match_review/{matchId}/logs", web.matchLogsHandler).Methods("GET")
+	router.HandleFunc("/match_review/{matchId}/timeline", web.matchReviewTimelineHandler).Methods("GET")
+	router.HandleFunc("/reports/csv/cycle_times/{type}", web.cycleTimesCsvReportHandler).Methods("GET")
+	router.HandleFunc("/reports/pdf/cycle_times/{type}", web.cycleTimesPdfReportHandler).Methods("GET")
 	router.HandleFunc("/reports/csv/rankings", web.rankingsCsvReportHandler).Methods("GET")
 	router.HandleFunc("/reports/pdf/rankings", web.rankingsPdfReportHandler).Methods("GET")
 	router.HandleFunc("/reports/csv/schedule/{type}", web.scheduleCsvReportHandler).Methods("GET")
@@ -174,10 +196,14 @@ func (web *Web) newHandler() http.Handler {
 	router.HandleFunc("/reports/csv/teams", web.teamsCsvReportHandler).Methods("GET")
 	router.HandleFunc("/reports/pdf/teams", web.teamsPdfReportHandler).Methods("GET")
 	router.HandleFunc("/reports/csv/wpa_keys", web.wpaKeysCsvReportHandler).Methods("GET")
+	router.HandleFunc("/reports/pdf/wpa_keys", web.wpaKeysPdfReportHandler).Methods("GET")
+	router.HandleFunc("/reports/csv/connection", web.connectionCsvReportHandler).Methods("GET")
 	router.HandleFunc("/displays/audience", web.audienceDisplayHandler).Methods("GET")
 	router.HandleFunc("/displays/audience/websocket", web.audienceDisplayWebsocketHandler).Methods("GET")
 	router.HandleFunc("/displays/pit", web.pitDisplayHandler).Methods("GET")
 	router.HandleFunc("/displays/pit/websocket", web.pitDisplayWebsocketHandler).Methods("GET")
+	router.HandleFunc("/displays/queueing", web.queueingDisplayHandler).Methods("GET")
+	router.HandleFunc("/displays/queueing/websocket", web.queueingDisplayWebsocketHandler).Methods("GET")
 	router.HandleFunc("/displays/announcer", web.announcerDisplayHandler).Methods("GET")
 	router.HandleFunc("/displays/announcer/websocket", web.announcerDisplayWebsocketHandler).Methods("GET")
 	router.HandleFunc("/displays/scoring/{alliance}", web.scoringDisplayHandler).Methods("GET")